@@ -9,6 +9,7 @@ import (
 	_ "embed"
 	"fmt"
 	"log/slog"
+	"slices"
 	"sync"
 
 	"github.com/gen2brain/beeep"
@@ -19,6 +20,9 @@ import (
 	"github.com/joshuar/go-hass-agent/config"
 	"github.com/joshuar/go-hass-agent/hass"
 	"github.com/joshuar/go-hass-agent/hass/api"
+	"github.com/joshuar/go-hass-agent/hass/notifications"
+	"github.com/joshuar/go-hass-agent/metrics"
+	"github.com/joshuar/go-hass-agent/models"
 )
 
 //go:embed assets/icon.png
@@ -29,6 +33,11 @@ var registered chan struct{}
 const (
 	// ConfigPrefix is the prefix/heading under which agent preferences are found in the preferences file.
 	ConfigPrefix = "agent"
+
+	// profileBufferSize is the number of entities buffered for each server profile when sending to multiple
+	// servers. It is large enough to absorb the burst of entities sent when the agent starts. If a server falls
+	// further behind, its oldest entities are dropped, rather than holding up the other servers.
+	profileBufferSize = 500
 )
 
 // Agent represents the data and methods required for running the agent.
@@ -114,7 +123,40 @@ func (a *Agent) Run(ctx context.Context) error {
 			if err != nil {
 				return fmt.Errorf("unable to run agent: %w", err)
 			}
+			// Create clients for any additional server profiles.
+			hassClients := append([]*hass.Client{hassClient}, a.profileClients(ctx)...)
 			manager := workers.NewManager()
+			// Set up notification routing. If the notification history cannot be loaded, notifications will still be
+			// displayed, just not recorded.
+			history, err := notifications.Load(config.GetPath())
+			if err != nil {
+				slogctx.FromCtx(ctx).Warn("Unable to load notification history.",
+					slog.Any("error", err))
+			}
+			router := notifications.NewRouter(history, func(record *notifications.Record) error {
+				return beeep.Notify(record.Title, record.Message, icon)
+			})
+			// Expose the agent on the session bus, so that local applications can push sensors and events, list sensor
+			// states, run polling jobs and display notifications.
+			service := newDBusService(ctx, router)
+			// Track the availability of the device for MQTT, which follows the power state of the device.
+			availability := mqtt.NewAvailability()
+			// Sensors of entity workers that use the MQTT sink and events are published over MQTT, if it is enabled.
+			useMQTT := mqttEnabled(ctx)
+			sinks, mqttEntityCh := setupSinks(ctx, manager, useMQTT)
+			// The notification router watches the entities for any do-not-disturb/screen lock changes, the MQTT
+			// availability follows any power state changes and the D-Bus service tracks the current state of all
+			// sensors. They watch the entities of every worker, whichever sinks the worker uses.
+			manager.SetObserver(func(ctx context.Context, entityCh <-chan models.Entity) <-chan models.Entity {
+				observedCh := router.Observe(ctx, availability.Observe(ctx, entityCh))
+				if service != nil {
+					observedCh = service.Observe(ctx, observedCh)
+				}
+				return observedCh
+			})
+			// Sensors bridged from other MQTT topics are generated by an entity worker, using subscriptions made through
+			// the MQTT connection.
+			bridgeWorker := newMQTTBridge(ctx, useMQTT)
 			var wg sync.WaitGroup
 			// Entity/Event workers.
 			wg.Go(func() {
@@ -124,6 +166,14 @@ func (a *Agent) Run(ctx context.Context) error {
 				entityWorkers = append(entityWorkers, CreateDeviceEntityWorkers(ctx, hassClient.RestAPIURL())...)
 				// Add os-based entity workers.
 				entityWorkers = append(entityWorkers, CreateOSEntityWorkers(ctx)...)
+				// Add the MQTT bridge worker.
+				if bridgeWorker != nil {
+					entityWorkers = append(entityWorkers, bridgeWorker)
+				}
+				// Add the D-Bus service, which sends any pushed sensors and events.
+				if service != nil {
+					entityWorkers = append(entityWorkers, service)
+				}
 				// Start all entity workers.
 				entityCh := manager.StartEntityWorkers(ctx, entityWorkers...)
 
@@ -132,88 +182,65 @@ func (a *Agent) Run(ctx context.Context) error {
 					<-ctx.Done()
 				}()
 
-				// Get hass client to handle entity workers.
-				hassClient.EntityHandler(ctx, entityCh)
-			})
-			// MQTT workers.
-			wg.Go(func() {
-				// Don't continue if MQTT isn't configured.
-				if !config.Exists("mqtt") {
-					slogctx.FromCtx(ctx).Debug("Not configuring MQTT functionality, not configured.")
+				// Get hass client(s) to handle entity workers. Every entity is sent to all server profiles.
+				if len(hassClients) == 1 {
+					hassClient.EntityHandler(ctx, entityCh)
 					return
 				}
-				// Get MQTT status.
-				enabled, err := config.Get[bool]("mqtt.enabled")
-				if err != nil {
-					slogctx.FromCtx(ctx).Warn("Unable to start device MQTT workers.",
-						slog.Any("error", err))
-					return
+				// Any entities dropped for a server profile that is falling behind are logged and counted.
+				onDrop := func(idx int, entity models.Entity) {
+					profile := hassClients[idx].Profile().Name
+					metrics.ObserveDropped(profile)
+					slogctx.FromCtx(ctx).Debug("Dropped entity for server profile.",
+						slog.String("profile", profile),
+						slog.Bool("event", entity.IsEvent()))
 				}
-				// Don't continue if MQTT is explicitly disabled.
-				if !enabled {
-					slogctx.FromCtx(ctx).Debug("Not starting MQTT workers, MQTT functionality explicitly disabled.")
-					return
-				}
-				// Create MQTT workers.
-				var mqttWorkers []workers.MQTTWorker
-				// Add device-based MQTT workers.
-				deviceMQTTworkers, err := CreateDeviceMQTTWorkers()
-				if err != nil {
-					slogctx.FromCtx(ctx).Warn("Unable to start device MQTT workers.",
-						slog.Any("error", err))
+				var clientWg sync.WaitGroup
+				for idx, clientCh := range workers.FanOutCh(ctx, entityCh, len(hassClients), profileBufferSize, onDrop) {
+					metrics.RegisterQueue(hassClients[idx].Profile().Name, func() int { return len(clientCh) })
+					clientWg.Go(func() {
+						hassClients[idx].EntityHandler(ctx, clientCh)
+					})
 				}
-				mqttWorkers = append(mqttWorkers, deviceMQTTworkers...)
-				// Add os-based MQTT workers.
-				osMQTTworkers, err := CreateOSMQTTWorkers(ctx)
-				if err != nil {
-					slogctx.FromCtx(ctx).Warn("Unable to start OS MQTT workers.",
-						slog.Any("error", err))
+				clientWg.Wait()
+			})
+			// MQTT workers.
+			wg.Go(func() {
+				if !useMQTT {
+					return
 				}
-				mqttWorkers = append(mqttWorkers, osMQTTworkers)
 				// Start all MQTT workers.
+				mqttWorkers := createMQTTWorkers(ctx)
+				if bridgeWorker != nil {
+					mqttWorkers = append(mqttWorkers, bridgeWorker.Subscriber())
+				}
 				data := manager.StartMQTTWorkers(ctx, mqttWorkers...)
-				if err := mqtt.Start(ctx, data); err != nil {
-					slogctx.FromCtx(ctx).Warn("Unable to start MQTT.",
-						slog.Any("error", err))
+				// Publish the sensors of any entity workers that use the MQTT sink and events.
+				if mqttEntityCh != nil {
+					data.Msgs = workers.MergeCh(ctx, data.Msgs, newEntitySink(ctx, sinks, mqttEntityCh))
 				}
-			})
-			// Run notification worker.
-			wg.Go(func() {
-				beeep.AppName = config.AppName
-				websocket, err := api.NewWebsocket(ctx)
-				if err != nil {
-					slogctx.FromCtx(ctx).Warn("Unable to listen for notifications.",
+				if err := mqtt.Start(ctx, data, availability); err != nil {
+					slogctx.FromCtx(ctx).Warn("Unable to start MQTT.",
 						slog.Any("error", err))
-					return
-				}
-				for {
-					select {
-					case <-ctx.Done():
-						return
-					default:
-						// Connect the websocket.
-						notifyCh, err := websocket.Connect(ctx)
-						if err != nil {
-							slogctx.FromCtx(ctx).Warn("Failed to connect to websocket.",
-								slog.Any("error", err))
-
-							return
-						}
-						// Start listening on the websocket
-						go func() {
-							websocket.Listen()
-						}()
-						// Display any notifications received.
-						for notification := range notifyCh {
-							err := beeep.Notify(notification.Title, notification.Message, icon)
-							if err != nil {
-								slogctx.FromCtx(ctx).Warn("Unable to send notification.",
-									slog.Any("error", err))
+					// Discard any messages, so that workers sending them are not blocked.
+					go func() {
+						for {
+							select {
+							case <-data.Msgs:
+							case <-ctx.Done():
+								return
 							}
 						}
-					}
+					}()
 				}
 			})
+			// Run notification worker(s), one for each server profile.
+			beeep.AppName = config.AppName
+			for client := range slices.Values(hassClients) {
+				wg.Go(func() {
+					listenForNotifications(ctx, client.Profile(), router)
+				})
+			}
 			wg.Wait()
 		case <-ctx.Done():
 			slogctx.FromCtx(ctx).Debug("Stopping agent.")
@@ -221,3 +248,81 @@ func (a *Agent) Run(ctx context.Context) error {
 		}
 	}
 }
+
+// profileClients creates clients for any additional (i.e., non-default) active server profiles.
+func (a *Agent) profileClients(ctx context.Context) []*hass.Client {
+	profiles, err := hass.Profiles()
+	if err != nil {
+		slogctx.FromCtx(ctx).Warn("Problem loading server profiles.",
+			slog.Any("error", err))
+	}
+
+	var clients []*hass.Client
+
+	for profile := range slices.Values(profiles) {
+		if profile.IsDefault() || !profile.IsActive() {
+			continue
+		}
+
+		client, err := hass.NewProfileClient(ctx, a, profile)
+		if err != nil {
+			slogctx.FromCtx(ctx).Warn("Unable to create client for server profile.",
+				slog.String("profile", profile.Name),
+				slog.Any("error", err))
+			continue
+		}
+
+		slogctx.FromCtx(ctx).Debug("Sending entities to server profile.",
+			slog.String("profile", profile.Name))
+
+		clients = append(clients, client)
+	}
+
+	return clients
+}
+
+// listenForNotifications will listen for notifications from the Home Assistant server of the given profile, routing
+// them for display.
+func listenForNotifications(ctx context.Context, profile *hass.Profile, router *notifications.Router) {
+	ctx = slogctx.With(ctx, slog.String("profile", profile.Name))
+
+	websocket, err := api.NewWebsocket(ctx, api.WithConfigPrefix(profile.ConfigPrefix()))
+	if err != nil {
+		slogctx.FromCtx(ctx).Warn("Unable to listen for notifications.",
+			slog.Any("error", err))
+		return
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			// Connect the websocket.
+			notifyCh, err := websocket.Connect(ctx)
+			if err != nil {
+				slogctx.FromCtx(ctx).Warn("Failed to connect to websocket.",
+					slog.Any("error", err))
+
+				return
+			}
+			// Start listening on the websocket
+			go func() {
+				websocket.Listen()
+			}()
+			// Route any notifications received for display.
+			for notification := range notifyCh {
+				record, err := notifications.NewRecord(notification.Title, notification.Message,
+					notification.Data)
+				if err != nil {
+					slogctx.FromCtx(ctx).Warn("Unable to process notification.",
+						slog.Any("error", err))
+					continue
+				}
+				if !profile.IsDefault() {
+					record.Profile = profile.Name
+				}
+				router.Route(ctx, record)
+			}
+		}
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package agent
+
+import (
+	"context"
+	"log/slog"
+
+	slogctx "github.com/veqryn/slog-context"
+
+	"github.com/joshuar/go-hass-agent/hass/notifications"
+	"github.com/joshuar/go-hass-agent/platform/linux/dbusservice"
+)
+
+// newDBusService creates the D-Bus service of the agent, which displays notifications with the given router. If the
+// service cannot be created or has been disabled, nil is returned.
+func newDBusService(ctx context.Context, router *notifications.Router) *dbusservice.Service {
+	service, err := dbusservice.NewService(ctx, router)
+	if err != nil {
+		slogctx.FromCtx(ctx).Warn("Could not set up D-Bus service.",
+			slog.Any("error", err))
+
+		return nil
+	}
+
+	if service.IsDisabled() {
+		return nil
+	}
+
+	return service
+}
@@ -38,6 +38,14 @@ func CreateDeviceEntityWorkers(ctx context.Context, restAPIURL string) []workers
 		deviceWorkers = append(deviceWorkers, w)
 	}
 
+	// Initialize and add Prometheus exporter sensor w.
+	if w, err := workers.NewPrometheusWorker(ctx); err != nil {
+		slogctx.FromCtx(ctx).Warn("Could not init agent worker.",
+			slog.Any("error", err))
+	} else {
+		deviceWorkers = append(deviceWorkers, w)
+	}
+
 	// Initialize and add scripts w.
 	if w, err := workers.NewScriptsWorker(ctx); err != nil {
 		slogctx.FromCtx(ctx).Warn("Could not init agent worker.",
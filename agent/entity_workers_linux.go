@@ -34,6 +34,7 @@ var linuxWorkers = []func(ctx context.Context) (workers.EntityWorker, error){
 	cpu.NewFreqWorker,
 	desktop.NewAppStateWorker,
 	desktop.NewDesktopWorker,
+	desktop.NewDNDWorker,
 	location.NewLocationWorker,
 	media.NewMicUsageWorker,
 	media.NewWebcamUsageWorker,
@@ -46,6 +47,7 @@ var linuxWorkers = []func(ctx context.Context) (workers.EntityWorker, error){
 	power.NewStateWorker,
 	power.NewScreenLockWorker,
 	system.NewChronyWorker,
+	system.NewDBusSensorsWorker,
 	system.NewfwupdWorker,
 	system.NewHWMonWorker,
 	system.NewInfoWorker,
@@ -4,15 +4,25 @@
 package agent
 
 import (
-	"errors"
+	"context"
 	"fmt"
-	"os"
+	"log/slog"
+
+	slogctx "github.com/veqryn/slog-context"
 
 	"github.com/joshuar/go-hass-agent/agent/workers"
 	"github.com/joshuar/go-hass-agent/agent/workers/mqtt"
+	"github.com/joshuar/go-hass-agent/agent/workers/mqtt/bridge"
 	"github.com/joshuar/go-hass-agent/agent/workers/mqtt/commands"
+	"github.com/joshuar/go-hass-agent/config"
+	"github.com/joshuar/go-hass-agent/models"
 )
 
+// mqttSinkBufferSize is the number of sensors/events buffered for publishing over MQTT. It is large enough to absorb
+// the burst of entities sent when the agent starts, before the MQTT connection is established. If the connection is
+// unavailable for longer, entities are not published over MQTT, rather than holding up the workers sending them.
+const mqttSinkBufferSize = 500
+
 // CreateDeviceMQTTWorkers sets up the device-specific MQTT workers.
 func CreateDeviceMQTTWorkers() ([]workers.MQTTWorker, error) {
 	var mqttWorkers []workers.MQTTWorker
@@ -23,12 +33,140 @@ func CreateDeviceMQTTWorkers() ([]workers.MQTTWorker, error) {
 	}
 	customCommandsWorker, err := commands.NewCommandsWorker(device)
 	if err != nil {
-		if !errors.Is(err, os.ErrNotExist) {
-			return nil, fmt.Errorf("create mqtt custom commands worker: %w", err)
-		}
-	} else {
-		mqttWorkers = append(mqttWorkers, customCommandsWorker)
+		return nil, fmt.Errorf("create mqtt custom commands worker: %w", err)
 	}
+	mqttWorkers = append(mqttWorkers, customCommandsWorker)
 
 	return mqttWorkers, nil
 }
+
+// createMQTTWorkers creates all device and OS MQTT workers. Any workers that cannot be created are logged and skipped.
+func createMQTTWorkers(ctx context.Context) []workers.MQTTWorker {
+	var mqttWorkers []workers.MQTTWorker
+	// Add device-based MQTT workers.
+	deviceMQTTworkers, err := CreateDeviceMQTTWorkers()
+	if err != nil {
+		slogctx.FromCtx(ctx).Warn("Unable to start device MQTT workers.",
+			slog.Any("error", err))
+	}
+	mqttWorkers = append(mqttWorkers, deviceMQTTworkers...)
+	// Add os-based MQTT workers.
+	osMQTTworkers, err := CreateOSMQTTWorkers(ctx)
+	if err != nil {
+		slogctx.FromCtx(ctx).Warn("Unable to start OS MQTT workers.",
+			slog.Any("error", err))
+	} else {
+		mqttWorkers = append(mqttWorkers, osMQTTworkers)
+	}
+
+	return mqttWorkers
+}
+
+// mqttEnabled returns whether MQTT has been configured and enabled.
+func mqttEnabled(ctx context.Context) bool {
+	// Don't continue if MQTT isn't configured.
+	if !config.Exists(mqtt.ConfigPrefix) {
+		slogctx.FromCtx(ctx).Debug("Not configuring MQTT functionality, not configured.")
+		return false
+	}
+	// Get MQTT status.
+	enabled, err := config.Get[bool](mqtt.ConfigPrefix + ".enabled")
+	if err != nil {
+		slogctx.FromCtx(ctx).Warn("Unable to start device MQTT workers.",
+			slog.Any("error", err))
+		return false
+	}
+	// Don't continue if MQTT is explicitly disabled.
+	if !enabled {
+		slogctx.FromCtx(ctx).Debug("Not starting MQTT workers, MQTT functionality explicitly disabled.")
+		return false
+	}
+	return true
+}
+
+// setupSinks loads the sink preferences of entity workers and configures the manager to use them. If any workers use
+// the MQTT sink or events are published over MQTT, the sink preferences and a channel on which the sensors/events
+// will be sent are returned. If MQTT is not enabled, all workers use the webhook sink.
+func setupSinks(ctx context.Context, manager *workers.Manager, useMQTT bool) (*workers.Sinks, chan models.Entity) {
+	sinks, err := workers.LoadSinks()
+	if err != nil {
+		slogctx.FromCtx(ctx).Warn("Unable to load sink preferences, using webhook for all workers.",
+			slog.Any("error", err))
+	}
+	if !sinks.UsesMQTT() {
+		return nil, nil
+	}
+	if !useMQTT {
+		if sinks.Default != workers.SinkWebhook || len(sinks.Workers) > 0 {
+			slogctx.FromCtx(ctx).Warn("Some workers are configured to publish sensors over MQTT, but MQTT is not enabled. Using webhook instead.")
+		}
+		return nil, nil
+	}
+	mqttEntityCh := make(chan models.Entity, mqttSinkBufferSize)
+	manager.SetSinks(sinks, mqttEntityCh)
+	return sinks, mqttEntityCh
+}
+
+// newMQTTBridge creates the worker that bridges messages on MQTT topics into sensors. As it needs the MQTT connection,
+// it is only created if MQTT is enabled. If the worker cannot be created or has no sensors to bridge, nil is returned.
+func newMQTTBridge(ctx context.Context, useMQTT bool) *bridge.Worker {
+	if !useMQTT {
+		return nil
+	}
+	worker, err := bridge.NewWorker(ctx)
+	if err != nil {
+		slogctx.FromCtx(ctx).Warn("Could not init MQTT bridge worker.",
+			slog.Any("error", err))
+		return nil
+	}
+	if worker.IsDisabled() {
+		return nil
+	}
+	return worker
+}
+
+// newEntitySink starts publishing the sensors and events sent on the given channel over MQTT, returning the channel of
+// messages to publish. If the MQTT device cannot be created, the sensors and events are discarded.
+func newEntitySink(ctx context.Context, sinks *workers.Sinks, entityCh <-chan models.Entity) <-chan models.MQTTMsg {
+	device, err := mqtt.Device()
+	if err != nil {
+		slogctx.FromCtx(ctx).Warn("Unable to publish sensors over MQTT.",
+			slog.Any("error", err))
+		go func() {
+			for {
+				select {
+				case <-entityCh:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+		return nil
+	}
+	return mqtt.NewEntitySink(device, sinks.Events).Run(ctx, entityCh)
+}
+
+// ResetMQTT will remove all entities the agent has published via MQTT from Home Assistant. If MQTT is not configured
+// or enabled, it does nothing.
+func ResetMQTT(ctx context.Context) error {
+	if !config.Exists(mqtt.ConfigPrefix) {
+		return nil
+	}
+	if enabled, err := config.Get[bool](mqtt.ConfigPrefix + ".enabled"); err != nil || !enabled {
+		return nil //nolint:nilerr // if the status cannot be determined, MQTT is not in use.
+	}
+	// The workers only need to run long enough to gather their configs. Like when the agent runs, they need the
+	// platform-specific context (e.g., D-Bus connections) to be created.
+	workerCtx, cancelFunc := context.WithCancel(workers.SetupCtx(ctx))
+	defer cancelFunc()
+
+	manager := workers.NewManager()
+	data := manager.StartMQTTWorkers(workerCtx, createMQTTWorkers(workerCtx)...)
+	defer manager.StopAllWorkers()
+
+	if err := mqtt.Reset(ctx, data.Configs); err != nil {
+		return fmt.Errorf("reset mqtt: %w", err)
+	}
+
+	return nil
+}
@@ -231,12 +231,13 @@ func CreateOSMQTTWorkers(ctx context.Context) (workers.MQTTWorker, error) {
 	}
 
 	// Add the D-Bus command action.
-	dbusCmdController, err := system.NewDBusCommandSubscription(ctx, mqttDevice)
+	dbusCmdWorker, err := system.NewDBusCommandWorker(ctx, mqttDevice)
 	if err != nil {
 		slogctx.FromCtx(ctx).Warn("Could not create MQTT D-Bus command controller.",
 			slog.Any("error", err))
-	} else if dbusCmdController != nil {
-		mqttController.controls = append(mqttController.controls, dbusCmdController)
+	} else if dbusCmdWorker != nil {
+		mqttController.controls = append(mqttController.controls, dbusCmdWorker.Subscription)
+		workerMsgs = append(workerMsgs, dbusCmdWorker.MsgCh)
 	}
 
 	mqttController.msgs = workers.MergeCh(ctx, workerMsgs...)
@@ -0,0 +1,101 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package mqtt
+
+import (
+	"context"
+
+	"github.com/joshuar/go-hass-agent/models"
+)
+
+const (
+	// powerStateSensorID is the id of the sensor reporting the power state of
+	// the device.
+	powerStateSensorID = "power_state"
+	powerStateOn       = "Powered On"
+	powerStateSuspend  = "Suspended"
+	powerStateOff      = "Powered Off"
+)
+
+// Availability tracks the availability status of the device, as reported to
+// Home Assistant on the availability topic. Only the latest status is kept,
+// so that updating the status never blocks, even when MQTT is not running.
+type Availability struct {
+	statusCh chan string
+}
+
+// NewAvailability creates a new availability tracker.
+func NewAvailability() *Availability {
+	return &Availability{
+		statusCh: make(chan string, 1),
+	}
+}
+
+// Set will set the availability status of the device to the given status
+// (either StatusOnline or StatusOffline).
+func (a *Availability) Set(status string) {
+	for {
+		select {
+		case a.statusCh <- status:
+			return
+		default:
+			// Replace any status not yet published.
+			select {
+			case <-a.statusCh:
+			default:
+			}
+		}
+	}
+}
+
+// Updates returns a channel on which changes to the availability status are
+// sent.
+func (a *Availability) Updates() <-chan string {
+	return a.statusCh
+}
+
+// Observe will watch the given entity channel for changes to the power state
+// of the device, marking the device offline when it is suspended or powered
+// off and online when it is powered on again. All entities are passed
+// through, unchanged, to the returned channel.
+func (a *Availability) Observe(ctx context.Context, entityCh <-chan models.Entity) <-chan models.Entity {
+	outCh := make(chan models.Entity)
+
+	go func() {
+		defer close(outCh)
+
+		for entity := range entityCh {
+			if sensor, err := entity.AsSensor(); err == nil && sensor.UniqueID == powerStateSensorID {
+				if status, ok := powerStateStatus(sensor.State); ok {
+					a.Set(status)
+				}
+			}
+
+			select {
+			case outCh <- entity:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return outCh
+}
+
+// powerStateStatus returns the availability status for the given power state.
+func powerStateStatus(state any) (string, bool) {
+	value, ok := state.(string)
+	if !ok {
+		return "", false
+	}
+
+	switch value {
+	case powerStateOn:
+		return StatusOnline, true
+	case powerStateSuspend, powerStateOff:
+		return StatusOffline, true
+	default:
+		return "", false
+	}
+}
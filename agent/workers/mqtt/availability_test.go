@@ -0,0 +1,66 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package mqtt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshuar/go-hass-agent/models"
+)
+
+func TestAvailability_Set(t *testing.T) {
+	availability := NewAvailability()
+
+	// Setting the status should not block and only the latest status is kept.
+	availability.Set(StatusOffline)
+	availability.Set(StatusOnline)
+
+	assert.Equal(t, StatusOnline, <-availability.Updates())
+	assert.Empty(t, availability.Updates())
+}
+
+func TestAvailability_Observe(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		state      string
+		wantStatus string
+	}{
+		{name: "suspended", id: powerStateSensorID, state: powerStateSuspend, wantStatus: StatusOffline},
+		{name: "powered off", id: powerStateSensorID, state: powerStateOff, wantStatus: StatusOffline},
+		{name: "powered on", id: powerStateSensorID, state: powerStateOn, wantStatus: StatusOnline},
+		{name: "unknown state", id: powerStateSensorID, state: "Unknown"},
+		{name: "other sensor", id: "other", state: powerStateSuspend},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+			availability := NewAvailability()
+
+			entityCh := make(chan models.Entity, 1)
+			entityCh <- models.NewSensor(ctx,
+				models.WithName("Test"),
+				models.WithID(tt.id),
+				models.WithState(tt.state),
+			)
+			close(entityCh)
+
+			outCh := availability.Observe(ctx, entityCh)
+			entity, ok := <-outCh
+			require.True(t, ok)
+			sensor, err := entity.AsSensor()
+			require.NoError(t, err)
+			assert.Equal(t, tt.id, sensor.UniqueID)
+
+			if tt.wantStatus == "" {
+				assert.Empty(t, availability.Updates())
+			} else {
+				assert.Equal(t, tt.wantStatus, <-availability.Updates())
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+// Package bridge provides a worker that bridges the messages published on
+// arbitrary MQTT topics (for example, by other daemons on the device) into
+// sensors of the agent.
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/eclipse/paho.golang/paho"
+	slogctx "github.com/veqryn/slog-context"
+
+	"github.com/joshuar/go-hass-agent/agent/workers"
+	"github.com/joshuar/go-hass-agent/agent/workers/mqtt"
+	"github.com/joshuar/go-hass-agent/models"
+)
+
+const (
+	workerID   = "mqtt_bridge"
+	workerDesc = "MQTT topic sensors"
+
+	// PreferencesID is the path in the preferences file for the worker
+	// preferences.
+	PreferencesID = "mqtt_bridge"
+)
+
+var (
+	_ workers.EntityWorker = (*Worker)(nil)
+	_ workers.MQTTWorker   = (*subscriber)(nil)
+)
+
+// Preferences are the preferences of the bridge worker, containing the
+// sensors to bridge.
+type Preferences struct {
+	workers.CommonWorkerPrefs `toml:",squash"`
+
+	Sensors []SensorConfig `toml:"sensors"`
+}
+
+// Worker is an entity worker that generates sensors from the messages
+// published on MQTT topics. The topics are subscribed to through the agent's
+// MQTT connection, using the MQTT worker returned by Subscriber.
+type Worker struct {
+	*models.WorkerMetadata
+
+	prefs  *Preferences
+	topics map[string][]*sensor
+	outCh  chan models.Entity
+	ready  chan struct{}
+	ctx    context.Context //nolint:containedctx // used by the subscription callbacks.
+	mu     sync.Mutex
+}
+
+// NewWorker creates a new bridge worker from the sensors in the preferences.
+// Any sensors with an invalid configuration are logged and skipped.
+func NewWorker(ctx context.Context) (*Worker, error) {
+	worker := &Worker{
+		WorkerMetadata: models.SetWorkerMetadata(workerID, workerDesc),
+		topics:         make(map[string][]*sensor),
+		outCh:          make(chan models.Entity),
+		ready:          make(chan struct{}),
+	}
+
+	var err error
+
+	worker.prefs, err = workers.LoadWorkerPreferences(PreferencesID, &Preferences{})
+	if err != nil {
+		return worker, fmt.Errorf("could not load preferences: %w", err)
+	}
+
+	for _, cfg := range worker.prefs.Sensors {
+		s, err := newSensor(cfg)
+		if err != nil {
+			slogctx.FromCtx(ctx).Warn("Ignoring MQTT bridge sensor.",
+				slog.Any("error", err))
+
+			continue
+		}
+
+		worker.topics[cfg.Topic] = append(worker.topics[cfg.Topic], s)
+	}
+
+	return worker, nil
+}
+
+// ID returns the id of the worker.
+func (w *Worker) ID() string {
+	return workerID
+}
+
+// IsDisabled returns whether the worker has been disabled or has no sensors
+// to bridge.
+func (w *Worker) IsDisabled() bool {
+	return w.prefs.IsDisabled() || len(w.topics) == 0
+}
+
+// Start returns the channel on which the bridged sensors are sent. Sensors are
+// generated once the subscriptions of the worker are made, by the MQTT worker
+// returned by Subscriber.
+func (w *Worker) Start(ctx context.Context) (<-chan models.Entity, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.ctx = ctx
+	close(w.ready)
+
+	go func() {
+		<-ctx.Done()
+
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		close(w.outCh)
+	}()
+
+	return w.outCh, nil
+}
+
+// Subscriber returns an MQTT worker that subscribes to the topics of the
+// bridged sensors.
+func (w *Worker) Subscriber() workers.MQTTWorker {
+	return &subscriber{worker: w}
+}
+
+// handle generates the sensors for a message received on the given topic,
+// sending them on the output channel. As retained messages are received as
+// soon as the subscriptions are made, it waits for the worker to be started
+// (or the given context to be canceled) first. Messages are dropped once the
+// worker has stopped.
+func (w *Worker) handle(ctx context.Context, sensors []*sensor, msg *paho.Publish) {
+	select {
+	case <-w.ready:
+	case <-ctx.Done():
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.ctx.Err() != nil {
+		return
+	}
+
+	for _, s := range sensors {
+		entity, err := s.entity(w.ctx, msg.Topic, msg.Payload)
+		if err != nil {
+			slogctx.FromCtx(w.ctx).Debug("Could not bridge MQTT message to sensor.",
+				slog.String("sensor", s.cfg.Name),
+				slog.String("topic", msg.Topic),
+				slog.Any("error", err))
+
+			continue
+		}
+
+		select {
+		case w.outCh <- entity:
+		case <-w.ctx.Done():
+			return
+		}
+	}
+}
+
+// subscriber is the MQTT side of the bridge worker.
+type subscriber struct {
+	worker *Worker
+}
+
+// IsDisabled returns whether the bridge worker is disabled.
+func (s *subscriber) IsDisabled() bool {
+	return s.worker.IsDisabled()
+}
+
+// Start returns the subscriptions for the topics of the bridged sensors.
+func (s *subscriber) Start(ctx context.Context) (*mqtt.WorkerData, error) {
+	subs := make([]*models.MQTTSubscription, 0, len(s.worker.topics))
+
+	for topic, sensors := range s.worker.topics {
+		subs = append(subs, &models.MQTTSubscription{
+			Topic: topic,
+			Callback: func(msg *paho.Publish) {
+				s.worker.handle(ctx, sensors, msg)
+			},
+		})
+	}
+
+	return &mqtt.WorkerData{Subscriptions: subs}, nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package bridge
+
+import (
+	"context"
+	"testing"
+
+	"github.com/eclipse/paho.golang/paho"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshuar/go-hass-agent/models"
+)
+
+func newTestWorker(t *testing.T, cfgs ...SensorConfig) *Worker {
+	t.Helper()
+
+	worker := &Worker{
+		WorkerMetadata: models.SetWorkerMetadata(workerID, workerDesc),
+		prefs:          &Preferences{Sensors: cfgs},
+		topics:         make(map[string][]*sensor),
+		outCh:          make(chan models.Entity),
+		ready:          make(chan struct{}),
+	}
+	for _, cfg := range cfgs {
+		s, err := newSensor(cfg)
+		require.NoError(t, err)
+		worker.topics[cfg.Topic] = append(worker.topics[cfg.Topic], s)
+	}
+
+	return worker
+}
+
+func TestWorker_subscriptions(t *testing.T) {
+	worker := newTestWorker(t,
+		SensorConfig{Name: "Temperature", Topic: "sensors/living_room", ValueJSONPath: "$.temperature"},
+		SensorConfig{Name: "Humidity", Topic: "sensors/living_room", ValueJSONPath: "$.humidity"},
+	)
+	assert.False(t, worker.IsDisabled())
+	assert.True(t, newTestWorker(t).IsDisabled())
+
+	data, err := worker.Subscriber().Start(t.Context())
+	require.NoError(t, err)
+	// Sensors on the same topic share a subscription.
+	require.Len(t, data.Subscriptions, 1)
+	assert.Equal(t, "sensors/living_room", data.Subscriptions[0].Topic)
+
+	// Messages received before the worker is started are not lost (e.g.,
+	// retained messages).
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		data.Subscriptions[0].Callback(&paho.Publish{
+			Topic:   "sensors/living_room",
+			Payload: []byte(`{"temperature": 21.5, "humidity": 40}`),
+		})
+	}()
+
+	ctx, cancelFunc := context.WithCancel(t.Context())
+	entityCh, err := worker.Start(ctx)
+	require.NoError(t, err)
+
+	got := make(map[string]any)
+	for range 2 {
+		sensor, err := (<-entityCh).AsSensor()
+		require.NoError(t, err)
+		got[sensor.UniqueID] = sensor.State
+	}
+	<-done
+	assert.Equal(t, map[string]any{"temperature": 21.5, "humidity": 40.0}, got)
+
+	// Messages received after the worker has stopped are dropped.
+	cancelFunc()
+	for range entityCh {
+	}
+	data.Subscriptions[0].Callback(&paho.Publish{
+		Topic:   "sensors/living_room",
+		Payload: []byte(`{"temperature": 22}`),
+	})
+}
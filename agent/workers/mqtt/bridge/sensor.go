@@ -0,0 +1,267 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package bridge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/iancoleman/strcase"
+	"github.com/speakeasy-api/jsonpath/pkg/jsonpath"
+	"gopkg.in/yaml.v3"
+
+	"github.com/joshuar/go-hass-agent/models"
+)
+
+const (
+	defaultIcon = "mdi:message-arrow-right"
+	binaryType  = "binary"
+)
+
+var (
+	// ErrInvalidSensor indicates the configuration of a bridged sensor is
+	// invalid.
+	ErrInvalidSensor = errors.New("invalid sensor")
+	// ErrNoValue indicates no value could be extracted from a message.
+	ErrNoValue = errors.New("no value found")
+)
+
+// SensorConfig is the configuration of a sensor whose state is taken from the
+// messages published on an MQTT topic.
+//
+// The state is extracted from the message payload with either a JSONPath
+// expression (RFC 9535) or a Go template. If neither is given, the whole
+// payload is used as the state.
+type SensorConfig struct {
+	Name               string `toml:"name"`
+	ID                 string `toml:"id,omitempty"`
+	Topic              string `toml:"topic"`
+	ValueJSONPath      string `toml:"value_jsonpath,omitempty"`
+	ValueTemplate      string `toml:"value_template,omitempty"`
+	AttributesJSONPath string `toml:"attributes_jsonpath,omitempty"`
+	Type               string `toml:"type,omitempty"`
+	Icon               string `toml:"icon,omitempty"`
+	Units              string `toml:"units,omitempty"`
+	DeviceClass        string `toml:"device_class,omitempty"`
+	StateClass         string `toml:"state_class,omitempty"`
+}
+
+// templateData is the data available to a value template.
+type templateData struct {
+	// Value is the payload decoded as JSON, or nil if the payload is not JSON.
+	Value any
+	// Payload is the raw payload.
+	Payload string
+	// Topic is the topic the message was published on.
+	Topic string
+}
+
+// sensor is a bridged sensor, with its value extraction parsed and ready for
+// use.
+type sensor struct {
+	cfg        SensorConfig
+	id         string
+	valuePath  *jsonpath.JSONPath
+	attrsPath  *jsonpath.JSONPath
+	valueTmpl  *template.Template
+	deviceType models.SensorType
+}
+
+// newSensor validates the given config and creates a bridged sensor from it.
+func newSensor(cfg SensorConfig) (*sensor, error) {
+	if cfg.Name == "" {
+		return nil, fmt.Errorf("%w: no name specified", ErrInvalidSensor)
+	}
+
+	if cfg.Topic == "" {
+		return nil, fmt.Errorf("%w: %s: no topic specified", ErrInvalidSensor, cfg.Name)
+	}
+
+	if cfg.ValueJSONPath != "" && cfg.ValueTemplate != "" {
+		return nil, fmt.Errorf("%w: %s: only one of value_jsonpath or value_template can be specified", ErrInvalidSensor, cfg.Name)
+	}
+
+	s := &sensor{
+		cfg:        cfg,
+		id:         cfg.ID,
+		deviceType: models.SensorTypeSensor,
+	}
+	if s.id == "" {
+		s.id = strcase.ToSnake(cfg.Name)
+	}
+
+	if cfg.Type == binaryType {
+		s.deviceType = models.SensorTypeBinarySensor
+	}
+
+	var err error
+
+	if cfg.ValueJSONPath != "" {
+		if s.valuePath, err = jsonpath.NewPath(cfg.ValueJSONPath); err != nil {
+			return nil, fmt.Errorf("%w: %s: value_jsonpath: %w", ErrInvalidSensor, cfg.Name, err)
+		}
+	}
+
+	if cfg.AttributesJSONPath != "" {
+		if s.attrsPath, err = jsonpath.NewPath(cfg.AttributesJSONPath); err != nil {
+			return nil, fmt.Errorf("%w: %s: attributes_jsonpath: %w", ErrInvalidSensor, cfg.Name, err)
+		}
+	}
+
+	if cfg.ValueTemplate != "" {
+		if s.valueTmpl, err = template.New(s.id).Option("missingkey=zero").Parse(cfg.ValueTemplate); err != nil {
+			return nil, fmt.Errorf("%w: %s: value_template: %w", ErrInvalidSensor, cfg.Name, err)
+		}
+	}
+
+	return s, nil
+}
+
+// entity extracts the state (and any attributes) from the given message
+// payload and returns the sensor entity.
+func (s *sensor) entity(ctx context.Context, topic string, payload []byte) (models.Entity, error) {
+	state, err := s.value(topic, payload)
+	if err != nil {
+		return models.Entity{}, err
+	}
+
+	if s.deviceType == models.SensorTypeBinarySensor {
+		state, err = binaryState(state)
+		if err != nil {
+			return models.Entity{}, err
+		}
+	}
+
+	options := []models.SensorOption{
+		models.WithName(s.cfg.Name),
+		models.WithID(s.id),
+		models.WithIcon(s.icon()),
+		models.WithUnits(s.cfg.Units),
+		models.WithDeviceClass(models.ParseSensorDeviceClass(s.cfg.DeviceClass)),
+		models.WithStateClass(models.ParseSensorStateClass(s.cfg.StateClass)),
+		models.WithDataSourceAttribute(topic),
+		models.WithState(state),
+	}
+
+	if s.deviceType == models.SensorTypeBinarySensor {
+		options = append(options, models.AsTypeBinarySensor())
+	}
+
+	if s.attrsPath != nil {
+		attributes, err := s.attributes(payload)
+		if err != nil {
+			return models.Entity{}, err
+		}
+
+		options = append(options, models.WithAttributes(attributes))
+	}
+
+	return models.NewSensor(ctx, options...), nil
+}
+
+// value extracts the state from the given payload.
+func (s *sensor) value(topic string, payload []byte) (any, error) {
+	switch {
+	case s.valuePath != nil:
+		return queryJSON(s.valuePath, payload)
+	case s.valueTmpl != nil:
+		data := templateData{Payload: string(payload), Topic: topic}
+		// Non-JSON payloads are still available to the template as the raw
+		// payload.
+		_ = json.Unmarshal(payload, &data.Value) //nolint:errcheck
+
+		var buf bytes.Buffer
+		if err := s.valueTmpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("execute value template: %w", err)
+		}
+
+		return strings.TrimSpace(buf.String()), nil
+	default:
+		return strings.TrimSpace(string(payload)), nil
+	}
+}
+
+// attributes extracts the attributes from the given payload. The attributes
+// JSONPath must select an object.
+func (s *sensor) attributes(payload []byte) (map[string]any, error) {
+	value, err := queryJSON(s.attrsPath, payload)
+	if err != nil {
+		return nil, fmt.Errorf("attributes: %w", err)
+	}
+
+	attributes, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("attributes: %w: not an object", ErrNoValue)
+	}
+
+	return attributes, nil
+}
+
+// icon returns the icon of the sensor, or a default icon if none was set.
+func (s *sensor) icon() string {
+	if s.cfg.Icon == "" {
+		return defaultIcon
+	}
+
+	return s.cfg.Icon
+}
+
+// queryJSON returns the first value in the given JSON payload selected by the
+// given JSONPath.
+func queryJSON(path *jsonpath.JSONPath, payload []byte) (any, error) {
+	// JSON is valid YAML, and the JSONPath implementation operates on YAML
+	// nodes.
+	var root yaml.Node
+	if err := yaml.Unmarshal(payload, &root); err != nil {
+		return nil, fmt.Errorf("parse payload: %w", err)
+	}
+
+	nodes := path.Query(&root)
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoValue, path.String())
+	}
+
+	var value any
+	if err := nodes[0].Decode(&value); err != nil {
+		return nil, fmt.Errorf("decode value: %w", err)
+	}
+
+	return value, nil
+}
+
+// binaryState converts the given value to the state of a binary sensor.
+// Booleans, numbers and strings like "on"/"off" or "true"/"false" are
+// understood.
+func binaryState(value any) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case int:
+		return v != 0, nil
+	case float64:
+		return v != 0, nil
+	case string:
+		switch strings.ToLower(v) {
+		case "on", "yes":
+			return true, nil
+		case "off", "no":
+			return false, nil
+		}
+
+		state, err := strconv.ParseBool(v)
+		if err != nil {
+			return false, fmt.Errorf("%w: %q is not a binary state", ErrNoValue, v)
+		}
+
+		return state, nil
+	default:
+		return false, fmt.Errorf("%w: %v is not a binary state", ErrNoValue, value)
+	}
+}
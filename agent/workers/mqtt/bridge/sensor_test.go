@@ -0,0 +1,198 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package bridge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshuar/go-hass-agent/models"
+)
+
+func Test_newSensor(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     SensorConfig
+		wantID  string
+		wantErr bool
+	}{
+		{
+			name:   "valid",
+			cfg:    SensorConfig{Name: "Living Room Temperature", Topic: "sensors/living_room", ValueJSONPath: "$.temperature"},
+			wantID: "living_room_temperature",
+		},
+		{
+			name:   "custom id",
+			cfg:    SensorConfig{Name: "Temperature", ID: "lr_temp", Topic: "sensors/living_room"},
+			wantID: "lr_temp",
+		},
+		{
+			name:    "no name",
+			cfg:     SensorConfig{Topic: "sensors/living_room"},
+			wantErr: true,
+		},
+		{
+			name:    "no topic",
+			cfg:     SensorConfig{Name: "Temperature"},
+			wantErr: true,
+		},
+		{
+			name:    "jsonpath and template",
+			cfg:     SensorConfig{Name: "Temperature", Topic: "sensors/living_room", ValueJSONPath: "$.temperature", ValueTemplate: "{{ .Payload }}"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid jsonpath",
+			cfg:     SensorConfig{Name: "Temperature", Topic: "sensors/living_room", ValueJSONPath: "$.[temperature"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid template",
+			cfg:     SensorConfig{Name: "Temperature", Topic: "sensors/living_room", ValueTemplate: "{{ .Payload "},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newSensor(tt.cfg)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidSensor)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantID, got.id)
+		})
+	}
+}
+
+func Test_sensor_entity(t *testing.T) {
+	payload := []byte(`{"temperature": 21.5, "state": "ON", "battery": {"level": 90, "charging": false}}`)
+
+	tests := []struct {
+		name      string
+		cfg       SensorConfig
+		payload   []byte
+		wantState any
+		wantAttrs map[string]any
+		wantType  models.SensorType
+		wantErr   bool
+	}{
+		{
+			name:      "jsonpath",
+			cfg:       SensorConfig{ValueJSONPath: "$.temperature", Units: "°C", DeviceClass: "temperature", StateClass: "measurement"},
+			payload:   payload,
+			wantState: 21.5,
+			wantType:  models.SensorTypeSensor,
+		},
+		{
+			name:      "nested jsonpath",
+			cfg:       SensorConfig{ValueJSONPath: "$.battery.level"},
+			payload:   payload,
+			wantState: 90,
+			wantType:  models.SensorTypeSensor,
+		},
+		{
+			name:      "template",
+			cfg:       SensorConfig{ValueTemplate: `{{ if gt .Value.temperature 20.0 }}warm{{ else }}cold{{ end }}`},
+			payload:   payload,
+			wantState: "warm",
+			wantType:  models.SensorTypeSensor,
+		},
+		{
+			name:      "template with raw payload",
+			cfg:       SensorConfig{ValueTemplate: `{{ .Payload }} on {{ .Topic }}`},
+			payload:   []byte("running"),
+			wantState: "running on sensors/test",
+			wantType:  models.SensorTypeSensor,
+		},
+		{
+			name:      "raw payload",
+			cfg:       SensorConfig{},
+			payload:   []byte(" running\n"),
+			wantState: "running",
+			wantType:  models.SensorTypeSensor,
+		},
+		{
+			name:      "binary",
+			cfg:       SensorConfig{ValueJSONPath: "$.state", Type: binaryType},
+			payload:   payload,
+			wantState: true,
+			wantType:  models.SensorTypeBinarySensor,
+		},
+		{
+			name:      "attributes",
+			cfg:       SensorConfig{ValueJSONPath: "$.temperature", AttributesJSONPath: "$.battery"},
+			payload:   payload,
+			wantState: 21.5,
+			wantAttrs: map[string]any{"level": 90, "charging": false},
+			wantType:  models.SensorTypeSensor,
+		},
+		{
+			name:    "missing value",
+			cfg:     SensorConfig{ValueJSONPath: "$.humidity"},
+			payload: payload,
+			wantErr: true,
+		},
+		{
+			name:    "not json",
+			cfg:     SensorConfig{ValueJSONPath: "$.temperature"},
+			payload: []byte("{not json"),
+			wantErr: true,
+		},
+		{
+			name:    "invalid binary state",
+			cfg:     SensorConfig{ValueJSONPath: "$.temperature", Type: binaryType},
+			payload: []byte(`{"temperature": "warm"}`),
+			wantErr: true,
+		},
+		{
+			name:    "attributes not an object",
+			cfg:     SensorConfig{ValueJSONPath: "$.temperature", AttributesJSONPath: "$.temperature"},
+			payload: payload,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Name = "Test"
+			tt.cfg.Topic = "sensors/#"
+			s, err := newSensor(tt.cfg)
+			require.NoError(t, err)
+
+			entity, err := s.entity(t.Context(), "sensors/test", tt.payload)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			sensor, err := entity.AsSensor()
+			require.NoError(t, err)
+			assert.Equal(t, "test", sensor.UniqueID)
+			assert.Equal(t, tt.wantType, sensor.Type)
+			assert.EqualValues(t, tt.wantState, sensor.State)
+			assert.Equal(t, "sensors/test", sensor.Attributes["data_source"])
+			for key, value := range tt.wantAttrs {
+				assert.EqualValues(t, value, sensor.Attributes[key])
+			}
+		})
+	}
+}
+
+func Test_binaryState(t *testing.T) {
+	for _, value := range []any{true, 1, 1.0, "ON", "on", "true", "yes", "1"} {
+		got, err := binaryState(value)
+		require.NoError(t, err)
+		assert.True(t, got, value)
+	}
+	for _, value := range []any{false, 0, 0.0, "OFF", "off", "false", "no", "0"} {
+		got, err := binaryState(value)
+		require.NoError(t, err)
+		assert.False(t, got, value)
+	}
+	_, err := binaryState("unknown")
+	require.ErrorIs(t, err, ErrNoValue)
+}
@@ -0,0 +1,409 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eclipse/paho.golang/autopaho"
+	"github.com/eclipse/paho.golang/paho"
+	mqttapi "github.com/joshuar/go-hass-anything/v12/pkg/mqtt"
+
+	"github.com/joshuar/go-hass-agent/config"
+	"github.com/joshuar/go-hass-agent/logging"
+	"github.com/joshuar/go-hass-agent/models"
+)
+
+const (
+	defaultKeepAliveSec     = 20
+	defaultSessionExpirySec = 60
+	// disconnectTimeout is how long to wait to publish the offline status and
+	// disconnect when the agent is stopping.
+	disconnectTimeout = 5 * time.Second
+
+	// StatusOnline is the availability status when the device is available.
+	StatusOnline = "online"
+	// StatusOffline is the availability status when the device is not
+	// available.
+	StatusOffline = "offline"
+
+	availabilityTopicName  = "availability"
+	availabilityTopicField = "availability_topic"
+	configTopicName        = "config"
+	statusTopicName        = "status"
+)
+
+var (
+	ErrNoConnection       = errors.New("no MQTT connection")
+	ErrInvalidTopicPrefix = errors.New("invalid topic prefix")
+)
+
+// client is a connection to the MQTT broker. The client reports the
+// availability of the device on a per-device availability topic, with a
+// last-will that marks the device offline if the connection is lost. All
+// discovery configs published through the client have the availability topic
+// added and are re-published whenever Home Assistant comes online.
+type client struct {
+	conn              *autopaho.ConnectionManager
+	cancelFunc        context.CancelFunc
+	availabilityTopic string
+	statusTopic       string
+	topicPrefix       string
+	status            string
+	configs           map[string]*models.MQTTConfig
+	mu                sync.Mutex
+}
+
+// appID returns the app name in the form used for topics and client ids.
+func appID() string {
+	return strings.ToLower(strings.ReplaceAll(config.AppName, " ", "_"))
+}
+
+// availabilityTopic returns the availability topic for the device with the
+// given id.
+func availabilityTopic(deviceID string) string {
+	return strings.Join([]string{appID(), deviceID, availabilityTopicName}, "/")
+}
+
+// clientID returns the MQTT client id for the device with the given id.
+func clientID(deviceID string) string {
+	return appID() + "_" + deviceID
+}
+
+// newClient creates a new client connected to the MQTT broker, with the given
+// subscriptions. The given availability status is published whenever the
+// connection comes up, unless it is empty. The connection is kept open,
+// reconnecting as needed, until the client is closed.
+//
+//nolint:exhaustruct
+func newClient(ctx context.Context, cfg *Config, clientID, deviceID, status string, subscriptions []*models.MQTTSubscription) (*client, error) {
+	if cfg.TopicPrefix() == "" {
+		return nil, fmt.Errorf("could not connect: %w", ErrInvalidTopicPrefix)
+	}
+
+	serverURL, err := url.Parse(cfg.Server())
+	if err != nil {
+		return nil, fmt.Errorf("could not connect: %w", err)
+	}
+
+	tlsCfg, err := cfg.TLSConfig()
+	if err != nil {
+		return nil, fmt.Errorf("could not connect: %w", err)
+	}
+
+	c := &client{
+		availabilityTopic: availabilityTopic(deviceID),
+		statusTopic:       cfg.TopicPrefix() + "/" + statusTopicName,
+		topicPrefix:       cfg.TopicPrefix(),
+		status:            status,
+		configs:           make(map[string]*models.MQTTConfig),
+	}
+
+	router := paho.NewStandardRouter()
+	subOpts := make([]paho.SubscribeOptions, 0, len(subscriptions)+1)
+
+	for _, sub := range subscriptions {
+		slog.Debug("Adding subscription for topic.",
+			slog.String("topic", sub.Topic))
+
+		subOpts = append(subOpts, paho.SubscribeOptions{Topic: sub.Topic, QoS: 1})
+		router.RegisterHandler(sub.Topic, sub.Callback)
+	}
+
+	subOpts = append(subOpts, paho.SubscribeOptions{Topic: c.statusTopic, QoS: 1})
+	router.RegisterHandler(c.statusTopic, c.handleHAStatus)
+
+	// The connection is not tied to the given context, so that the offline
+	// status can still be published once the context is canceled.
+	connCtx, cancelFunc := context.WithCancel(context.WithoutCancel(ctx))
+	c.cancelFunc = cancelFunc
+
+	connOpts := autopaho.ClientConfig{
+		ServerUrls: []*url.URL{serverURL},
+		// Used for the ssl/tls/mqtts and wss schemes.
+		TlsCfg:                        tlsCfg,
+		KeepAlive:                     defaultKeepAliveSec,
+		CleanStartOnInitialConnection: false,
+		SessionExpiryInterval:         defaultSessionExpirySec,
+		// If the connection is lost, the broker will mark the device offline.
+		WillMessage: &paho.WillMessage{
+			Retain:  true,
+			QoS:     1,
+			Topic:   c.availabilityTopic,
+			Payload: []byte(StatusOffline),
+		},
+		OnConnectionUp: func(cm *autopaho.ConnectionManager, _ *paho.Connack) {
+			slog.Debug("MQTT connection up.")
+			// The connection may come up before NewConnection returns.
+			c.setConn(cm)
+			// Subscriptions and the availability status are (re)established
+			// on every connection. This callback must not block.
+			go func() {
+				if _, err := cm.Subscribe(connCtx, &paho.Subscribe{Subscriptions: subOpts}); err != nil {
+					slog.Warn("Failed to publish subscriptions to MQTT.",
+						slog.Any("error", err))
+				}
+
+				c.publishStatus(connCtx)
+			}()
+		},
+		OnConnectError: func(err error) {
+			slog.Error("Error establishing MQTT connection.",
+				slog.Any("error", err))
+		},
+		ClientConfig: paho.ClientConfig{
+			ClientID: clientID,
+			OnPublishReceived: []func(paho.PublishReceived) (bool, error){
+				func(pr paho.PublishReceived) (bool, error) {
+					router.Route(pr.Packet.Packet())
+
+					return true, nil
+				},
+			},
+			OnClientError: func(err error) {
+				slog.Error("MQTT client error.",
+					slog.Any("error", err))
+			},
+		},
+	}
+
+	if cfg.User() != "" && cfg.Password() != "" {
+		connOpts.ConnectUsername = cfg.User()
+		connOpts.ConnectPassword = []byte(cfg.Password())
+	}
+
+	conn, err := autopaho.NewConnection(connCtx, connOpts)
+	if err != nil {
+		cancelFunc()
+		return nil, fmt.Errorf("could not connect: %w", err)
+	}
+
+	if err := conn.AwaitConnection(ctx); err != nil {
+		cancelFunc()
+		return nil, fmt.Errorf("could not connect: %w", err)
+	}
+
+	c.setConn(conn)
+
+	return c, nil
+}
+
+// setConn sets the connection used for publishing.
+func (c *client) setConn(conn *autopaho.ConnectionManager) {
+	c.mu.Lock()
+	c.conn = conn
+	c.mu.Unlock()
+}
+
+// Publish will publish the given messages. Any discovery configs have the
+// availability topic added and are tracked, so that they can be re-published
+// when Home Assistant comes online. A config with an empty payload removes the
+// config.
+func (c *client) Publish(ctx context.Context, msgs ...*models.MQTTMsg) error {
+	var errs error
+
+	for _, msg := range msgs {
+		if msg == nil {
+			continue
+		}
+
+		if c.isConfig(msg.Topic) {
+			// Device triggers do not support availability.
+			if !strings.Contains(msg.Topic, "/"+deviceAutomationType+"/") {
+				msg = withAvailability(msg, c.availabilityTopic)
+			}
+
+			c.mu.Lock()
+			if len(msg.Message) == 0 {
+				delete(c.configs, msg.Topic)
+			} else {
+				c.configs[msg.Topic] = msg
+			}
+			c.mu.Unlock()
+		}
+
+		errs = errors.Join(errs, c.publish(ctx, msg))
+	}
+
+	return errs
+}
+
+// Unpublish will remove the given messages by publishing empty payloads to
+// their topics.
+func (c *client) Unpublish(ctx context.Context, msgs ...*models.MQTTMsg) error {
+	empty := make([]*models.MQTTMsg, 0, len(msgs))
+
+	for _, msg := range msgs {
+		if msg != nil {
+			empty = append(empty, mqttapi.NewMsg(msg.Topic, []byte(``)))
+		}
+	}
+
+	return c.Publish(ctx, empty...)
+}
+
+// SetStatus will set the availability status of the device and publish it.
+func (c *client) SetStatus(ctx context.Context, status string) {
+	c.mu.Lock()
+	c.status = status
+	c.mu.Unlock()
+
+	c.publishStatus(ctx)
+}
+
+// Close will publish the offline status and disconnect from the broker.
+func (c *client) Close() {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), disconnectTimeout)
+	defer cancelFunc()
+
+	c.SetStatus(ctx, StatusOffline)
+	c.disconnect(ctx)
+}
+
+// disconnect will disconnect from the broker and stop reconnecting.
+func (c *client) disconnect(ctx context.Context) {
+	c.mu.Lock()
+	conn := c.conn
+	c.mu.Unlock()
+
+	if err := conn.Disconnect(ctx); err != nil {
+		slog.Debug("Error disconnecting from MQTT.",
+			slog.Any("error", err))
+	}
+
+	c.cancelFunc()
+}
+
+// publishStatus publishes the current availability status as a retained
+// message.
+func (c *client) publishStatus(ctx context.Context) {
+	c.mu.Lock()
+	status := c.status
+	c.mu.Unlock()
+
+	if status == "" {
+		return
+	}
+
+	msg := mqttapi.NewMsg(c.availabilityTopic, []byte(status))
+	msg.Retain()
+
+	if err := c.publish(ctx, msg); err != nil {
+		slog.Warn("Unable to publish availability to MQTT.",
+			slog.String("status", status),
+			slog.Any("error", err))
+	}
+}
+
+// handleHAStatus handles the birth and last-will messages of Home Assistant.
+// When Home Assistant comes online, all current configs are re-published so
+// that it rediscovers the entities.
+func (c *client) handleHAStatus(p *paho.Publish) {
+	if string(p.Payload) != StatusOnline {
+		slog.Debug("Home Assistant detected offline.")
+		return
+	}
+
+	slog.Debug("Home Assistant detected online, re-publishing configs.")
+
+	c.mu.Lock()
+	configs := make([]*models.MQTTConfig, 0, len(c.configs))
+	for _, msg := range c.configs {
+		configs = append(configs, msg)
+	}
+	c.mu.Unlock()
+
+	// Handlers must not block the connection, so publish in the background.
+	go func() {
+		ctx, cancelFunc := context.WithTimeout(context.Background(), disconnectTimeout)
+		defer cancelFunc()
+
+		c.publishStatus(ctx)
+
+		for _, msg := range configs {
+			if err := c.publish(ctx, msg); err != nil {
+				slog.Warn("Unable to re-publish config to MQTT.",
+					slog.String("topic", msg.Topic),
+					slog.Any("error", err))
+			}
+		}
+	}()
+}
+
+// isConfig returns whether the topic is a discovery config topic.
+func (c *client) isConfig(topic string) bool {
+	return strings.HasPrefix(topic, c.topicPrefix+"/") && strings.HasSuffix(topic, "/"+configTopicName)
+}
+
+// publish will publish the message to the broker.
+//
+//nolint:exhaustruct
+func (c *client) publish(ctx context.Context, msg *models.MQTTMsg) error {
+	c.mu.Lock()
+	conn := c.conn
+	c.mu.Unlock()
+
+	if conn == nil {
+		return ErrNoConnection
+	}
+
+	slog.Log(ctx, logging.LevelTrace, "Publishing message.",
+		slog.String("topic", msg.Topic),
+		slog.Bool("retain", msg.Retained))
+
+	if _, err := conn.Publish(ctx, &paho.Publish{
+		QoS:     1,
+		Retain:  msg.Retained,
+		Topic:   msg.Topic,
+		Payload: msg.Message,
+	}); err != nil {
+		return fmt.Errorf("publish %s: %w", msg.Topic, err)
+	}
+
+	return nil
+}
+
+// withAvailability returns the config with the availability topic added, if
+// the config does not already specify its availability. Empty configs (which
+// remove the entity) and configs that are not JSON objects are returned
+// unchanged.
+func withAvailability(msg *models.MQTTConfig, topic string) *models.MQTTConfig {
+	if len(msg.Message) == 0 {
+		return msg
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(msg.Message, &fields); err != nil {
+		return msg
+	}
+
+	if _, found := fields[availabilityTopicField]; found {
+		return msg
+	}
+
+	if _, found := fields[availabilityTopicName]; found {
+		return msg
+	}
+
+	fields[availabilityTopicField], _ = json.Marshal(topic) //nolint:errchkjson
+
+	payload, err := json.Marshal(fields)
+	if err != nil {
+		return msg
+	}
+
+	return &models.MQTTConfig{
+		Topic:    msg.Topic,
+		Message:  payload,
+		Retained: msg.Retained,
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package mqtt
+
+import (
+	"testing"
+
+	mqttapi "github.com/joshuar/go-hass-anything/v12/pkg/mqtt"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joshuar/go-hass-agent/models"
+)
+
+func Test_withAvailability(t *testing.T) {
+	topic := "go_hass_agent/abc/availability"
+
+	tests := []struct {
+		name string
+		msg  *models.MQTTConfig
+		want string
+	}{
+		{
+			name: "adds availability",
+			msg:  mqttapi.NewMsg("homeassistant/button/app/id/config", []byte(`{"name":"test"}`)),
+			want: `{"availability_topic":"go_hass_agent/abc/availability","name":"test"}`,
+		},
+		{
+			name: "existing availability topic",
+			msg:  mqttapi.NewMsg("homeassistant/button/app/id/config", []byte(`{"availability_topic":"other"}`)),
+			want: `{"availability_topic":"other"}`,
+		},
+		{
+			name: "existing availability list",
+			msg:  mqttapi.NewMsg("homeassistant/button/app/id/config", []byte(`{"availability":[{"topic":"other"}]}`)),
+			want: `{"availability":[{"topic":"other"}]}`,
+		},
+		{
+			name: "empty config",
+			msg:  mqttapi.NewMsg("homeassistant/button/app/id/config", []byte(``)),
+			want: ``,
+		},
+		{
+			name: "not json",
+			msg:  mqttapi.NewMsg("homeassistant/button/app/id/config", []byte(`ON`)),
+			want: `ON`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := withAvailability(tt.msg, topic)
+			assert.Equal(t, tt.msg.Topic, got.Topic)
+			assert.Equal(t, tt.want, string(got.Message))
+		})
+	}
+}
+
+func Test_client_isConfig(t *testing.T) {
+	c := &client{topicPrefix: "homeassistant"}
+
+	assert.True(t, c.isConfig("homeassistant/sensor/app/id/config"))
+	assert.False(t, c.isConfig("homeassistant/sensor/app/id/state"))
+	assert.False(t, c.isConfig("other/sensor/app/id/config"))
+}
+
+func Test_availabilityTopic(t *testing.T) {
+	assert.Equal(t, "go_hass_agent/abc/availability", availabilityTopic("abc"))
+	assert.Equal(t, "go_hass_agent_abc", clientID("abc"))
+}
@@ -0,0 +1,273 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	shellPath = "/bin/sh"
+
+	placeholderString = "string"
+	placeholderInt    = "int"
+	placeholderFloat  = "float"
+)
+
+var (
+	// ErrInvalidCommand indicates the configuration of a command is invalid.
+	ErrInvalidCommand = errors.New("invalid command")
+	// ErrInvalidPayload indicates a payload received from Home Assistant is not
+	// allowed for the command.
+	ErrInvalidPayload = errors.New("payload not allowed")
+)
+
+// placeholderRegex matches the placeholders in argument templates, which are
+// either {{value}} or {{value:<type>}}.
+var placeholderRegex = regexp.MustCompile(`\{\{\s*value\s*(?::\s*(\w+)\s*)?\}\}`)
+
+// execSpec is the parsed and validated form of how the command of a control
+// is run.
+//
+// A command is never run through a shell unless requested. Payloads received
+// from Home Assistant are checked against any allowed values or pattern and
+// are then either appended to the command-line or substituted into the
+// argument templates. Either way, a payload only ever becomes (part of) a
+// single argument, and is never interpreted by a shell.
+type execSpec struct {
+	name    string
+	argv    []string
+	args    []string
+	allowed []string
+	pattern *regexp.Regexp
+	number  *numberRange
+	timeout time.Duration
+}
+
+// numberRange is the range of values allowed for the command of a number.
+type numberRange struct {
+	min, max float64
+	integer  bool
+}
+
+// newExecSpec creates an execSpec for the given command. If the command does
+// not specify its own allowed values, the given allowed values (if any) are
+// used instead.
+func newExecSpec(cmd Command, allowed ...string) (*execSpec, error) {
+	spec := &execSpec{
+		name:    cmd.Name,
+		args:    cmd.Args,
+		allowed: allowed,
+	}
+
+	if cmd.Shell {
+		// Any payloads become the positional parameters of the script.
+		spec.argv = []string{shellPath, "-c", cmd.Exec, cmd.Name}
+	} else {
+		spec.argv = strings.Fields(cmd.Exec)
+	}
+
+	if len(spec.argv) == 0 || cmd.Exec == "" {
+		return nil, fmt.Errorf("%w: %s: no exec specified", ErrInvalidCommand, cmd.Name)
+	}
+
+	for _, arg := range cmd.Args {
+		for _, match := range placeholderRegex.FindAllStringSubmatch(arg, -1) {
+			switch match[1] {
+			case "", placeholderString, placeholderInt, placeholderFloat:
+			default:
+				return nil, fmt.Errorf("%w: %s: unknown placeholder type %q", ErrInvalidCommand, cmd.Name, match[1])
+			}
+		}
+	}
+
+	if len(cmd.Allow) > 0 {
+		spec.allowed = cmd.Allow
+	}
+
+	// For texts, the pattern of the text is used if no pattern was given.
+	pattern := cmd.AllowPattern
+	if pattern == "" {
+		pattern = cmd.Pattern
+	}
+
+	if pattern != "" {
+		regex, err := regexp.Compile(`^(?:` + pattern + `)$`)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidCommand, cmd.Name, err)
+		}
+
+		spec.pattern = regex
+	}
+
+	if cmd.Timeout != "" {
+		timeout, err := time.ParseDuration(cmd.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidCommand, cmd.Name, err)
+		}
+
+		spec.timeout = timeout
+	}
+
+	return spec, nil
+}
+
+// withRange restricts the payloads of the command to numbers between the given
+// minimum and maximum (inclusive), which must be integers if requested.
+func (s *execSpec) withRange(minValue, maxValue float64, integer bool) {
+	s.number = &numberRange{min: minValue, max: maxValue, integer: integer}
+}
+
+// commandLine returns the command-line for running the command with the given
+// payloads. An error is returned if any payload is not allowed or does not
+// match the type of its placeholder.
+func (s *execSpec) commandLine(payloads ...string) ([]string, error) {
+	payloads = slices.Clone(payloads)
+
+	for idx, payload := range payloads {
+		valid, err := s.validate(payload)
+		if err != nil {
+			return nil, err
+		}
+
+		payloads[idx] = valid
+	}
+
+	argv := slices.Clone(s.argv)
+
+	// Without argument templates, any payloads are appended to the command-line.
+	if len(s.args) == 0 {
+		return append(argv, payloads...), nil
+	}
+
+	for _, arg := range s.args {
+		rendered, err := renderArg(arg, payloads)
+		if err != nil {
+			return nil, err
+		}
+
+		argv = append(argv, rendered)
+	}
+
+	return argv, nil
+}
+
+// validate checks the payload is allowed and returns it in the form passed to
+// the command. The payloads of numbers must be a number within their range and
+// are passed in their canonical form. As they might otherwise be interpreted as
+// options by the command, payloads starting with "-" are only allowed by an
+// explicit list of allowed values or pattern.
+func (s *execSpec) validate(payload string) (string, error) {
+	if payload == "" {
+		return "", fmt.Errorf("%w: no state specified", ErrCmdFailed)
+	}
+
+	if len(s.allowed) > 0 && !slices.Contains(s.allowed, payload) {
+		return "", fmt.Errorf("%w: %q is not one of the allowed values", ErrInvalidPayload, payload)
+	}
+
+	if s.pattern != nil && !s.pattern.MatchString(payload) {
+		return "", fmt.Errorf("%w: %q does not match the allowed pattern", ErrInvalidPayload, payload)
+	}
+
+	if s.number != nil {
+		return s.number.validate(payload)
+	}
+
+	if len(s.allowed) == 0 && s.pattern == nil && strings.HasPrefix(payload, "-") {
+		return "", fmt.Errorf("%w: %q starts with \"-\" and no allowed values or pattern are set",
+			ErrInvalidPayload, payload)
+	}
+
+	return payload, nil
+}
+
+// validate checks the payload is a number within the range and returns it in
+// its canonical form.
+func (r *numberRange) validate(payload string) (string, error) {
+	value, err := strconv.ParseFloat(payload, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return "", fmt.Errorf("%w: %q is not a number", ErrInvalidPayload, payload)
+	}
+
+	if r.integer && value != math.Trunc(value) {
+		return "", fmt.Errorf("%w: %q is not an integer", ErrInvalidPayload, payload)
+	}
+
+	if value < r.min || value > r.max {
+		return "", fmt.Errorf("%w: %q is not between %v and %v", ErrInvalidPayload, payload, r.min, r.max)
+	}
+
+	if r.integer {
+		return strconv.FormatInt(int64(value), 10), nil
+	}
+
+	return strconv.FormatFloat(value, 'f', -1, 64), nil
+}
+
+// context returns a context for running the command, which has a deadline if
+// the command has a timeout.
+func (s *execSpec) context() (context.Context, context.CancelFunc) {
+	if s.timeout == 0 {
+		return context.WithCancel(context.Background())
+	}
+
+	return context.WithTimeout(context.Background(), s.timeout)
+}
+
+// renderArg substitutes the payload into any placeholders in the argument
+// template, checking the payload matches the type of each placeholder.
+func renderArg(arg string, payloads []string) (string, error) {
+	var renderErr error
+
+	rendered := placeholderRegex.ReplaceAllStringFunc(arg, func(placeholder string) string {
+		if len(payloads) == 0 {
+			renderErr = fmt.Errorf("%w: no value for %s", ErrInvalidPayload, placeholder)
+			return ""
+		}
+
+		value, err := formatValue(placeholderRegex.FindStringSubmatch(placeholder)[1], payloads[0])
+		if err != nil {
+			renderErr = err
+		}
+
+		return value
+	})
+	if renderErr != nil {
+		return "", renderErr
+	}
+
+	return rendered, nil
+}
+
+// formatValue checks the payload is of the given placeholder type and returns
+// it in its canonical form.
+func formatValue(valueType, payload string) (string, error) {
+	switch valueType {
+	case placeholderInt:
+		value, err := strconv.ParseInt(payload, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("%w: %q is not an int", ErrInvalidPayload, payload)
+		}
+
+		return strconv.FormatInt(value, 10), nil
+	case placeholderFloat:
+		value, err := strconv.ParseFloat(payload, 64)
+		if err != nil {
+			return "", fmt.Errorf("%w: %q is not a float", ErrInvalidPayload, payload)
+		}
+
+		return strconv.FormatFloat(value, 'f', -1, 64), nil
+	default:
+		return payload, nil
+	}
+}
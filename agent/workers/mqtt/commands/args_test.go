@@ -0,0 +1,227 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_newExecSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		cmd     Command
+		wantErr error
+	}{
+		{
+			name: "valid",
+			cmd:  Command{Name: "test", Exec: "echo", Args: []string{"--level={{value:int}}"}, AllowPattern: "[0-9]+"},
+		},
+		{
+			name:    "no exec",
+			cmd:     Command{Name: "test"},
+			wantErr: ErrInvalidCommand,
+		},
+		{
+			name:    "unknown placeholder type",
+			cmd:     Command{Name: "test", Exec: "echo", Args: []string{"{{value:bytes}}"}},
+			wantErr: ErrInvalidCommand,
+		},
+		{
+			name:    "invalid pattern",
+			cmd:     Command{Name: "test", Exec: "echo", AllowPattern: "[0-9"},
+			wantErr: ErrInvalidCommand,
+		},
+		{
+			name:    "invalid timeout",
+			cmd:     Command{Name: "test", Exec: "echo", Timeout: "soon"},
+			wantErr: ErrInvalidCommand,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newExecSpec(tt.cmd)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func Test_execSpec_commandLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		cmd      Command
+		allowed  []string
+		number   *numberRange
+		payloads []string
+		want     []string
+		wantErr  error
+	}{
+		{
+			name:     "appended payload",
+			cmd:      Command{Exec: "brightness  set"},
+			payloads: []string{"50"},
+			want:     []string{"brightness", "set", "50"},
+		},
+		{
+			name: "no payload",
+			cmd:  Command{Exec: "systemctl suspend"},
+			want: []string{"systemctl", "suspend"},
+		},
+		{
+			name:     "templated payload",
+			cmd:      Command{Exec: "brightness", Args: []string{"set", "--level={{ value:int }}", "--quiet"}},
+			payloads: []string{"050"},
+			want:     []string{"brightness", "set", "--level=50", "--quiet"},
+		},
+		{
+			name:     "templated float",
+			cmd:      Command{Exec: "volume", Args: []string{"{{value:float}}"}},
+			payloads: []string{"0.50"},
+			want:     []string{"volume", "0.5"},
+		},
+		{
+			name:     "wrong type",
+			cmd:      Command{Exec: "brightness", Args: []string{"{{value:int}}"}},
+			payloads: []string{"50; rm -rf /"},
+			wantErr:  ErrInvalidPayload,
+		},
+		{
+			name:    "template without payload",
+			cmd:     Command{Exec: "brightness", Args: []string{"{{value}}"}},
+			wantErr: ErrInvalidPayload,
+		},
+		{
+			name:     "shell metacharacters are a single argument",
+			cmd:      Command{Exec: "notify-send", Args: []string{"Message: {{value}}"}},
+			payloads: []string{"$(reboot) && `reboot`"},
+			want:     []string{"notify-send", "Message: $(reboot) && `reboot`"},
+		},
+		{
+			name:     "shell",
+			cmd:      Command{Name: "say", Exec: `echo "$1"`, Shell: true},
+			payloads: []string{"$(reboot)"},
+			want:     []string{shellPath, "-c", `echo "$1"`, "say", "$(reboot)"},
+		},
+		{
+			name:     "implicit allowed",
+			cmd:      Command{Exec: "toggle"},
+			allowed:  []string{switchOnState, switchOffState},
+			payloads: []string{"MAYBE"},
+			wantErr:  ErrInvalidPayload,
+		},
+		{
+			name:     "explicit allowed overrides implicit",
+			cmd:      Command{Exec: "toggle", Allow: []string{"MAYBE"}},
+			allowed:  []string{switchOnState, switchOffState},
+			payloads: []string{"MAYBE"},
+			want:     []string{"toggle", "MAYBE"},
+		},
+		{
+			name:     "pattern",
+			cmd:      Command{Exec: "hostname", AllowPattern: `[a-z0-9-]+`},
+			payloads: []string{"my-host"},
+			want:     []string{"hostname", "my-host"},
+		},
+		{
+			name:     "pattern must fully match",
+			cmd:      Command{Exec: "hostname", AllowPattern: `[a-z0-9-]+`},
+			payloads: []string{"my-host; reboot"},
+			wantErr:  ErrInvalidPayload,
+		},
+		{
+			name:     "text pattern",
+			cmd:      Command{Exec: "hostname", Pattern: `[a-z]+`},
+			payloads: []string{"host1"},
+			wantErr:  ErrInvalidPayload,
+		},
+		{
+			name:     "option-like payload",
+			cmd:      Command{Exec: "notify-send"},
+			payloads: []string{"--output=/etc/x"},
+			wantErr:  ErrInvalidPayload,
+		},
+		{
+			name:     "option-like payload in template",
+			cmd:      Command{Exec: "notify-send", Args: []string{"{{value}}"}},
+			payloads: []string{"-x"},
+			wantErr:  ErrInvalidPayload,
+		},
+		{
+			name:     "option-like payload allowed by pattern",
+			cmd:      Command{Exec: "offset", AllowPattern: `-?[0-9]+`},
+			payloads: []string{"-5"},
+			want:     []string{"offset", "-5"},
+		},
+		{
+			name:     "int number",
+			cmd:      Command{Exec: "brightness"},
+			number:   &numberRange{min: 0, max: 100, integer: true},
+			payloads: []string{"50.0"},
+			want:     []string{"brightness", "50"},
+		},
+		{
+			name:     "negative number",
+			cmd:      Command{Exec: "offset"},
+			number:   &numberRange{min: -10, max: 10},
+			payloads: []string{"-2.50"},
+			want:     []string{"offset", "-2.5"},
+		},
+		{
+			name:     "number not a number",
+			cmd:      Command{Exec: "brightness"},
+			number:   &numberRange{min: 0, max: 100, integer: true},
+			payloads: []string{"--output=/etc/x"},
+			wantErr:  ErrInvalidPayload,
+		},
+		{
+			name:     "number not an integer",
+			cmd:      Command{Exec: "brightness"},
+			number:   &numberRange{min: 0, max: 100, integer: true},
+			payloads: []string{"50.5"},
+			wantErr:  ErrInvalidPayload,
+		},
+		{
+			name:     "number out of range",
+			cmd:      Command{Exec: "brightness"},
+			number:   &numberRange{min: 0, max: 100},
+			payloads: []string{"101"},
+			wantErr:  ErrInvalidPayload,
+		},
+		{
+			name:     "number not finite",
+			cmd:      Command{Exec: "brightness"},
+			number:   &numberRange{min: 0, max: 100},
+			payloads: []string{"NaN"},
+			wantErr:  ErrInvalidPayload,
+		},
+		{
+			name:     "empty payload",
+			cmd:      Command{Exec: "toggle"},
+			payloads: []string{""},
+			wantErr:  ErrCmdFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := newExecSpec(tt.cmd, tt.allowed...)
+			require.NoError(t, err)
+			if tt.number != nil {
+				spec.withRange(tt.number.min, tt.number.max, tt.number.integer)
+			}
+			got, err := spec.commandLine(tt.payloads...)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
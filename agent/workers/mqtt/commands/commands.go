@@ -15,10 +15,14 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/eclipse/paho.golang/paho"
 	"github.com/iancoleman/strcase"
 	"github.com/pelletier/go-toml/v2"
+	"github.com/reugn/go-quartz/quartz"
+	slogctx "github.com/veqryn/slog-context"
 
 	mqtthass "github.com/joshuar/go-hass-anything/v12/pkg/hass"
 	mqttapi "github.com/joshuar/go-hass-anything/v12/pkg/mqtt"
@@ -26,13 +30,16 @@ import (
 	"github.com/joshuar/go-hass-agent/agent/workers/mqtt"
 	"github.com/joshuar/go-hass-agent/config"
 	"github.com/joshuar/go-hass-agent/models"
+	"github.com/joshuar/go-hass-agent/scheduler"
 )
 
 const (
-	stateValueTemplate = "{{ value_json.value }}"
-	switchOnState      = "ON"
-	switchOffState     = "OFF"
-	commandsFile       = "commands.toml"
+	stateValueTemplate  = "{{ value_json.value }}"
+	binaryValueTemplate = "{{ value }}"
+	defaultSchedule     = "@every 1m"
+	switchOnState       = "ON"
+	switchOffState      = "OFF"
+	commandsFile        = "commands.toml"
 
 	workerID   = "mqtt_commands"
 	workerDesc = "MQTT Commands"
@@ -45,9 +52,12 @@ var (
 	ErrUnknownSwitchState = errors.New("could not determine state of switch")
 	// ErrUnknownNumberState indicates the state of the number could not be determined.
 	ErrUnknownNumberState = errors.New("could not determine state of number")
+	// ErrUnknownSensorState indicates the state of the sensor could not be determined.
+	ErrUnknownSensorState = errors.New("could not determine state of sensor")
 )
 
-// Command represents a Command to run by a button or switch.
+// Command represents a Command to run by a control or to retrieve the state of
+// a sensor.
 type Command struct {
 	// Name is display name for the command.
 	Name string `toml:"name"`
@@ -71,20 +81,63 @@ type Command struct {
 	// Step is the amount to change the value. It is only relevant for certain
 	// types, such as numbers and is ignored if unused.
 	Step any `toml:"step,omitempty"`
+	// Args are templates for the arguments passed to the command of a control,
+	// which can contain a {{value}} placeholder for the value from Home
+	// Assistant. A placeholder can be typed as {{value:int}} or
+	// {{value:float}}, in which case the value must be of that type. If no
+	// args are given, the value is appended to the command-line.
+	Args []string `toml:"args,omitempty"`
+	// Allow is a list of values from Home Assistant that are allowed to be
+	// passed to the command of a control. Switches only allow ON/OFF and
+	// selects only allow their options by default.
+	Allow []string `toml:"allow,omitempty"`
+	// AllowPattern is a regular expression that values from Home Assistant
+	// must fully match to be passed to the command of a control.
+	AllowPattern string `toml:"allow_pattern,omitempty"`
+	// Shell indicates the command should be run as a script by the system
+	// shell. Any value from Home Assistant is passed as an argument to the
+	// script (i.e., "$1"), never as part of the script itself. By default,
+	// commands are run directly, without a shell.
+	Shell bool `toml:"shell,omitempty"`
+	// Timeout is how long the command of a control can run before it is
+	// stopped, as a duration such as "30s". By default, there is no timeout.
+	Timeout string `toml:"timeout,omitempty"`
+	// Result indicates whether the result (output, exit code and duration) of
+	// running the command of a control is published as a sensor.
+	Result bool `toml:"result,omitempty"`
+	// Options are the options that can be chosen for a select.
+	Options []string `toml:"options,omitempty"`
+	// OptionsExec is a binary or script that outputs the options for a select,
+	// one per line. It is used if no options are given.
+	OptionsExec string `toml:"options_exec,omitempty"`
+	// Pattern is a regular expression that the value of a text must match.
+	Pattern string `toml:"pattern,omitempty"`
+	// Schedule is how often the state of a sensor will be updated, as a cron
+	// expression or "@every <duration>". It defaults to every minute.
+	Schedule string `toml:"schedule,omitempty"`
+	// Units are the units of measurement for a sensor.
+	Units string `toml:"units,omitempty"`
+	// StateClass is the state class of a sensor. It should be either
+	// 'measurement', 'total' or 'total_increasing'.
+	StateClass string `toml:"state_class,omitempty"`
 }
 
 // CommandList is a CommandList of all the buttons/commands parsed from the config file.
 //
 //revive:disable:struct-tag
 type CommandList struct {
-	Buttons  []Command `toml:"button,omitempty"`
-	Switches []Command `toml:"switch,omitempty"`
-	Numbers  []Command `toml:"number,omitempty"`
+	Buttons       []Command `toml:"button,omitempty"`
+	Switches      []Command `toml:"switch,omitempty"`
+	Numbers       []Command `toml:"number,omitempty"`
+	Selects       []Command `toml:"select,omitempty"`
+	Texts         []Command `toml:"text,omitempty"`
+	Sensors       []Command `toml:"sensor,omitempty"`
+	BinarySensors []Command `toml:"binary_sensor,omitempty"`
 }
 
-// Worker represents an object with one or more buttons and switches
+// Worker represents an object with one or more control and sensor
 // definitions, which can be passed to Home Assistant to add appropriate
-// entities to control the buttons/switches over MQTT.
+// entities to control the controls and show the sensors over MQTT.
 type Worker struct {
 	*models.WorkerMetadata
 
@@ -93,19 +146,42 @@ type Worker struct {
 	switches     []*mqtthass.SwitchEntity
 	intNumbers   []*mqtthass.NumberEntity[int64]
 	floatNumbers []*mqtthass.NumberEntity[float64]
+	selects      []*selectEntity
+	texts        []*mqtthass.TextEntity
+	sensors      []*commandSensor
+	results      map[string]*mqtthass.SensorEntity
+	resultMsgs   chan mqttapi.Msg
+	msgs         chan mqttapi.Msg
+	ctx          context.Context //nolint:containedctx // used by MQTT message handlers.
+	path         string
+	stopPolling  context.CancelFunc
+	mu           sync.Mutex
 }
 
-// entity is a convienience interface to avoid duplicating a lot of loops when
-// configuring the controller.
+// commandSensor is a sensor or binary sensor entity whose state is updated on
+// a schedule.
+type commandSensor struct {
+	*mqtthass.SensorEntity
+
+	trigger quartz.Trigger
+}
+
+// configEntity is a convienience interface to avoid duplicating a lot of loops
+// when configuring the controller.
+type configEntity interface {
+	MarshalConfig() (*mqttapi.Msg, error)
+}
+
+// entity is a configEntity that can also be controlled.
 type entity interface {
+	configEntity
 	MarshalSubscription() (*mqttapi.Subscription, error)
-	MarshalConfig() (*mqttapi.Msg, error)
 }
 
 // Subscriptions are the MQTT subscriptions for buttons and switches, providing
 // the appropriate callback mechanism to execute the associated commands.
 func (d *Worker) Subscriptions() []*mqttapi.Subscription {
-	total := len(d.buttons) + len(d.switches) + len(d.intNumbers) + len(d.floatNumbers)
+	total := len(d.buttons) + len(d.switches) + len(d.intNumbers) + len(d.floatNumbers) + len(d.selects) + len(d.texts)
 	subs := make([]*mqttapi.Subscription, 0, total)
 
 	// Create subscriptions for buttons.
@@ -124,6 +200,14 @@ func (d *Worker) Subscriptions() []*mqttapi.Subscription {
 	for _, fnum := range d.floatNumbers {
 		subs = append(subs, d.generateSubscriptions(fnum))
 	}
+	// Create subscriptions for selects.
+	for _, sel := range d.selects {
+		subs = append(subs, d.generateSubscriptions(sel))
+	}
+	// Create subscriptions for texts.
+	for _, txt := range d.texts {
+		subs = append(subs, d.generateSubscriptions(txt))
+	}
 
 	return subs
 }
@@ -142,7 +226,8 @@ func (d *Worker) generateSubscriptions(e entity) *mqttapi.Subscription {
 // Configs are the MQTT configurations required by Home Assistant to set up
 // entities for the buttons/switches.
 func (d *Worker) Configs() []*mqttapi.Msg {
-	total := len(d.buttons) + len(d.switches) + len(d.intNumbers) + len(d.floatNumbers)
+	total := len(d.buttons) + len(d.switches) + len(d.intNumbers) + len(d.floatNumbers) +
+		len(d.selects) + len(d.texts) + len(d.sensors) + len(d.results)
 	cfgs := make([]*mqttapi.Msg, 0, total)
 
 	// Create button configs.
@@ -161,11 +246,27 @@ func (d *Worker) Configs() []*mqttapi.Msg {
 	for _, fnum := range d.floatNumbers {
 		cfgs = append(cfgs, d.generateConfigs(fnum))
 	}
+	// Create select configs.
+	for _, sel := range d.selects {
+		cfgs = append(cfgs, d.generateConfigs(sel))
+	}
+	// Create text configs.
+	for _, txt := range d.texts {
+		cfgs = append(cfgs, d.generateConfigs(txt))
+	}
+	// Create sensor configs.
+	for _, sensor := range d.sensors {
+		cfgs = append(cfgs, d.generateConfigs(sensor))
+	}
+	// Create command result configs.
+	for _, result := range d.results {
+		cfgs = append(cfgs, d.generateConfigs(result))
+	}
 
 	return cfgs
 }
 
-func (d *Worker) generateConfigs(e entity) *mqttapi.Msg {
+func (d *Worker) generateConfigs(e configEntity) *mqttapi.Msg {
 	msg, err := e.MarshalConfig()
 	if err != nil {
 		slog.Warn("Could not create entity config.", slog.Any("error", err))
@@ -177,11 +278,9 @@ func (d *Worker) generateConfigs(e entity) *mqttapi.Msg {
 }
 
 // Msgs are additional MQTT messages to be published based on any event logic
-// managed by the controller. This is unused.
-//
-//revive:disable:unused-receiver
+// managed by the controller. These are the sensor state updates.
 func (d *Worker) Msgs() chan mqttapi.Msg {
-	return nil
+	return d.msgs
 }
 
 // generateButtons will create MQTT entities for buttons defined by the
@@ -192,8 +291,19 @@ func (d *Worker) generateButtons(buttonCmds []Command) {
 	entities := make([]*mqtthass.ButtonEntity, 0, len(buttonCmds))
 
 	for _, cmd := range buttonCmds {
+		spec, err := newExecSpec(cmd)
+		if err != nil {
+			slog.Warn("Ignoring invalid button.",
+				slog.String("button", cmd.Name),
+				slog.Any("error", err))
+
+			continue
+		}
+
+		result := d.generateResult(cmd)
+
 		callback := func(_ *paho.Publish) {
-			err := cmdWithoutState(cmd.Exec)
+			err := d.runCommand(spec, result)
 			if err != nil {
 				slog.Warn("Button press failed.",
 					slog.String("button", cmd.Name),
@@ -234,10 +344,21 @@ func (d *Worker) generateSwitches(switchCmds []Command) {
 	entities := make([]*mqtthass.SwitchEntity, 0, len(switchCmds))
 
 	for _, cmd := range switchCmds {
+		spec, err := newExecSpec(cmd, switchOnState, switchOffState)
+		if err != nil {
+			slog.Warn("Ignoring invalid switch.",
+				slog.String("switch", cmd.Name),
+				slog.Any("error", err))
+
+			continue
+		}
+
+		result := d.generateResult(cmd)
+
 		cmdCallBack := func(p *paho.Publish) {
 			state := string(p.Payload)
 
-			err := cmdWithState(cmd.Exec, state)
+			err := d.runCommand(spec, result, state)
 			if err != nil {
 				slog.Warn("Switch toggle failed.",
 					slog.String("switch", cmd.Name),
@@ -290,10 +411,21 @@ func (d *Worker) generateNumbers(numberCommands []Command) {
 	)
 
 	for _, cmd := range numberCommands {
+		spec, err := newExecSpec(cmd)
+		if err != nil {
+			slog.Warn("Ignoring invalid number.",
+				slog.String("number", cmd.Name),
+				slog.Any("error", err))
+
+			continue
+		}
+
+		result := d.generateResult(cmd)
+
 		cmdCallBack := func(p *paho.Publish) {
 			state := string(p.Payload)
 
-			err := cmdWithState(cmd.Exec, state)
+			err := d.runCommand(spec, result, state)
 			if err != nil {
 				slog.Warn("Set number failed.",
 					slog.String("number", cmd.Name),
@@ -352,6 +484,7 @@ func (d *Worker) generateNumbers(numberCommands []Command) {
 			if step == 0 {
 				step = 1
 			}
+			spec.withRange(minValue, maxValue, false)
 
 			floats = append(floats,
 				mqtthass.NewNumberEntity[float64]().
@@ -392,6 +525,7 @@ func (d *Worker) generateNumbers(numberCommands []Command) {
 			if step == 0 {
 				step = 1
 			}
+			spec.withRange(float64(minValue), float64(maxValue), true)
 
 			ints = append(ints,
 				mqtthass.NewNumberEntity[int64]().
@@ -422,11 +556,265 @@ func (d *Worker) generateNumbers(numberCommands []Command) {
 	d.intNumbers = ints
 }
 
-// Start starts the MQTT worker.
-func (d *Worker) Start(_ context.Context) (*mqtt.WorkerData, error) {
+// generateSelects will create MQTT entities for selects defined by the
+// controller. The options are either given directly or generated by running
+// the options command.
+func (d *Worker) generateSelects(selectCmds []Command) {
+	entities := make([]*selectEntity, 0, len(selectCmds))
+
+	for _, cmd := range selectCmds {
+		options := cmd.Options
+		if len(options) == 0 && cmd.OptionsExec != "" {
+			var err error
+
+			options, err = selectOptions(cmd.OptionsExec)
+			if err != nil {
+				slog.Warn("Could not get select options.",
+					slog.String("select", cmd.Name),
+					slog.Any("error", err))
+
+				continue
+			}
+		}
+
+		if len(options) == 0 {
+			slog.Warn("Ignoring select without options.",
+				slog.String("select", cmd.Name))
+
+			continue
+		}
+
+		// Only the options of the select are allowed, unless otherwise specified.
+		spec, err := newExecSpec(cmd, options...)
+		if err != nil {
+			slog.Warn("Ignoring invalid select.",
+				slog.String("select", cmd.Name),
+				slog.Any("error", err))
+
+			continue
+		}
+
+		result := d.generateResult(cmd)
+
+		cmdCallBack := func(p *paho.Publish) {
+			state := string(p.Payload)
+
+			err := d.runCommand(spec, result, state)
+			if err != nil {
+				slog.Warn("Select option failed.",
+					slog.String("select", cmd.Name),
+					slog.Any("error", err))
+			}
+		}
+
+		entities = append(entities,
+			newSelectEntity(
+				mqtthass.NewTextEntity().
+					WithDetails(d.entityDetails(cmd, "mdi:form-dropdown")...).
+					WithCommand(
+						mqtthass.CommandCallback(cmdCallBack),
+					),
+				options))
+	}
+
+	d.selects = entities
+}
+
+// generateTexts will create MQTT entities for texts defined by the controller.
+func (d *Worker) generateTexts(textCmds []Command) {
+	entities := make([]*mqtthass.TextEntity, 0, len(textCmds))
+
+	for _, cmd := range textCmds {
+		spec, err := newExecSpec(cmd)
+		if err != nil {
+			slog.Warn("Ignoring invalid text.",
+				slog.String("text", cmd.Name),
+				slog.Any("error", err))
+
+			continue
+		}
+
+		result := d.generateResult(cmd)
+
+		cmdCallBack := func(p *paho.Publish) {
+			state := string(p.Payload)
+
+			err := d.runCommand(spec, result, state)
+			if err != nil {
+				slog.Warn("Set text failed.",
+					slog.String("text", cmd.Name),
+					slog.Any("error", err))
+			}
+		}
+
+		mode := mqtthass.PlainText
+		if cmd.Display == "password" {
+			mode = mqtthass.Password
+		}
+
+		entity := mqtthass.NewTextEntity().
+			WithDetails(d.entityDetails(cmd, "mdi:form-textbox")...).
+			WithCommand(
+				mqtthass.CommandCallback(cmdCallBack),
+			).
+			WithMode(mode).
+			WithPattern(cmd.Pattern).
+			WithMin(int(convValue[int64](cmd.Min))).
+			WithMax(int(convValue[int64](cmd.Max)))
+
+		entities = append(entities, entity)
+	}
+
+	d.texts = entities
+}
+
+// generateSensors will create MQTT entities for sensors and binary sensors
+// defined by the controller. The state of each sensor is the output of its
+// command, which is run on the sensor's schedule.
+func (d *Worker) generateSensors(sensorCmds, binarySensorCmds []Command) {
+	entities := make([]*commandSensor, 0, len(sensorCmds)+len(binarySensorCmds))
+
+	for _, cmd := range sensorCmds {
+		trigger, err := sensorTrigger(cmd)
+		if err != nil {
+			slog.Warn("Could not schedule sensor.",
+				slog.String("sensor", cmd.Name),
+				slog.Any("error", err))
+
+			continue
+		}
+
+		stateCallBack := func(_ ...any) (json.RawMessage, error) {
+			return sensorState(cmd.Exec)
+		}
+
+		stateOptions := []mqtthass.StateOption{
+			mqtthass.StateCallback(stateCallBack),
+			mqtthass.ValueTemplate(stateValueTemplate),
+			mqtthass.Units(cmd.Units),
+		}
+
+		switch cmd.StateClass {
+		case "measurement":
+			stateOptions = append(stateOptions, mqtthass.StateClassMeasurement())
+		case "total":
+			stateOptions = append(stateOptions, mqtthass.StateClassTotal())
+		case "total_increasing":
+			stateOptions = append(stateOptions, mqtthass.StateClassTotalIncreasing())
+		}
+
+		entities = append(entities, &commandSensor{
+			SensorEntity: mqtthass.NewSensorEntity().
+				WithDetails(d.entityDetails(cmd, "mdi:console")...).
+				WithState(stateOptions...),
+			trigger: trigger,
+		})
+	}
+
+	for _, cmd := range binarySensorCmds {
+		trigger, err := sensorTrigger(cmd)
+		if err != nil {
+			slog.Warn("Could not schedule binary sensor.",
+				slog.String("binary_sensor", cmd.Name),
+				slog.Any("error", err))
+
+			continue
+		}
+
+		stateCallBack := func(_ ...any) (json.RawMessage, error) {
+			return switchState(cmd.Exec)
+		}
+
+		entities = append(entities, &commandSensor{
+			SensorEntity: mqtthass.NewBinarySensorEntity().
+				WithDetails(d.entityDetails(cmd, "mdi:console")...).
+				WithState(
+					mqtthass.StateCallback(stateCallBack),
+					mqtthass.ValueTemplate(binaryValueTemplate),
+				),
+			trigger: trigger,
+		})
+	}
+
+	d.sensors = entities
+}
+
+// entityDetails returns the details for the entity for the given command,
+// using the given icon if the command does not specify one.
+func (d *Worker) entityDetails(cmd Command, icon string) []mqtthass.DetailsOption {
+	if cmd.Icon != "" {
+		icon = cmd.Icon
+	}
+
+	return []mqtthass.DetailsOption{
+		mqtthass.App(config.AppName + "_" + d.device.Name),
+		mqtthass.Name(cmd.Name),
+		mqtthass.ID(strcase.ToSnake(d.device.Name + "_" + cmd.Name)),
+		mqtthass.OriginInfo(mqtt.Origin()),
+		mqtthass.DeviceInfo(d.device),
+		mqtthass.Icon(icon),
+	}
+}
+
+// pollSensor publishes the state of the sensor on its schedule, until the
+// context is canceled.
+func (d *Worker) pollSensor(ctx context.Context, sensor *commandSensor) {
+	for {
+		d.publishState(ctx, sensor)
+
+		next, err := sensor.trigger.NextFireTime(time.Now().UnixNano())
+		if err != nil {
+			slogctx.FromCtx(ctx).Warn("Could not schedule sensor update.",
+				slog.String("sensor", sensor.Name),
+				slog.Any("error", err))
+
+			return
+		}
+
+		timer := time.NewTimer(time.Until(time.Unix(0, next)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// publishState sends the current state of the sensor to be published.
+func (d *Worker) publishState(ctx context.Context, sensor *commandSensor) {
+	msg, err := sensor.MarshalState()
+	if err != nil {
+		slogctx.FromCtx(ctx).Warn("Could not get sensor state.",
+			slog.String("sensor", sensor.Name),
+			slog.Any("error", err))
+
+		return
+	}
+
+	select {
+	case d.msgs <- *msg:
+	case <-ctx.Done():
+	}
+}
+
+// Start starts the MQTT worker. The commands file is watched and reloaded when
+// it changes.
+func (d *Worker) Start(ctx context.Context) (*mqtt.WorkerData, error) {
+	d.ctx = ctx
+	d.msgs = make(chan mqttapi.Msg)
+	d.resultMsgs = make(chan mqttapi.Msg, resultQueueSize)
+
+	go d.publishResults(ctx)
+
+	d.startPolling(ctx)
+
+	go d.watch(ctx)
+
 	return &mqtt.WorkerData{
 		Configs:       d.Configs(),
-		Subscriptions: d.Subscriptions(),
+		Subscriptions: []*mqttapi.Subscription{d.subscription()},
+		Msgs:          d.msgs,
 	}, nil
 }
 
@@ -437,14 +825,25 @@ func (d *Worker) IsDisabled() bool {
 
 // NewCommandsWorker is used by the agent to initialize the commands
 // controller, which holds the MQTT configuration for the commands defined by
-// the user.
+// the user. If the commands file does not exist (yet), the worker has no
+// entities until the file is created.
 func NewCommandsWorker(device *mqtthass.Device) (*Worker, error) {
 	commandsFile := filepath.Join(config.GetPath(), commandsFile)
-	// if _, err := os.Stat(commandsFile); err != nil {
-	// 	return nil, fmt.Errorf("stat commands file: %w", err)
-	// }
 
-	data, err := os.ReadFile(commandsFile) // #nosec: G304
+	cmds, err := loadCommands(commandsFile)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		cmds = &CommandList{}
+	case err != nil:
+		return nil, err
+	}
+
+	return newWorker(device, commandsFile, cmds), nil
+}
+
+// loadCommands reads and parses the given commands file.
+func loadCommands(path string) (*CommandList, error) {
+	data, err := os.ReadFile(path) // #nosec: G304
 	if err != nil {
 		return nil, fmt.Errorf("read commands file: %w", err)
 	}
@@ -455,51 +854,48 @@ func NewCommandsWorker(device *mqtthass.Device) (*Worker, error) {
 		return nil, fmt.Errorf("parse commands file: %w", err)
 	}
 
+	return cmds, nil
+}
+
+// newWorker creates a worker with entities for the given commands.
+func newWorker(device *mqtthass.Device, path string, cmds *CommandList) *Worker {
 	controller := &Worker{
 		WorkerMetadata: models.SetWorkerMetadata(workerID, workerDesc),
 		device:         device,
+		path:           path,
 	}
 	controller.generateButtons(cmds.Buttons)
 	controller.generateSwitches(cmds.Switches)
 	controller.generateNumbers(cmds.Numbers)
+	controller.generateSelects(cmds.Selects)
+	controller.generateTexts(cmds.Texts)
+	controller.generateSensors(cmds.Sensors, cmds.BinarySensors)
+	controller.useCommandTopics()
 
-	return controller, nil
+	return controller
 }
 
-// cmdWithoutState runs the executable associated with a control with no state
-// passed to the command. This is used for controls which do not have a state,
-// like buttons.
-func cmdWithoutState(command string) error {
-	cmdElems := strings.Split(command, " ")
-
-	if len(cmdElems) == 0 {
-		return fmt.Errorf("%w: invalid command-line", ErrCmdFailed)
+// useCommandTopics changes the command topics of all controls to the topic used
+// for receiving commands.
+func (d *Worker) useCommandTopics() {
+	for _, e := range d.buttons {
+		useCommandTopic(e.EntityCommand)
 	}
-
-	_, err := exec.Command(cmdElems[0], cmdElems[1:]...).Output() // #nosec:204
-	if err != nil {
-		return fmt.Errorf("%w: %w", ErrCmdFailed, err)
+	for _, e := range d.switches {
+		useCommandTopic(e.EntityCommand)
 	}
-
-	return nil
-}
-
-// cmdWithState runs the executable associated with a control, passing a state
-// value. This is used by controls with a controllable state in Home Assistant.
-func cmdWithState(command, state string) error {
-	if state == "" {
-		return fmt.Errorf("%w: no state specified", ErrCmdFailed)
+	for _, e := range d.intNumbers {
+		useCommandTopic(e.EntityCommand)
 	}
-
-	cmdElems := strings.Split(command, " ")
-	cmdElems = append(cmdElems, state)
-
-	_, err := exec.Command(cmdElems[0], cmdElems[1:]...).Output() // #nosec:204
-	if err != nil {
-		return fmt.Errorf("%w: %w", ErrCmdFailed, err)
+	for _, e := range d.floatNumbers {
+		useCommandTopic(e.EntityCommand)
+	}
+	for _, e := range d.selects {
+		useCommandTopic(e.EntityCommand)
+	}
+	for _, e := range d.texts {
+		useCommandTopic(e.EntityCommand)
 	}
-
-	return nil
 }
 
 // switchState will execute the command associated with the switch control,
@@ -548,6 +944,66 @@ func numberState(command string) (json.RawMessage, error) {
 	return json.RawMessage(`{ "value": ` + number + ` }`), nil
 }
 
+// sensorState will execute the command associated with the sensor, which
+// should output the current state of the sensor. Numeric output is published as
+// a number, any other output as a string.
+func sensorState(command string) (json.RawMessage, error) {
+	cmdElems := strings.Split(command, " ")
+
+	output, err := exec.Command(cmdElems[0], cmdElems[1:]...).Output() // #nosec:204
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnknownSensorState, err)
+	}
+
+	state := string(bytes.TrimSpace(output))
+
+	var value json.RawMessage
+	if _, err := strconv.ParseFloat(state, 64); err == nil {
+		value = json.RawMessage(state)
+	} else if value, err = json.Marshal(state); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnknownSensorState, err)
+	}
+
+	return json.RawMessage(`{ "value": ` + string(value) + ` }`), nil
+}
+
+// selectOptions will execute the given command, which should output the
+// options for a select, one per line.
+func selectOptions(command string) ([]string, error) {
+	cmdElems := strings.Split(command, " ")
+
+	output, err := exec.Command(cmdElems[0], cmdElems[1:]...).Output() // #nosec:204
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCmdFailed, err)
+	}
+
+	var options []string
+
+	for line := range strings.Lines(string(output)) {
+		if option := strings.TrimSpace(line); option != "" {
+			options = append(options, option)
+		}
+	}
+
+	return options, nil
+}
+
+// sensorTrigger returns the trigger for updating the state of the sensor,
+// based on its schedule.
+func sensorTrigger(cmd Command) (quartz.Trigger, error) {
+	schedule := cmd.Schedule
+	if schedule == "" {
+		schedule = defaultSchedule
+	}
+
+	trigger, err := scheduler.ParseSchedule(schedule)
+	if err != nil {
+		return nil, fmt.Errorf("sensor schedule: %w", err)
+	}
+
+	return trigger, nil
+}
+
 // convValue provides a generic way to either convert to an int/float or just
 // return the default value of that type.
 func convValue[T ~float64 | ~int64](orig any) T {
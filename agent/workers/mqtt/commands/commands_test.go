@@ -5,11 +5,14 @@ package commands
 
 import (
 	"encoding/json"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
 	"github.com/eclipse/paho.golang/paho"
 	"github.com/go-test/deep"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	mqtthass "github.com/joshuar/go-hass-anything/v12/pkg/hass"
@@ -175,6 +178,10 @@ func TestController_Configs(t *testing.T) {
 func TestNewCommandsController(t *testing.T) {
 	mockDevice := &mqtthass.Device{}
 
+	// A directory in place of the commands file cannot be read.
+	unreadableDir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(unreadableDir, commandsFile), 0o700))
+
 	type args struct {
 		device    *mqtthass.Device
 		configDir string
@@ -188,7 +195,11 @@ func TestNewCommandsController(t *testing.T) {
 		{
 			name:    "unreadable commands file",
 			wantErr: true,
-			args:    args{configDir: "testdata/unreadable", device: mockDevice},
+			args:    args{configDir: unreadableDir, device: mockDevice},
+		},
+		{
+			name: "missing commands file",
+			args: args{configDir: "testdata/missing", device: mockDevice},
 		},
 		{
 			name:    "invalid commands file",
@@ -212,92 +223,52 @@ func TestNewCommandsController(t *testing.T) {
 	}
 }
 
-func Test_switchCmd(t *testing.T) {
-	type args struct {
-		command string
-		state   string
-	}
+func TestWorker_runCommand(t *testing.T) {
 	tests := []struct {
 		name    string
-		args    args
+		cmd     Command
+		states  []string
 		wantErr bool
 	}{
 		{
-			name: "successful command",
-			args: args{command: "true", state: "someState"},
+			name: "successful command without state",
+			cmd:  Command{Exec: "true"},
 		},
 		{
-			name:    "unsuccessful command",
-			args:    args{command: "false", state: "someState"},
+			name:    "unsuccessful command without state",
+			cmd:     Command{Exec: "false"},
 			wantErr: true,
 		},
 		{
-			name:    "no state",
-			args:    args{command: "true"},
-			wantErr: true,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if err := cmdWithState(tt.args.command, tt.args.state); (err != nil) != tt.wantErr {
-				t.Errorf("switchCmd() error = %v, wantErr %v", err, tt.wantErr)
-			}
-		})
-	}
-}
-
-func Test_cmdWithoutState(t *testing.T) {
-	type args struct {
-		command string
-	}
-	tests := []struct {
-		name    string
-		args    args
-		wantErr bool
-	}{
-		{
-			name: "successful command",
-			args: args{command: "true"},
+			name:   "successful command with state",
+			cmd:    Command{Exec: "true"},
+			states: []string{"someState"},
 		},
 		{
-			name:    "unsuccessful command",
-			args:    args{command: "false"},
+			name:    "unsuccessful command with state",
+			cmd:     Command{Exec: "false"},
+			states:  []string{"someState"},
 			wantErr: true,
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if err := cmdWithoutState(tt.args.command); (err != nil) != tt.wantErr {
-				t.Errorf("cmdWithoutState() error = %v, wantErr %v", err, tt.wantErr)
-			}
-		})
-	}
-}
-
-func Test_cmdWithState(t *testing.T) {
-	type args struct {
-		command string
-		state   string
-	}
-	tests := []struct {
-		name    string
-		args    args
-		wantErr bool
-	}{
 		{
-			name: "successful command",
-			args: args{command: "true", state: "foo"},
+			name:    "no state",
+			cmd:     Command{Exec: "true"},
+			states:  []string{""},
+			wantErr: true,
 		},
 		{
-			name:    "unsuccessful command",
-			args:    args{command: "false", state: "foo"},
+			name:    "timeout",
+			cmd:     Command{Exec: "sleep 5", Timeout: "100ms"},
 			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := cmdWithState(tt.args.command, tt.args.state); (err != nil) != tt.wantErr {
-				t.Errorf("cmdWithState() error = %v, wantErr %v", err, tt.wantErr)
+			spec, err := newExecSpec(tt.cmd)
+			require.NoError(t, err)
+			d := &Worker{}
+			if err := d.runCommand(spec, nil, tt.states...); (err != nil) != tt.wantErr {
+				t.Errorf("runCommand() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
@@ -402,3 +373,52 @@ func Test_numberState(t *testing.T) {
 		})
 	}
 }
+
+func TestNewCommandsWorker_entities(t *testing.T) {
+	config.SetPath("testdata/valid")
+	worker, err := NewCommandsWorker(&mqtthass.Device{Name: "test"})
+	require.NoError(t, err)
+
+	assert.Len(t, worker.selects, 2)
+	assert.Equal(t, []string{"one", "two"}, worker.selects[1].Options)
+	assert.Len(t, worker.texts, 1)
+	assert.Len(t, worker.sensors, 2)
+	assert.Len(t, worker.Configs(), 9)
+	assert.Len(t, worker.Subscriptions(), 7)
+}
+
+func Test_sensorState(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		want    json.RawMessage
+		wantErr bool
+	}{
+		{
+			name:    "number",
+			command: "echo 42.5",
+			want:    json.RawMessage(`{ "value": 42.5 }`),
+		},
+		{
+			name:    "string",
+			command: `echo some "string"`,
+			want:    json.RawMessage(`{ "value": "some \"string\"" }`),
+		},
+		{
+			name:    "unsuccessful",
+			command: "false",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sensorState(tt.command)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrUnknownSensorState)
+				return
+			}
+			require.NoError(t, err)
+			assert.JSONEq(t, string(tt.want), string(got))
+		})
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/eclipse/paho.golang/paho"
+	"github.com/fsnotify/fsnotify"
+	slogctx "github.com/veqryn/slog-context"
+
+	mqtthass "github.com/joshuar/go-hass-anything/v12/pkg/hass"
+	mqttapi "github.com/joshuar/go-hass-anything/v12/pkg/mqtt"
+
+	"github.com/joshuar/go-hass-agent/config"
+)
+
+const (
+	// commandTopicName is the name of the topic on which the commands of
+	// controls are received. It differs from the "set"/"press" topics used by
+	// other workers, so that the single subscription for all commands does not
+	// overlap with their subscriptions.
+	commandTopicName = "run"
+	// reloadDelay is how long to wait for further changes to the commands
+	// file before reloading it. Editors will often generate several events
+	// when saving a file.
+	reloadDelay = 500 * time.Millisecond
+)
+
+// useCommandTopic changes the command topic of the entity to the topic used
+// for receiving commands.
+func useCommandTopic(cmd *mqtthass.EntityCommand) {
+	if idx := strings.LastIndex(cmd.CommandTopic, "/"); idx >= 0 {
+		cmd.CommandTopic = cmd.CommandTopic[:idx+1] + commandTopicName
+	}
+}
+
+// subscription returns a single subscription for the commands of all
+// controls, which passes commands to the current control with a matching
+// command topic. This allows controls to be added and removed without
+// subscribing again.
+func (d *Worker) subscription() *mqttapi.Subscription {
+	appID := strings.ToLower(strings.ReplaceAll(config.AppName+"_"+d.device.Name, " ", "_"))
+
+	return &mqttapi.Subscription{
+		Topic:    strings.Join([]string{mqtthass.HomeAssistantTopic, "+", appID, "+", commandTopicName}, "/"),
+		Callback: d.dispatch,
+	}
+}
+
+// dispatch passes the command to the control with a matching command topic.
+func (d *Worker) dispatch(p *paho.Publish) {
+	d.mu.Lock()
+	subs := d.Subscriptions()
+	d.mu.Unlock()
+
+	for _, sub := range subs {
+		if sub != nil && sub.Topic == p.Topic {
+			sub.Callback(p)
+
+			return
+		}
+	}
+
+	slog.Debug("No control found for command.",
+		slog.String("topic", p.Topic))
+}
+
+// watch will reload the commands file whenever it changes, until the context
+// is canceled.
+func (d *Worker) watch(ctx context.Context) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		slogctx.FromCtx(ctx).Warn("Could not watch commands file for changes.",
+			slog.Any("error", err))
+
+		return
+	}
+	defer watcher.Close() //nolint:errcheck
+
+	// Watch the directory rather than the file, as editors will often replace
+	// the file when saving it.
+	if err := watcher.Add(filepath.Dir(d.path)); err != nil {
+		slogctx.FromCtx(ctx).Warn("Could not watch commands file for changes.",
+			slog.Any("error", err))
+
+		return
+	}
+
+	reload := time.NewTimer(reloadDelay)
+	reload.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			reload.Stop()
+
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+
+			if filepath.Clean(event.Name) == filepath.Clean(d.path) && !event.Has(fsnotify.Chmod) {
+				reload.Reset(reloadDelay)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+
+			slogctx.FromCtx(ctx).Warn("Error watching commands file.",
+				slog.Any("error", err))
+		case <-reload.C:
+			if err := d.reload(ctx); err != nil {
+				slogctx.FromCtx(ctx).Warn("Could not reload commands file.",
+					slog.Any("error", err))
+			} else {
+				slogctx.FromCtx(ctx).Info("Reloaded commands file.")
+			}
+		}
+	}
+}
+
+// reload reads the commands file again and updates the controls and sensors to
+// match. Configs are published for any new or changed entities and removed for
+// any entities no longer in the file. If the file has been removed, all
+// entities are removed.
+func (d *Worker) reload(ctx context.Context) error {
+	cmds, err := loadCommands(d.path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		cmds = &CommandList{}
+	case err != nil:
+		return err
+	}
+
+	next := newWorker(d.device, d.path, cmds)
+	next.ctx = ctx
+	next.msgs = d.msgs
+	next.resultMsgs = d.resultMsgs
+
+	d.mu.Lock()
+	prevCfgs := d.Configs()
+	d.buttons = next.buttons
+	d.switches = next.switches
+	d.intNumbers = next.intNumbers
+	d.floatNumbers = next.floatNumbers
+	d.selects = next.selects
+	d.texts = next.texts
+	d.sensors = next.sensors
+	d.results = next.results
+	d.mu.Unlock()
+
+	for _, msg := range diffConfigs(prevCfgs, next.Configs()) {
+		select {
+		case d.msgs <- *msg:
+		case <-ctx.Done():
+			return fmt.Errorf("reload commands: %w", ctx.Err())
+		}
+	}
+
+	d.startPolling(ctx)
+
+	return nil
+}
+
+// diffConfigs returns the messages needed to update the previous configs to
+// the next configs: the configs that are new or changed, and empty messages to
+// remove the configs that no longer exist (as done by mqtt.Reset).
+func diffConfigs(prev, next []*mqttapi.Msg) []*mqttapi.Msg {
+	prevByTopic := make(map[string]*mqttapi.Msg, len(prev))
+
+	for _, msg := range prev {
+		if msg != nil {
+			prevByTopic[msg.Topic] = msg
+		}
+	}
+
+	var msgs []*mqttapi.Msg
+
+	for _, msg := range next {
+		if msg == nil {
+			continue
+		}
+
+		if prevMsg, found := prevByTopic[msg.Topic]; !found || !bytes.Equal(prevMsg.Message, msg.Message) {
+			msgs = append(msgs, msg)
+		}
+
+		delete(prevByTopic, msg.Topic)
+	}
+
+	for topic := range prevByTopic {
+		msgs = append(msgs, mqttapi.NewMsg(topic, []byte(``)))
+	}
+
+	return msgs
+}
+
+// startPolling starts updating the state of all sensors, stopping any
+// previous updates.
+func (d *Worker) startPolling(ctx context.Context) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.stopPolling != nil {
+		d.stopPolling()
+	}
+
+	pollCtx, cancelFunc := context.WithCancel(ctx)
+	d.stopPolling = cancelFunc
+
+	for _, sensor := range d.sensors {
+		go d.pollSensor(pollCtx, sensor)
+	}
+}
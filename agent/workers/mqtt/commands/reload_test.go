@@ -0,0 +1,156 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.golang/paho"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mqtthass "github.com/joshuar/go-hass-anything/v12/pkg/hass"
+	mqttapi "github.com/joshuar/go-hass-anything/v12/pkg/mqtt"
+
+	"github.com/joshuar/go-hass-agent/config"
+)
+
+func Test_diffConfigs(t *testing.T) {
+	prev := []*mqttapi.Msg{
+		mqttapi.NewMsg("unchanged/config", []byte(`{"name":"unchanged"}`)),
+		mqttapi.NewMsg("changed/config", []byte(`{"name":"before"}`)),
+		mqttapi.NewMsg("removed/config", []byte(`{"name":"removed"}`)),
+		nil,
+	}
+	next := []*mqttapi.Msg{
+		mqttapi.NewMsg("unchanged/config", []byte(`{"name":"unchanged"}`)),
+		mqttapi.NewMsg("changed/config", []byte(`{"name":"after"}`)),
+		mqttapi.NewMsg("added/config", []byte(`{"name":"added"}`)),
+	}
+
+	got := diffConfigs(prev, next)
+
+	assert.ElementsMatch(t, []*mqttapi.Msg{
+		mqttapi.NewMsg("changed/config", []byte(`{"name":"after"}`)),
+		mqttapi.NewMsg("added/config", []byte(`{"name":"added"}`)),
+		mqttapi.NewMsg("removed/config", []byte(``)),
+	}, got)
+}
+
+func TestWorker_reload(t *testing.T) {
+	dir := t.TempDir()
+	config.SetPath(dir)
+	path := filepath.Join(dir, commandsFile)
+
+	require.NoError(t, os.WriteFile(path, []byte(`
+[[button]]
+name = "kept"
+exec = "true"
+
+[[button]]
+name = "removed"
+exec = "true"
+`), 0o600))
+
+	worker, err := NewCommandsWorker(&mqtthass.Device{Name: "test"})
+	require.NoError(t, err)
+	worker.msgs = make(chan mqttapi.Msg, 10)
+
+	require.NoError(t, os.WriteFile(path, []byte(`
+[[button]]
+name = "kept"
+exec = "true"
+
+[[switch]]
+name = "added"
+exec = "true"
+`), 0o600))
+	require.NoError(t, worker.reload(t.Context()))
+	close(worker.msgs)
+
+	msgs := make(map[string]string)
+	for msg := range worker.msgs {
+		msgs[msg.Topic] = string(msg.Message)
+	}
+
+	assert.Len(t, msgs, 2)
+	assert.Contains(t, msgs["homeassistant/switch/go_hass_agent_test/test_added/config"], `"name":"added"`)
+	assert.Empty(t, msgs["homeassistant/button/go_hass_agent_test/test_removed/config"])
+	assert.Contains(t, msgs, "homeassistant/button/go_hass_agent_test/test_removed/config")
+	assert.Len(t, worker.buttons, 1)
+	assert.Len(t, worker.switches, 1)
+
+	// Removing the file removes all entities.
+	require.NoError(t, os.Remove(path))
+	worker.msgs = make(chan mqttapi.Msg, 10)
+	require.NoError(t, worker.reload(t.Context()))
+	close(worker.msgs)
+	assert.Len(t, worker.msgs, 2)
+	assert.Empty(t, worker.Configs())
+}
+
+func TestWorker_dispatch(t *testing.T) {
+	var pressed string
+
+	worker := &Worker{device: &mqtthass.Device{Name: "test"}}
+	for _, name := range []string{"first", "second"} {
+		button := mqtthass.NewButtonEntity().
+			WithDetails(worker.entityDetails(Command{Name: name}, "mdi:test")...).
+			WithCommand(mqtthass.CommandCallback(func(_ *paho.Publish) { pressed = name }))
+		worker.buttons = append(worker.buttons, button)
+	}
+	worker.useCommandTopics()
+
+	sub := worker.subscription()
+	assert.Equal(t, "homeassistant/+/go_hass_agent_test/+/run", sub.Topic)
+
+	sub.Callback(&paho.Publish{Topic: "homeassistant/button/go_hass_agent_test/test_second/run"})
+	assert.Equal(t, "second", pressed)
+
+	sub.Callback(&paho.Publish{Topic: "homeassistant/button/go_hass_agent_test/test_unknown/run"})
+	assert.Equal(t, "second", pressed)
+}
+
+func TestWorker_reloadWhileRunning(t *testing.T) {
+	dir := t.TempDir()
+	config.SetPath(dir)
+
+	started := filepath.Join(dir, "started")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, commandsFile), []byte(`
+[[button]]
+name = "slow"
+exec = "touch '`+started+`' && sleep 0.2"
+shell = true
+result = true
+`), 0o600))
+
+	worker, err := NewCommandsWorker(&mqtthass.Device{Name: "test"})
+	require.NoError(t, err)
+	require.Len(t, worker.buttons, 1)
+
+	_, err = worker.Start(t.Context())
+	require.NoError(t, err)
+
+	go func() {
+		for range worker.msgs { //nolint:revive // drain published messages.
+		}
+	}()
+
+	// Press the button and reload while its command is running.
+	topic := worker.buttons[0].CommandTopic
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		worker.dispatch(&paho.Publish{Topic: topic})
+	}()
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(started)
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, worker.reload(t.Context()))
+	<-done
+}
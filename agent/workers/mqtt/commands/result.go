@@ -0,0 +1,210 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"time"
+
+	slogctx "github.com/veqryn/slog-context"
+
+	mqtthass "github.com/joshuar/go-hass-anything/v12/pkg/hass"
+
+	"github.com/joshuar/go-hass-agent/agent/workers"
+)
+
+const (
+	// maxOutputSize is the maximum number of bytes of stdout and stderr kept
+	// from a command.
+	maxOutputSize      = 4096
+	resultNameSuffix   = " Result"
+	resultIcon         = "mdi:console-line"
+	resultStateTmpl    = "{{ value_json.exit_code }}"
+	resultTruncatedMsg = "...(truncated)"
+	// resultQueueSize is the number of command results that can be waiting to
+	// be published. Any further results are dropped.
+	resultQueueSize = 10
+	// commandWaitDelay is how long to wait for the output of a stopped command
+	// to be closed, before giving up on it.
+	commandWaitDelay = 5 * time.Second
+)
+
+// commandResult is the result of running the command of a control.
+type commandResult struct {
+	Stdout   string  `json:"stdout"`
+	Stderr   string  `json:"stderr"`
+	Error    string  `json:"error,omitempty"`
+	ExitCode int     `json:"exit_code"`
+	Duration float64 `json:"duration"`
+	TimedOut bool    `json:"timed_out"`
+}
+
+// cappedBuffer is a buffer that discards anything written beyond its limit.
+// The buffer is not embedded, so that its ReadFrom method cannot be used to
+// bypass the limit.
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.limit - b.buf.Len(); len(p) > remaining {
+		b.truncated = true
+		if remaining > 0 {
+			b.buf.Write(p[:remaining])
+		}
+
+		return len(p), nil
+	}
+
+	return b.buf.Write(p) //nolint:wrapcheck
+}
+
+// String returns the contents of the buffer, noting if it was truncated.
+func (b *cappedBuffer) String() string {
+	if b.truncated {
+		return b.buf.String() + resultTruncatedMsg
+	}
+
+	return b.buf.String()
+}
+
+// execCommand runs the given command-line and returns the result. The command
+// is stopped if the context is canceled. A non-nil error is returned if the
+// command could not be run or was not successful.
+func execCommand(ctx context.Context, argv []string) (*commandResult, error) {
+	stdout := &cappedBuffer{limit: maxOutputSize}
+	stderr := &cappedBuffer{limit: maxOutputSize}
+
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...) // #nosec:204
+	// Stop any processes started by the command as well, so that they do not
+	// keep the command running past its timeout by holding its output open.
+	workers.KillProcessGroup(cmd)
+	cmd.WaitDelay = commandWaitDelay
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	start := time.Now()
+	err := cmd.Run()
+
+	result := &commandResult{
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		ExitCode: cmd.ProcessState.ExitCode(),
+		Duration: time.Since(start).Seconds(),
+		TimedOut: errors.Is(ctx.Err(), context.DeadlineExceeded),
+	}
+
+	if err != nil {
+		result.Error = err.Error()
+
+		return result, fmt.Errorf("%w: %w", ErrCmdFailed, err)
+	}
+
+	return result, nil
+}
+
+// runCommand runs the command of a control with the given payloads. If the
+// command publishes its result (i.e., it has a result entity), the result is
+// published whether or not the command was successful. Payloads that are not
+// allowed are rejected without running the command.
+//
+// The spec and result entity are those of the control when it was generated,
+// so they are not affected by the commands file being reloaded while the
+// command runs.
+func (d *Worker) runCommand(spec *execSpec, result *mqtthass.SensorEntity, payloads ...string) error {
+	argv, err := spec.commandLine(payloads...)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancelFunc := spec.context()
+	defer cancelFunc()
+
+	details, err := execCommand(ctx, argv)
+
+	if result != nil {
+		d.publishResult(result, details)
+	}
+
+	return err
+}
+
+// generateResult will create a sensor entity for publishing the result of the
+// command, if requested. It returns the entity, or nil if the result is not
+// published.
+func (d *Worker) generateResult(cmd Command) *mqtthass.SensorEntity {
+	if !cmd.Result {
+		return nil
+	}
+
+	stateCallBack := func(args ...any) (json.RawMessage, error) {
+		return json.Marshal(args[0]) //nolint:wrapcheck
+	}
+
+	entity := mqtthass.NewSensorEntity().
+		WithDetails(d.entityDetails(Command{Name: cmd.Name + resultNameSuffix}, resultIcon)...).
+		WithState(
+			mqtthass.StateCallback(stateCallBack),
+			mqtthass.ValueTemplate(resultStateTmpl),
+		).
+		WithAttributes()
+	// The result is published once as the state, with all its fields as
+	// attributes.
+	entity.AttributesTopic = entity.StateTopic
+
+	if d.results == nil {
+		d.results = make(map[string]*mqtthass.SensorEntity)
+	}
+
+	d.results[cmd.Name] = entity
+
+	return entity
+}
+
+// publishResult queues the command result to be published. As it is called
+// from an MQTT message handler, it does not wait for the result to be sent. If
+// too many results are already waiting to be published (e.g., while
+// disconnected from MQTT), the result is dropped.
+func (d *Worker) publishResult(entity *mqtthass.SensorEntity, result *commandResult) {
+	msg, err := entity.MarshalState(result)
+	if err != nil {
+		slogctx.FromCtx(d.ctx).Warn("Could not publish command result.",
+			slog.String("command", entity.Name),
+			slog.Any("error", err))
+
+		return
+	}
+
+	select {
+	case d.resultMsgs <- *msg:
+	default:
+		slogctx.FromCtx(d.ctx).Warn("Too many command results waiting to be published, dropping result.",
+			slog.String("command", entity.Name))
+	}
+}
+
+// publishResults sends any queued command results to be published, until the
+// context is canceled.
+func (d *Worker) publishResults(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg := <-d.resultMsgs:
+			select {
+			case d.msgs <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
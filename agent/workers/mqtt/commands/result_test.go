@@ -0,0 +1,129 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mqtthass "github.com/joshuar/go-hass-anything/v12/pkg/hass"
+	mqttapi "github.com/joshuar/go-hass-anything/v12/pkg/mqtt"
+)
+
+func Test_execCommand(t *testing.T) {
+	tests := []struct {
+		name       string
+		command    string
+		args       []string
+		timeout    time.Duration
+		wantResult *commandResult
+		wantErr    bool
+	}{
+		{
+			name:       "stdout",
+			command:    "echo hello",
+			args:       []string{"world"},
+			wantResult: &commandResult{Stdout: "hello world\n"},
+		},
+		{
+			name:       "stderr and exit code",
+			command:    "bash -c echo${IFS}oops>&2;exit${IFS}3",
+			wantResult: &commandResult{Stderr: "oops\n", ExitCode: 3, Error: "exit status 3"},
+			wantErr:    true,
+		},
+		{
+			name:       "truncated output",
+			command:    "printf %5000s",
+			wantResult: &commandResult{Stdout: strings.Repeat(" ", maxOutputSize) + resultTruncatedMsg},
+		},
+		{
+			name:       "timeout",
+			command:    "sleep 5",
+			timeout:    100 * time.Millisecond,
+			wantResult: &commandResult{ExitCode: -1, TimedOut: true, Error: "signal: killed"},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+			if tt.timeout > 0 {
+				var cancelFunc context.CancelFunc
+				ctx, cancelFunc = context.WithTimeout(ctx, tt.timeout)
+				defer cancelFunc()
+			}
+			got, err := execCommand(ctx, append(strings.Fields(tt.command), tt.args...))
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrCmdFailed)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.GreaterOrEqual(t, got.Duration, 0.0)
+			got.Duration = 0
+			assert.Equal(t, tt.wantResult, got)
+		})
+	}
+}
+
+func Test_execCommand_childProcess(t *testing.T) {
+	ctx, cancelFunc := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancelFunc()
+
+	// The shell starts sleep as a child process, which holds the output of the
+	// command open. It should be stopped along with the shell.
+	got, err := execCommand(ctx, []string{"sh", "-c", "sleep 30; true"})
+	require.ErrorIs(t, err, ErrCmdFailed)
+	assert.True(t, got.TimedOut)
+	assert.Less(t, got.Duration, 5.0)
+}
+
+func TestWorker_runCommand_result(t *testing.T) {
+	d := &Worker{
+		device:     &mqtthass.Device{Name: "test"},
+		ctx:        t.Context(),
+		msgs:       make(chan mqttapi.Msg),
+		resultMsgs: make(chan mqttapi.Msg, resultQueueSize),
+	}
+	go d.publishResults(t.Context())
+	result := d.generateResult(Command{Name: "say hello", Exec: "echo hello", Result: true})
+	require.Len(t, d.Configs(), 1)
+
+	spec, err := newExecSpec(Command{Name: "say hello", Exec: "echo hello"})
+	require.NoError(t, err)
+	require.NoError(t, d.runCommand(spec, result))
+
+	select {
+	case msg := <-d.msgs:
+		assert.Equal(t, "homeassistant/sensor/go_hass_agent_test/test_say_hello_result/state", msg.Topic)
+		var result commandResult
+		require.NoError(t, json.Unmarshal(msg.Message, &result))
+		assert.Equal(t, "hello\n", result.Stdout)
+		assert.Equal(t, 0, result.ExitCode)
+	case <-time.After(time.Second):
+		t.Fatal("command result not published")
+	}
+}
+
+func TestWorker_publishResult_dropped(t *testing.T) {
+	d := &Worker{
+		device:     &mqtthass.Device{Name: "test"},
+		ctx:        t.Context(),
+		msgs:       make(chan mqttapi.Msg),
+		resultMsgs: make(chan mqttapi.Msg, resultQueueSize),
+	}
+	result := d.generateResult(Command{Name: "say hello", Exec: "echo hello", Result: true})
+
+	// Nothing is publishing results, so any results beyond the queue size should be dropped rather than block.
+	for range resultQueueSize + 5 {
+		d.publishResult(result, &commandResult{})
+	}
+
+	assert.Len(t, d.resultMsgs, resultQueueSize)
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	mqtthass "github.com/joshuar/go-hass-anything/v12/pkg/hass"
+	mqttapi "github.com/joshuar/go-hass-anything/v12/pkg/mqtt"
+)
+
+// ErrNoOptions indicates a select has no options to choose from.
+var ErrNoOptions = errors.New("select has no options")
+
+// selectEntity represents an entity that allows choosing one of a fixed list of options. There is no select entity in
+// go-hass-anything, so it is built on a text entity, which has the same details and command handling, with the topics
+// rewritten for a select. For more details see https://www.home-assistant.io/integrations/select.mqtt/
+type selectEntity struct {
+	*mqtthass.TextEntity
+
+	Options []string `json:"options"`
+}
+
+// newSelectEntity creates a select entity with the given options from the given text entity.
+func newSelectEntity(text *mqtthass.TextEntity, options []string) *selectEntity {
+	if text.EntityCommand != nil {
+		text.CommandTopic = selectTopic(text.CommandTopic)
+	}
+
+	if text.EntityState != nil {
+		text.StateTopic = selectTopic(text.StateTopic)
+	}
+
+	return &selectEntity{
+		TextEntity: text,
+		Options:    options,
+	}
+}
+
+// MarshalConfig generates the config message for the select entity.
+func (e *selectEntity) MarshalConfig() (*mqttapi.Msg, error) {
+	if len(e.Options) == 0 {
+		return nil, fmt.Errorf("entity config is invalid: %w", ErrNoOptions)
+	}
+
+	// Validate the entity and generate the config topic as a text entity.
+	textCfg, err := e.TextEntity.MarshalConfig()
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	cfg, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("marshal config: %w", err)
+	}
+
+	return mqttapi.NewMsg(selectTopic(textCfg.Topic), cfg), nil
+}
+
+// selectTopic converts the given text entity topic into the equivalent select entity topic.
+func selectTopic(topic string) string {
+	return strings.Replace(topic, mqtthass.HomeAssistantTopic+"/text/", mqtthass.HomeAssistantTopic+"/select/", 1)
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package commands
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mqtthass "github.com/joshuar/go-hass-anything/v12/pkg/hass"
+)
+
+func Test_selectEntity_MarshalConfig(t *testing.T) {
+	newText := func() *mqtthass.TextEntity {
+		return mqtthass.NewTextEntity().
+			WithDetails(
+				mqtthass.App("test"),
+				mqtthass.Name("test select"),
+				mqtthass.ID("test_select"),
+				mqtthass.OriginInfo(&mqtthass.Origin{}),
+				mqtthass.DeviceInfo(&mqtthass.Device{}),
+			).
+			WithCommand(
+				mqtthass.CommandCallback(mockCommandCallback),
+			)
+	}
+
+	tests := []struct {
+		name    string
+		options []string
+		wantErr error
+	}{
+		{
+			name:    "with options",
+			options: []string{"a", "b"},
+		},
+		{
+			name:    "without options",
+			wantErr: ErrNoOptions,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entity := newSelectEntity(newText(), tt.options)
+			got, err := entity.MarshalConfig()
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "homeassistant/select/test/test_select/config", got.Topic)
+
+			var cfg map[string]any
+			require.NoError(t, json.Unmarshal(got.Message, &cfg))
+			assert.Equal(t, "homeassistant/select/test/test_select/set", cfg["command_topic"])
+			assert.Equal(t, []any{"a", "b"}, cfg["options"])
+
+			sub, err := entity.MarshalSubscription()
+			require.NoError(t, err)
+			assert.Equal(t, cfg["command_topic"], sub.Topic)
+		})
+	}
+}
@@ -4,9 +4,13 @@
 package mqtt
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"net/url"
+	"os"
+	"slices"
 
 	mqtthass "github.com/joshuar/go-hass-anything/v12/pkg/hass"
 
@@ -22,12 +26,30 @@ const (
 	defaultMQTTServer  = "tcp://localhost:1883"
 )
 
+var (
+	// ErrUnsupportedScheme indicates the scheme of the MQTT server URI is not
+	// supported.
+	ErrUnsupportedScheme = errors.New("unsupported scheme")
+	// ErrNoCertificates indicates no certificates could be loaded from a
+	// certificate file.
+	ErrNoCertificates = errors.New("no certificates found")
+)
+
+// supportedSchemes are the schemes of the MQTT server URI that are supported.
+// The tcp/mqtt schemes are plain MQTT, the ssl/tls/mqtts schemes are MQTT over
+// TLS and the ws/wss schemes are MQTT over (secure) WebSockets.
+var supportedSchemes = []string{"tcp", "mqtt", "ssl", "tls", "mqtts", "ws", "wss"}
+
 // Config represents MQTT preferences.
 type Config struct {
-	MQTTServer      string `toml:"server,omitempty"       form:"mqtt.mqtt_server"       validate:"required_if=MQTTEnabled true,omitempty,uri" kong:"help='MQTT server URI.',placeholder='tcp://some.host:port'"`
+	MQTTServer      string `toml:"server,omitempty"       form:"mqtt.mqtt_server"       validate:"required_if=MQTTEnabled true,omitempty,uri" kong:"help='MQTT server URI (tcp://, ssl://, ws:// or wss://).',placeholder='tcp://some.host:port'"`
 	MQTTUser        string `toml:"user,omitempty"         form:"mqtt.mqtt_user"         validate:"omitempty"                                  kong:"optional,help='MQTT username.'"`
 	MQTTPassword    string `toml:"password,omitempty"     form:"mqtt.mqtt_password"     validate:"omitempty"                                  kong:"optional,help='MQTT password.'"`
 	MQTTTopicPrefix string `toml:"topic_prefix,omitempty" form:"mqtt.mqtt_topic_prefix" validate:"required,ascii"                             kong:"optional,help='MQTT topic prefix.'"`
+	MQTTCACert      string `toml:"ca_cert,omitempty"      form:"mqtt.mqtt_ca_cert"      validate:"omitempty,file"                             kong:"optional,name='mqtt-ca-cert',type='path',help='Path to a CA certificate bundle for verifying the MQTT server.'"`
+	MQTTClientCert  string `toml:"client_cert,omitempty"  form:"mqtt.mqtt_client_cert"  validate:"required_with=MQTTClientKey,omitempty,file"  kong:"optional,name='mqtt-client-cert',type='path',help='Path to a client certificate for authenticating with the MQTT server.'"`
+	MQTTClientKey   string `toml:"client_key,omitempty"   form:"mqtt.mqtt_client_key"   validate:"required_with=MQTTClientCert,omitempty,file" kong:"optional,name='mqtt-client-key',type='path',help='Path to the private key of the client certificate.'"`
+	MQTTInsecure    bool   `toml:"insecure,omitempty"     form:"mqtt.mqtt_insecure"     validate:"boolean"                                    kong:"optional,name='mqtt-insecure',help='Do not verify the MQTT server certificate (insecure).'"`
 	MQTTEnabled     bool   `toml:"enabled"                form:"mqtt.mqtt_enabled"      validate:"boolean"                                    kong:"negatable,help='Enable MQTT features.'"`
 }
 
@@ -52,22 +74,72 @@ func (c *Config) Valid() (bool, error) {
 	if err := validation.ValidateStruct(c); err != nil {
 		return false, fmt.Errorf("validate config: %w", err)
 	}
+	if c.MQTTServer != "" {
+		server, err := url.Parse(c.MQTTServer)
+		if err != nil {
+			return false, fmt.Errorf("validate config: %w", err)
+		}
+		if !slices.Contains(supportedSchemes, server.Scheme) {
+			return false, fmt.Errorf("validate config: %w: %s", ErrUnsupportedScheme, server.Scheme)
+		}
+	}
 	return true, nil
 }
 
+// TLSConfig returns the TLS configuration for connecting to the MQTT server,
+// loading any CA certificates and client certificate. If no TLS preferences
+// are set, a nil config is returned and the system defaults are used.
+func (c *Config) TLSConfig() (*tls.Config, error) {
+	if c.MQTTCACert == "" && c.MQTTClientCert == "" && !c.MQTTInsecure {
+		return nil, nil //nolint:nilnil
+	}
+	tlsCfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: c.MQTTInsecure, // #nosec G402 -- explicitly requested by the user.
+	}
+	if c.MQTTCACert != "" {
+		pem, err := os.ReadFile(c.MQTTCACert)
+		if err != nil {
+			return nil, fmt.Errorf("load CA certificate: %w", err)
+		}
+		tlsCfg.RootCAs = x509.NewCertPool()
+		if !tlsCfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("load CA certificate: %w", ErrNoCertificates)
+		}
+	}
+	if c.MQTTClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(c.MQTTClientCert, c.MQTTClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+	return tlsCfg, nil
+}
+
 // Sanitise will sanitise the values of the MQTT preferences.
 func (c *Config) Sanitise() error {
 	if c == nil {
 		return errors.New("no config found")
 	}
+	if c.MQTTServer == "" {
+		return nil
+	}
 	server, err := url.Parse(c.MQTTServer)
 	if err != nil {
 		return fmt.Errorf("could not sanitise server value: %w", err)
 	}
-	// Set scheme to tcp, a common error is to use http or https.
-	if server.Scheme != "tcp" {
+	// A common error is to use http or https, use the equivalent MQTT scheme
+	// instead.
+	switch server.Scheme {
+	case "http":
 		server.Scheme = "tcp"
+	case "https":
+		server.Scheme = "ssl"
+	default:
+		return nil
 	}
+	c.MQTTServer = server.String()
 	return nil
 }
 
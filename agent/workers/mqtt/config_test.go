@@ -0,0 +1,164 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package mqtt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestCert writes a self-signed certificate and its key to the given
+// directory, returning their paths.
+func writeTestCert(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600))
+
+	return certFile, keyFile
+}
+
+func TestConfig_Valid(t *testing.T) {
+	certFile, keyFile := writeTestCert(t, t.TempDir())
+
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr bool
+	}{
+		{
+			name:   "tcp",
+			config: Config{MQTTServer: "tcp://localhost:1883", MQTTTopicPrefix: DefaultTopicPrefix, MQTTEnabled: true},
+		},
+		{
+			name:   "ssl",
+			config: Config{MQTTServer: "ssl://localhost:8883", MQTTTopicPrefix: DefaultTopicPrefix, MQTTEnabled: true},
+		},
+		{
+			name:   "websockets",
+			config: Config{MQTTServer: "wss://localhost/mqtt", MQTTTopicPrefix: DefaultTopicPrefix, MQTTEnabled: true},
+		},
+		{
+			name:    "unsupported scheme",
+			config:  Config{MQTTServer: "http://localhost:1883", MQTTTopicPrefix: DefaultTopicPrefix, MQTTEnabled: true},
+			wantErr: true,
+		},
+		{
+			name: "client certificate",
+			config: Config{
+				MQTTServer: "ssl://localhost:8883", MQTTTopicPrefix: DefaultTopicPrefix, MQTTEnabled: true,
+				MQTTCACert: certFile, MQTTClientCert: certFile, MQTTClientKey: keyFile,
+			},
+		},
+		{
+			name: "client certificate without key",
+			config: Config{
+				MQTTServer: "ssl://localhost:8883", MQTTTopicPrefix: DefaultTopicPrefix, MQTTEnabled: true,
+				MQTTClientCert: certFile,
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing CA certificate",
+			config: Config{
+				MQTTServer: "ssl://localhost:8883", MQTTTopicPrefix: DefaultTopicPrefix, MQTTEnabled: true,
+				MQTTCACert: filepath.Join(t.TempDir(), "missing.pem"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.config.Valid()
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.False(t, got)
+			} else {
+				require.NoError(t, err)
+				assert.True(t, got)
+			}
+		})
+	}
+}
+
+func TestConfig_Sanitise(t *testing.T) {
+	tests := []struct {
+		server string
+		want   string
+	}{
+		{server: "http://localhost:1883", want: "tcp://localhost:1883"},
+		{server: "https://localhost:8883", want: "ssl://localhost:8883"},
+		{server: "wss://localhost/mqtt", want: "wss://localhost/mqtt"},
+		{server: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.server, func(t *testing.T) {
+			config := &Config{MQTTServer: tt.server}
+			require.NoError(t, config.Sanitise())
+			assert.Equal(t, tt.want, config.MQTTServer)
+		})
+	}
+}
+
+func TestConfig_TLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeTestCert(t, dir)
+	notCert := filepath.Join(dir, "empty.pem")
+	require.NoError(t, os.WriteFile(notCert, []byte("not a certificate"), 0o600))
+
+	t.Run("no tls preferences", func(t *testing.T) {
+		tlsCfg, err := (&Config{}).TLSConfig()
+		require.NoError(t, err)
+		assert.Nil(t, tlsCfg)
+	})
+	t.Run("insecure", func(t *testing.T) {
+		tlsCfg, err := (&Config{MQTTInsecure: true}).TLSConfig()
+		require.NoError(t, err)
+		assert.True(t, tlsCfg.InsecureSkipVerify)
+	})
+	t.Run("certificates", func(t *testing.T) {
+		tlsCfg, err := (&Config{MQTTCACert: certFile, MQTTClientCert: certFile, MQTTClientKey: keyFile}).TLSConfig()
+		require.NoError(t, err)
+		assert.NotNil(t, tlsCfg.RootCAs)
+		assert.Len(t, tlsCfg.Certificates, 1)
+		assert.False(t, tlsCfg.InsecureSkipVerify)
+	})
+	t.Run("invalid CA certificate", func(t *testing.T) {
+		_, err := (&Config{MQTTCACert: notCert}).TLSConfig()
+		require.ErrorIs(t, err, ErrNoCertificates)
+	})
+	t.Run("invalid client key", func(t *testing.T) {
+		_, err := (&Config{MQTTClientCert: certFile, MQTTClientKey: notCert}).TLSConfig()
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,185 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package mqtt
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	mqtthass "github.com/joshuar/go-hass-anything/v12/pkg/hass"
+	mqttapi "github.com/joshuar/go-hass-anything/v12/pkg/mqtt"
+
+	"github.com/joshuar/go-hass-agent/models"
+)
+
+const (
+	// EventsAsTriggers publishes events as device triggers.
+	EventsAsTriggers EventMode = "trigger"
+	// EventsAsEntities publishes events as event entities.
+	EventsAsEntities EventMode = "event"
+	// EventsAsBoth publishes events as both device triggers and event
+	// entities.
+	EventsAsBoth EventMode = "both"
+	// EventsDisabled does not publish events over MQTT.
+	EventsDisabled EventMode = "none"
+
+	deviceAutomationType = "device_automation"
+	eventEntityType      = "event"
+	triggerSubtype       = "event"
+	eventIcon            = "mdi:bell-ring"
+	eventTypeField       = "event_type"
+)
+
+// knownEvents are the types of events generated by the agent. They are
+// published when the sink starts. Other event types are published when they
+// first occur.
+var knownEvents = []string{"session_started", "session_stopped", "oom_event"}
+
+// EventMode is how events are published over MQTT.
+type EventMode string
+
+// Valid returns whether the event mode is a known mode.
+func (m EventMode) Valid() bool {
+	switch m {
+	case EventsAsTriggers, EventsAsEntities, EventsAsBoth, EventsDisabled:
+		return true
+	default:
+		return false
+	}
+}
+
+// Triggers returns whether events are published as device triggers.
+func (m EventMode) Triggers() bool {
+	return m == EventsAsTriggers || m == EventsAsBoth
+}
+
+// Entities returns whether events are published as event entities.
+func (m EventMode) Entities() bool {
+	return m == EventsAsEntities || m == EventsAsBoth
+}
+
+// deviceTrigger is the config of an MQTT device trigger. For more details, see
+// https://www.home-assistant.io/integrations/device_trigger.mqtt/
+type deviceTrigger struct {
+	Device         *mqtthass.Device `json:"device"`
+	Origin         *mqtthass.Origin `json:"origin,omitempty"`
+	AutomationType string           `json:"automation_type"`
+	Topic          string           `json:"topic"`
+	Type           string           `json:"type"`
+	Subtype        string           `json:"subtype"`
+}
+
+// eventEntity is the config of an MQTT event entity. For more details, see
+// https://www.home-assistant.io/integrations/event.mqtt/
+type eventEntity struct {
+	Device     *mqtthass.Device `json:"device"`
+	Origin     *mqtthass.Origin `json:"origin,omitempty"`
+	Name       string           `json:"name"`
+	UniqueID   string           `json:"unique_id"`
+	Icon       string           `json:"icon,omitempty"`
+	StateTopic string           `json:"state_topic"`
+	EventTypes []string         `json:"event_types"`
+}
+
+// eventID returns the id of the trigger and entity for the given event type.
+func (s *EntitySink) eventID(eventType string) string {
+	return strings.ToLower(strings.ReplaceAll(s.device.Name+"_"+eventType, " ", "_"))
+}
+
+// eventConfigs returns the config messages for the trigger and/or entity of
+// the given event type, if they have not already been published.
+func (s *EntitySink) eventConfigs(eventType string) []*models.MQTTMsg {
+	if s.eventTypes[eventType] {
+		return nil
+	}
+
+	s.eventTypes[eventType] = true
+
+	id := s.eventID(eventType)
+
+	var msgs []*models.MQTTMsg
+
+	if s.eventMode.Triggers() {
+		cfg, _ := json.Marshal(&deviceTrigger{ //nolint:errchkjson
+			Device:         s.device,
+			Origin:         Origin(),
+			AutomationType: "trigger",
+			Topic:          s.topic(deviceAutomationType, id, "trigger"),
+			Type:           eventType,
+			Subtype:        triggerSubtype,
+		})
+		msgs = append(msgs, mqttapi.NewMsg(s.topic(deviceAutomationType, id, "config"), cfg))
+	}
+
+	if s.eventMode.Entities() {
+		cfg, _ := json.Marshal(&eventEntity{ //nolint:errchkjson
+			Device:     s.device,
+			Origin:     Origin(),
+			Name:       eventName(eventType),
+			UniqueID:   id,
+			Icon:       eventIcon,
+			StateTopic: s.topic(eventEntityType, id, "state"),
+			EventTypes: []string{eventType},
+		})
+		msgs = append(msgs, mqttapi.NewMsg(s.topic(eventEntityType, id, "config"), cfg))
+	}
+
+	return msgs
+}
+
+// eventMessages returns the messages to publish for the given event: the
+// configs for the event type if it has not been seen before, then the event
+// data to fire the trigger and/or entity.
+func (s *EntitySink) eventMessages(event *models.Event) ([]*models.MQTTMsg, error) {
+	if s.eventMode == EventsDisabled {
+		return nil, nil
+	}
+
+	msgs := s.eventConfigs(event.Type)
+	id := s.eventID(event.Type)
+
+	if s.eventMode.Triggers() {
+		payload, err := json.Marshal(event.Data)
+		if err != nil {
+			return nil, fmt.Errorf("marshal event data: %w", err)
+		}
+
+		msgs = append(msgs, mqttapi.NewMsg(s.topic(deviceAutomationType, id, "trigger"), payload))
+	}
+
+	if s.eventMode.Entities() {
+		// The event type must be included in the payload of an event entity.
+		data := maps.Clone(event.Data)
+		if data == nil {
+			data = make(map[string]any)
+		}
+
+		data[eventTypeField] = event.Type
+
+		payload, err := json.Marshal(data)
+		if err != nil {
+			return nil, fmt.Errorf("marshal event data: %w", err)
+		}
+
+		msgs = append(msgs, mqttapi.NewMsg(s.topic(eventEntityType, id, "state"), payload))
+	}
+
+	return msgs, nil
+}
+
+// eventName returns a human-friendly name for the given event type, e.g.
+// "session_started" becomes "Session Started".
+func eventName(eventType string) string {
+	words := strings.Fields(strings.ReplaceAll(eventType, "_", " "))
+	for idx, word := range words {
+		first, size := utf8.DecodeRuneInString(word)
+		words[idx] = string(unicode.ToUpper(first)) + word[size:]
+	}
+
+	return strings.Join(words, " ")
+}
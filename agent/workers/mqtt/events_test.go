@@ -0,0 +1,120 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package mqtt
+
+import (
+	"encoding/json"
+	"testing"
+
+	mqtthass "github.com/joshuar/go-hass-anything/v12/pkg/hass"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshuar/go-hass-agent/models"
+)
+
+func TestEntitySink_eventMessages(t *testing.T) {
+	const (
+		triggerTopic = "homeassistant/device_automation/go_hass_agent_test/test_session_started/trigger"
+		stateTopic   = "homeassistant/event/go_hass_agent_test/test_session_started/state"
+	)
+
+	tests := []struct {
+		name       string
+		mode       EventMode
+		wantTopics []string
+	}{
+		{
+			name: "triggers",
+			mode: EventsAsTriggers,
+			wantTopics: []string{
+				"homeassistant/device_automation/go_hass_agent_test/test_session_started/config",
+				triggerTopic,
+			},
+		},
+		{
+			name: "entities",
+			mode: EventsAsEntities,
+			wantTopics: []string{
+				"homeassistant/event/go_hass_agent_test/test_session_started/config",
+				stateTopic,
+			},
+		},
+		{
+			name: "both",
+			mode: EventsAsBoth,
+			wantTopics: []string{
+				"homeassistant/device_automation/go_hass_agent_test/test_session_started/config",
+				"homeassistant/event/go_hass_agent_test/test_session_started/config",
+				triggerTopic,
+				stateTopic,
+			},
+		},
+		{
+			name: "disabled",
+			mode: EventsDisabled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := NewEntitySink(&mqtthass.Device{Name: "test", Identifiers: []string{"test", "abc"}}, tt.mode)
+			event := &models.Event{Type: "session_started", Data: map[string]any{"user": "test"}}
+
+			msgs, err := sink.eventMessages(event)
+			require.NoError(t, err)
+
+			var topics []string
+			for _, msg := range msgs {
+				topics = append(topics, msg.Topic)
+
+				switch msg.Topic {
+				case triggerTopic:
+					assert.JSONEq(t, `{"user":"test"}`, string(msg.Message))
+				case stateTopic:
+					assert.JSONEq(t, `{"user":"test","event_type":"session_started"}`, string(msg.Message))
+				}
+			}
+			assert.Equal(t, tt.wantTopics, topics)
+			// The event data is not modified.
+			assert.NotContains(t, event.Data, eventTypeField)
+
+			// The configs are only published for the first event of a type.
+			msgs, err = sink.eventMessages(event)
+			require.NoError(t, err)
+			for _, msg := range msgs {
+				assert.NotContains(t, msg.Topic, "/config")
+			}
+		})
+	}
+}
+
+func TestEntitySink_eventConfigs(t *testing.T) {
+	sink := NewEntitySink(&mqtthass.Device{Name: "test", Identifiers: []string{"test", "abc"}}, EventsAsBoth)
+
+	msgs := sink.eventConfigs("oom_event")
+	require.Len(t, msgs, 2)
+
+	var trigger map[string]any
+	require.NoError(t, json.Unmarshal(msgs[0].Message, &trigger))
+	assert.Equal(t, "trigger", trigger["automation_type"])
+	assert.Equal(t, "oom_event", trigger["type"])
+	assert.Equal(t, triggerSubtype, trigger["subtype"])
+	assert.Equal(t, "homeassistant/device_automation/go_hass_agent_test/test_oom_event/trigger", trigger["topic"])
+	assert.NotNil(t, trigger["device"])
+
+	var entity map[string]any
+	require.NoError(t, json.Unmarshal(msgs[1].Message, &entity))
+	assert.Equal(t, "Oom Event", entity["name"])
+	assert.Equal(t, "test_oom_event", entity["unique_id"])
+	assert.Equal(t, []any{"oom_event"}, entity["event_types"])
+	assert.Equal(t, "homeassistant/event/go_hass_agent_test/test_oom_event/state", entity["state_topic"])
+
+	assert.Empty(t, sink.eventConfigs("oom_event"))
+}
+
+func Test_eventName(t *testing.T) {
+	assert.Equal(t, "Session Started", eventName("session_started"))
+	assert.Equal(t, "Oom Event", eventName("oom_event"))
+	assert.Equal(t, "Custom", eventName("custom"))
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
 
 	mqttapi "github.com/joshuar/go-hass-anything/v12/pkg/mqtt"
 	slogctx "github.com/veqryn/slog-context"
@@ -23,10 +24,12 @@ type WorkerData struct {
 	Msgs          <-chan models.MQTTMsg
 }
 
-// Start will connect to MQTT, publish worker configs and subscriptions, then
-// start a goroutine to listen for messages from workers to publish through the
-// client. If the client connection fails, a non-nil error is returned.
-func Start(ctx context.Context, data *WorkerData) error {
+// Start will connect to MQTT, publish the availability of the device, worker
+// configs and subscriptions, then start a goroutine to listen for messages from
+// workers and availability changes to publish through the client. The device
+// is marked offline when the context is canceled or the connection is lost. If
+// the client connection fails, a non-nil error is returned.
+func Start(ctx context.Context, data *WorkerData, availability *Availability) error {
 	// Load the mqtt config.
 	var mqttcfg Config
 	if err := config.Load(ConfigPrefix, &mqttcfg); err != nil {
@@ -35,22 +38,36 @@ func Start(ctx context.Context, data *WorkerData) error {
 	if ok, err := mqttcfg.Valid(); err != nil || !ok {
 		return fmt.Errorf("load mqtt config: %w", err)
 	}
+	deviceID, err := config.Get[string]("device.id")
+	if err != nil {
+		return fmt.Errorf("unable to start MQTT: %w", err)
+	}
 	// Create a new connection to the MQTT broker, publish subscriptions and
 	// configs.
-	client, err := mqttapi.NewClient(ctx, &mqttcfg, data.Subscriptions, data.Configs)
+	client, err := newClient(ctx, &mqttcfg, clientID(deviceID), deviceID, StatusOnline, data.Subscriptions)
 	if err != nil {
 		return fmt.Errorf("could not start MQTT client: %w", err)
 	}
-	// Listen for worker MQTT messages and publish them through the client.
+	if err := client.Publish(ctx, data.Configs...); err != nil {
+		slogctx.FromCtx(ctx).Warn("Unable to publish configs to MQTT.",
+			slog.Any("error", err))
+	}
+	// Listen for worker MQTT messages and availability changes and publish them
+	// through the client.
 	go func() {
+		defer client.Close()
 		for {
 			select {
 			case msg := <-data.Msgs:
 				if err := client.Publish(ctx, &msg); err != nil {
 					slogctx.FromCtx(ctx).Warn("Unable to publish message to MQTT.",
 						slog.String("topic", msg.Topic),
-						slog.Any("msg", msg.Message))
+						slog.Any("error", err))
 				}
+			case status := <-availability.Updates():
+				slogctx.FromCtx(ctx).Debug("Device availability changed.",
+					slog.String("status", status))
+				client.SetStatus(ctx, status)
 			case <-ctx.Done():
 				slogctx.FromCtx(ctx).Debug("Stopped listening for messages to publish to MQTT.")
 				return
@@ -61,21 +78,65 @@ func Start(ctx context.Context, data *WorkerData) error {
 	return nil
 }
 
-// Reset will connect to MQTT and unpublish worker configs. If there is an
-// problem, a non-nil error is returned.
+// Reset will connect to MQTT and unpublish worker configs, the configs of any
+// sensors and events published by the entity sink and the availability of the
+// device. If there is an problem, a non-nil error is returned.
 func Reset(ctx context.Context, configs []*models.MQTTConfig) error {
 	// Load the mqtt config.
 	var mqttcfg Config
 	if err := config.Load(ConfigPrefix, &mqttcfg); err != nil {
 		return fmt.Errorf("could not reset MQTT preferences: %w", err)
 	}
-	client, err := mqttapi.NewClient(ctx, &mqttcfg, nil, nil)
+	deviceID, err := config.Get[string]("device.id")
+	if err != nil {
+		return fmt.Errorf("could not reset MQTT preferences: %w", err)
+	}
+	sinkConfigs, err := entitySinkConfigs()
 	if err != nil {
 		return fmt.Errorf("could not reset MQTT preferences: %w", err)
 	}
+	configs = append(slices.Clone(configs), sinkConfigs...)
+	// Use a different client id, so as not to disconnect any running agent.
+	client, err := newClient(ctx, &mqttcfg, clientID(deviceID)+"_reset", deviceID, "", nil)
+	if err != nil {
+		return fmt.Errorf("could not reset MQTT preferences: %w", err)
+	}
+	defer client.disconnect(ctx)
 	if err := client.Unpublish(ctx, configs...); err != nil {
 		return fmt.Errorf("could not reset MQTT preferences: %w", err)
 	}
+	// Clear the retained availability status.
+	if err := client.publish(ctx, mqttapi.NewMsg(client.availabilityTopic, []byte(``)).Retain()); err != nil {
+		return fmt.Errorf("could not reset MQTT preferences: %w", err)
+	}
+	if err := clearPublished(); err != nil {
+		return fmt.Errorf("could not reset MQTT preferences: %w", err)
+	}
 
 	return nil
 }
+
+// entitySinkConfigs returns the configs published by the entity sink: those
+// recorded as published and those of the known events in every event mode.
+func entitySinkConfigs() ([]*models.MQTTConfig, error) {
+	device, err := Device()
+	if err != nil {
+		return nil, err
+	}
+	sink := NewEntitySink(device, EventsAsBoth)
+
+	var configs []*models.MQTTConfig
+	for _, eventType := range knownEvents {
+		configs = append(configs, sink.eventConfigs(eventType)...)
+	}
+
+	topics, err := recordedPublished()
+	if err != nil {
+		return nil, err
+	}
+	for _, topic := range topics {
+		configs = append(configs, mqttapi.NewMsg(topic, []byte(``)))
+	}
+
+	return configs, nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package mqtt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/joshuar/go-hass-agent/config"
+)
+
+const (
+	// publishedFile is the file (in the config directory) recording the
+	// config topics published by the entity sink.
+	publishedFile      = "mqtt_published.json"
+	publishedFilePerms = 0o600
+)
+
+// publishedMu serializes access to the published file.
+var publishedMu sync.Mutex
+
+// publishedPath returns the path of the file recording the config topics
+// published by the entity sink.
+func publishedPath() string {
+	return filepath.Join(config.GetPath(), publishedFile)
+}
+
+// recordPublished adds the given config topics to the topics recorded as
+// published. The sensors and events published by the entity sink are only known
+// while the agent is running, so their config topics are recorded for Reset to
+// remove.
+func recordPublished(topics ...string) error {
+	publishedMu.Lock()
+	defer publishedMu.Unlock()
+
+	recorded, err := readPublished()
+	if err != nil {
+		return err
+	}
+
+	for _, topic := range topics {
+		if !slices.Contains(recorded, topic) {
+			recorded = append(recorded, topic)
+		}
+	}
+
+	data, err := json.Marshal(recorded)
+	if err != nil {
+		return fmt.Errorf("record published configs: %w", err)
+	}
+
+	if err := os.WriteFile(publishedPath(), data, publishedFilePerms); err != nil {
+		return fmt.Errorf("record published configs: %w", err)
+	}
+
+	return nil
+}
+
+// recordedPublished returns the config topics recorded as published.
+func recordedPublished() ([]string, error) {
+	publishedMu.Lock()
+	defer publishedMu.Unlock()
+
+	return readPublished()
+}
+
+// clearPublished removes the record of published config topics.
+func clearPublished() error {
+	publishedMu.Lock()
+	defer publishedMu.Unlock()
+
+	if err := os.Remove(publishedPath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("clear published configs: %w", err)
+	}
+
+	return nil
+}
+
+// readPublished reads the recorded config topics. If none have been recorded,
+// an empty list is returned.
+func readPublished() ([]string, error) {
+	data, err := os.ReadFile(publishedPath())
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("read published configs: %w", err)
+	}
+
+	var topics []string
+	if err := json.Unmarshal(data, &topics); err != nil {
+		return nil, fmt.Errorf("read published configs: %w", err)
+	}
+
+	return topics, nil
+}
+
+// isConfigTopic returns whether the topic is a discovery config topic.
+func isConfigTopic(topic string) bool {
+	return strings.HasSuffix(topic, "/"+configTopicName)
+}
@@ -0,0 +1,41 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package mqtt
+
+import (
+	"testing"
+
+	mqtthass "github.com/joshuar/go-hass-anything/v12/pkg/hass"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshuar/go-hass-agent/config"
+	"github.com/joshuar/go-hass-agent/models"
+)
+
+func TestEntitySink_recordsPublished(t *testing.T) {
+	config.SetPath(t.TempDir())
+
+	ctx := t.Context()
+	sink := NewEntitySink(&mqtthass.Device{Name: "test"}, EventsAsTriggers)
+
+	entityCh := make(chan models.Entity, 2)
+	entityCh <- models.NewSensor(ctx, models.WithName("Test"), models.WithID("test"), models.WithState(1))
+	entityCh <- models.NewSensor(ctx, models.WithName("Test"), models.WithID("test"), models.WithState(2))
+	close(entityCh)
+
+	for range sink.Run(ctx, entityCh) {
+	}
+
+	topics, err := recordedPublished()
+	require.NoError(t, err)
+	// The configs of the known events and the sensor are recorded, once each.
+	assert.Len(t, topics, len(knownEvents)+1)
+	assert.Contains(t, topics, "homeassistant/sensor/go_hass_agent_test/test_test/config")
+
+	require.NoError(t, clearPublished())
+	topics, err = recordedPublished()
+	require.NoError(t, err)
+	assert.Empty(t, topics)
+}
@@ -0,0 +1,146 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package mqtt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	mqtthass "github.com/joshuar/go-hass-anything/v12/pkg/hass"
+	mqttapi "github.com/joshuar/go-hass-anything/v12/pkg/mqtt"
+
+	"github.com/joshuar/go-hass-agent/config"
+	"github.com/joshuar/go-hass-agent/models"
+)
+
+const (
+	sensorStateTmpl       = "{{ value_json.value }}"
+	binarySensorStateTmpl = "{{ 'ON' if value_json.value else 'OFF' }}"
+)
+
+// sensorEntity is an MQTT sensor entity for a sensor from an entity worker.
+// The device class is set here rather than on the entity state, as the entity
+// state of go-hass-anything does not marshal the device class correctly.
+type sensorEntity struct {
+	*mqtthass.SensorEntity
+
+	DeviceClass string `json:"device_class,omitempty"`
+}
+
+// MarshalConfig generates the config message for the sensor entity.
+func (e *sensorEntity) MarshalConfig() (*mqttapi.Msg, error) {
+	// Validate the entity and generate the config topic with go-hass-anything.
+	sensorCfg, err := e.SensorEntity.MarshalConfig()
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	cfg, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("marshal config: %w", err)
+	}
+
+	return mqttapi.NewMsg(sensorCfg.Topic, cfg), nil
+}
+
+// sensorMessages returns the messages to publish for the given sensor. The
+// config of the sensor is only included when it is first seen or whenever it
+// changes (e.g., its icon).
+func (s *EntitySink) sensorMessages(sensor *models.Sensor) ([]*models.MQTTMsg, error) {
+	entity := s.newSensorEntity(sensor)
+
+	var msgs []*models.MQTTMsg
+
+	cfg, err := entity.MarshalConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if prev, found := s.configs[sensor.UniqueID]; !found || !bytes.Equal(prev, cfg.Message) {
+		s.configs[sensor.UniqueID] = cfg.Message
+		msgs = append(msgs, cfg)
+	}
+
+	state, err := entity.MarshalState(sensor.State)
+	if err != nil {
+		return nil, fmt.Errorf("marshal state: %w", err)
+	}
+	// States are retained, so that Home Assistant has the current state after a
+	// restart, even for sensors that are updated infrequently.
+	msgs = append(msgs, state.Retain())
+
+	if entity.EntityAttributes != nil {
+		attributes, err := entity.MarshalAttributes(sensor.Attributes)
+		if err != nil {
+			return nil, fmt.Errorf("marshal attributes: %w", err)
+		}
+
+		msgs = append(msgs, attributes.Retain())
+	}
+
+	return msgs, nil
+}
+
+// newSensorEntity maps the given sensor to an MQTT sensor entity.
+func (s *EntitySink) newSensorEntity(sensor *models.Sensor) *sensorEntity {
+	details := []mqtthass.DetailsOption{
+		mqtthass.App(config.AppName + "_" + s.device.Name),
+		mqtthass.Name(sensor.Name),
+		mqtthass.ID(s.device.Name + "_" + sensor.UniqueID),
+		mqtthass.OriginInfo(Origin()),
+		mqtthass.DeviceInfo(s.device),
+	}
+	if sensor.Icon != "" {
+		details = append(details, mqtthass.Icon(sensor.Icon))
+	}
+	if sensor.EntityCategory == models.EntityCategoryDiagnostic {
+		details = append(details, mqtthass.AsDiagnostic())
+	}
+	if sensor.Disabled {
+		details = append(details, mqtthass.NotEnabledByDefault())
+	}
+
+	stateOptions := []mqtthass.StateOption{
+		mqtthass.StateCallback(marshalSensorState),
+	}
+
+	var entity *mqtthass.SensorEntity
+
+	if sensor.Type == models.SensorTypeBinarySensor {
+		stateOptions = append(stateOptions, mqtthass.ValueTemplate(binarySensorStateTmpl))
+		entity = mqtthass.NewBinarySensorEntity().
+			WithDetails(details...).
+			WithState(stateOptions...)
+	} else {
+		stateOptions = append(stateOptions, mqtthass.ValueTemplate(sensorStateTmpl))
+		if sensor.UnitOfMeasurement != "" {
+			stateOptions = append(stateOptions, mqtthass.Units(sensor.UnitOfMeasurement))
+		}
+		entity = mqtthass.NewSensorEntity().
+			WithDetails(details...).
+			WithState(stateOptions...)
+		entity.StateClass = sensor.StateClass
+	}
+
+	if len(sensor.Attributes) > 0 {
+		entity = entity.WithAttributes(mqtthass.AttributesCallback(marshalSensorAttributes))
+	}
+
+	return &sensorEntity{
+		SensorEntity: entity,
+		DeviceClass:  sensor.DeviceClass,
+	}
+}
+
+// marshalSensorState marshals the state of a sensor, as a JSON object with
+// the state as its value.
+func marshalSensorState(args ...any) (json.RawMessage, error) {
+	return json.Marshal(map[string]any{"value": args[0]}) //nolint:wrapcheck
+}
+
+// marshalSensorAttributes marshals the attributes of a sensor.
+func marshalSensorAttributes(args ...any) (json.RawMessage, error) {
+	return json.Marshal(args[0]) //nolint:wrapcheck
+}
@@ -0,0 +1,90 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package mqtt
+
+import (
+	"encoding/json"
+	"testing"
+
+	mqtthass "github.com/joshuar/go-hass-anything/v12/pkg/hass"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshuar/go-hass-agent/models"
+)
+
+func TestEntitySink_sensorMessages(t *testing.T) {
+	ctx := t.Context()
+	sink := NewEntitySink(&mqtthass.Device{Name: "test", Identifiers: []string{"test", "abc"}}, EventsDisabled)
+
+	entity := models.NewSensor(ctx,
+		models.WithName("Battery Level"),
+		models.WithID("battery_level"),
+		models.WithDeviceClass(models.SensorClassBattery),
+		models.WithStateClass(models.StateMeasurement),
+		models.WithUnits("%"),
+		models.WithIcon("mdi:battery"),
+		models.AsDiagnostic(),
+		models.WithAttribute("source", "test"),
+		models.WithState(50),
+	)
+	sensor, err := entity.AsSensor()
+	require.NoError(t, err)
+
+	msgs, err := sink.sensorMessages(&sensor)
+	require.NoError(t, err)
+	require.Len(t, msgs, 3)
+
+	// Config.
+	assert.Equal(t, "homeassistant/sensor/go_hass_agent_test/test_battery_level/config", msgs[0].Topic)
+	var cfg map[string]any
+	require.NoError(t, json.Unmarshal(msgs[0].Message, &cfg))
+	assert.Equal(t, "battery", cfg["device_class"])
+	assert.Equal(t, "measurement", cfg["state_class"])
+	assert.Equal(t, "%", cfg["unit_of_measurement"])
+	assert.Equal(t, "mdi:battery", cfg["icon"])
+	assert.Equal(t, "diagnostic", cfg["entity_category"])
+	assert.Equal(t, "Battery Level", cfg["name"])
+	assert.Equal(t, msgs[1].Topic, cfg["state_topic"])
+	assert.Equal(t, msgs[2].Topic, cfg["json_attributes_topic"])
+	// State.
+	assert.JSONEq(t, `{"value":50}`, string(msgs[1].Message))
+	assert.True(t, msgs[1].Retained)
+	// Attributes.
+	assert.JSONEq(t, `{"source":"test"}`, string(msgs[2].Message))
+
+	// The config is only published again if it changes.
+	msgs, err = sink.sensorMessages(&sensor)
+	require.NoError(t, err)
+	assert.Len(t, msgs, 2)
+
+	sensor.Icon = "mdi:battery-low"
+	msgs, err = sink.sensorMessages(&sensor)
+	require.NoError(t, err)
+	assert.Len(t, msgs, 3)
+}
+
+func TestEntitySink_binarySensor(t *testing.T) {
+	ctx := t.Context()
+	sink := NewEntitySink(&mqtthass.Device{Name: "test", Identifiers: []string{"test", "abc"}}, EventsDisabled)
+
+	entity := models.NewSensor(ctx,
+		models.WithName("Screen Lock"),
+		models.WithID("screen_lock"),
+		models.AsTypeBinarySensor(),
+		models.WithState(true),
+	)
+	sensor, err := entity.AsSensor()
+	require.NoError(t, err)
+
+	msgs, err := sink.sensorMessages(&sensor)
+	require.NoError(t, err)
+	require.Len(t, msgs, 2)
+	assert.Equal(t, "homeassistant/binary_sensor/go_hass_agent_test/test_screen_lock/config", msgs[0].Topic)
+	var cfg map[string]any
+	require.NoError(t, json.Unmarshal(msgs[0].Message, &cfg))
+	assert.Equal(t, binarySensorStateTmpl, cfg["value_template"])
+	assert.NotContains(t, cfg, "state_class")
+	assert.JSONEq(t, `{"value":true}`, string(msgs[1].Message))
+}
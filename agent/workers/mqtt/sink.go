@@ -0,0 +1,143 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package mqtt
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	mqtthass "github.com/joshuar/go-hass-anything/v12/pkg/hass"
+	slogctx "github.com/veqryn/slog-context"
+
+	"github.com/joshuar/go-hass-agent/config"
+	"github.com/joshuar/go-hass-agent/models"
+)
+
+// EntitySink publishes the sensors and events of entity workers over MQTT, as
+// an alternative or in addition to sending them through the mobile_app
+// webhook. Sensors are published as MQTT sensors and events as device
+// triggers and/or event entities, depending on the event mode.
+type EntitySink struct {
+	device     *mqtthass.Device
+	eventMode  EventMode
+	configs    map[string][]byte
+	eventTypes map[string]bool
+	published  map[string]bool
+}
+
+// NewEntitySink creates a new sink that publishes sensors and events as
+// entities of the given device. Events are published according to the given
+// event mode.
+func NewEntitySink(device *mqtthass.Device, eventMode EventMode) *EntitySink {
+	return &EntitySink{
+		device:     device,
+		eventMode:  eventMode,
+		configs:    make(map[string][]byte),
+		eventTypes: make(map[string]bool),
+		published:  make(map[string]bool),
+	}
+}
+
+// Run will publish the sensors and events received on the given channel,
+// until the channel is closed or the context is canceled. The messages to
+// publish are sent on the returned channel.
+func (s *EntitySink) Run(ctx context.Context, entityCh <-chan models.Entity) <-chan models.MQTTMsg {
+	msgCh := make(chan models.MQTTMsg)
+
+	go func() {
+		defer close(msgCh)
+
+		send := func(msgs []*models.MQTTMsg) bool {
+			s.recordConfigs(ctx, msgs)
+
+			for _, msg := range msgs {
+				select {
+				case msgCh <- *msg:
+				case <-ctx.Done():
+					return false
+				}
+			}
+
+			return true
+		}
+
+		// Publish the configs for the known events, so that they can be used in
+		// automations before they first occur.
+		for _, eventType := range knownEvents {
+			if !send(s.eventConfigs(eventType)) {
+				return
+			}
+		}
+
+		for entity := range entityCh {
+			var (
+				msgs []*models.MQTTMsg
+				err  error
+			)
+
+			switch {
+			case entity.IsEvent():
+				event, _ := entity.AsEvent() //nolint:errcheck // checked by IsEvent.
+				msgs, err = s.eventMessages(&event)
+			case entity.IsSensor():
+				sensor, _ := entity.AsSensor() //nolint:errcheck // checked by IsSensor.
+				msgs, err = s.sensorMessages(&sensor)
+			default:
+				continue
+			}
+
+			if err != nil {
+				slogctx.FromCtx(ctx).Warn("Unable to publish entity over MQTT.",
+					slog.Any("error", err))
+
+				continue
+			}
+
+			if !send(msgs) {
+				return
+			}
+		}
+	}()
+
+	return msgCh
+}
+
+// recordConfigs records the topics of any configs in the given messages that
+// have not been published before, so that Reset can remove them.
+func (s *EntitySink) recordConfigs(ctx context.Context, msgs []*models.MQTTMsg) {
+	var topics []string
+
+	for _, msg := range msgs {
+		if isConfigTopic(msg.Topic) && len(msg.Message) > 0 && !s.published[msg.Topic] {
+			topics = append(topics, msg.Topic)
+		}
+	}
+
+	if len(topics) == 0 {
+		return
+	}
+
+	if err := recordPublished(topics...); err != nil {
+		slogctx.FromCtx(ctx).Warn("Unable to record published MQTT configs.",
+			slog.Any("error", err))
+
+		return
+	}
+
+	for _, topic := range topics {
+		s.published[topic] = true
+	}
+}
+
+// appID returns the app id used in the topics of the entities of the sink.
+func (s *EntitySink) appID() string {
+	return strings.ToLower(strings.ReplaceAll(config.AppName+"_"+s.device.Name, " ", "_"))
+}
+
+// topic returns a topic for an entity of the sink, following the same scheme
+// as go-hass-anything.
+func (s *EntitySink) topic(entityType, id, name string) string {
+	return strings.Join([]string{mqtthass.HomeAssistantTopic, entityType, s.appID(), id, name}, "/")
+}
@@ -0,0 +1,444 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package workers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/iancoleman/strcase"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/model"
+	"github.com/reugn/go-quartz/quartz"
+	slogctx "github.com/veqryn/slog-context"
+
+	"github.com/joshuar/go-hass-agent/models"
+	"github.com/joshuar/go-hass-agent/scheduler"
+)
+
+const (
+	prometheusPollInterval   = time.Minute
+	prometheusJitterAmount   = 5 * time.Second
+	prometheusRequestTimeout = 10 * time.Second
+
+	prometheusWorkerID          = "prometheus"
+	prometheusWorkerDesc        = "Sensors from Prometheus exporters"
+	prometheusWorkerPreferences = "sensors.agent.prometheus"
+
+	// prometheusTextfileExt is the extension of files read from a textfile
+	// directory, as used by the node_exporter textfile collector.
+	prometheusTextfileExt = ".prom"
+	// prometheusAcceptHeader requests the Prometheus text format from
+	// exporters that support other formats.
+	prometheusAcceptHeader = "text/plain;version=0.0.4;q=1,*/*;q=0.1"
+)
+
+var (
+	_ quartz.Job          = (*PrometheusWorker)(nil)
+	_ PollingEntityWorker = (*PrometheusWorker)(nil)
+)
+
+// ErrScrapeTarget is returned when a Prometheus target could not be scraped.
+var ErrScrapeTarget = errors.New("could not scrape target")
+
+// prometheusUnits maps the unit suffix of a metric name to its units and
+// device class.
+var prometheusUnits = map[string]struct {
+	units       string
+	deviceClass models.SensorDeviceClass
+}{
+	"celsius": {units: "°C", deviceClass: models.SensorClassTemperature},
+	"bytes":   {units: "B", deviceClass: models.SensorClassDataSize},
+	"seconds": {units: "s", deviceClass: models.SensorClassDuration},
+	"volts":   {units: "V", deviceClass: models.SensorClassVoltage},
+	"amperes": {units: "A", deviceClass: models.SensorClassCurrent},
+	"watts":   {units: "W", deviceClass: models.SensorClassPower},
+	"joules":  {units: "J"},
+	"hertz":   {units: "Hz", deviceClass: models.SensorClassFrequency},
+	"percent": {units: "%"},
+}
+
+// PrometheusPrefs are the preferences for the Prometheus worker.
+type PrometheusPrefs struct {
+	CommonWorkerPrefs `toml:",squash"`
+
+	// UpdateInterval is how often the targets are scraped.
+	UpdateInterval string `toml:"update_interval"`
+	// Targets are the exporters or textfiles to scrape.
+	Targets []PrometheusTarget `toml:"targets"`
+}
+
+// PrometheusTarget is an exporter endpoint or textfile directory to scrape.
+type PrometheusTarget struct {
+	// URL is the URL of an exporter endpoint, such as
+	// http://localhost:9100/metrics.
+	URL string `toml:"url,omitempty"`
+	// Path is a file, or a directory of *.prom files, containing metrics in
+	// the Prometheus text format.
+	Path string `toml:"path,omitempty"`
+	// Metrics are the metrics to turn into sensors.
+	Metrics []PrometheusMetric `toml:"metrics"`
+}
+
+// PrometheusMetric selects the series of a target to turn into sensors and
+// describes those sensors.
+type PrometheusMetric struct {
+	// Match is a Prometheus-style selector for the series, such as
+	// node_hwmon_temp_celsius{chip="platform_coretemp_0"}.
+	Match string `toml:"match"`
+	// SensorName is the name of the sensor. If the selector matches more than
+	// one series, the values of any labels not matched exactly are appended.
+	// If not set, the metric name is used.
+	SensorName string `toml:"sensor_name,omitempty"`
+	// SensorID is the ID of the sensor. If the selector matches more than one
+	// series, the values of any labels not matched exactly are appended. If not
+	// set, it is derived from the sensor name.
+	SensorID          string `toml:"sensor_id,omitempty"`
+	SensorIcon        string `toml:"sensor_icon,omitempty"`
+	SensorDeviceClass string `toml:"sensor_device_class,omitempty"`
+	SensorStateClass  string `toml:"sensor_state_class,omitempty"`
+	SensorUnits       string `toml:"sensor_units,omitempty"`
+	// Scale is a factor that values are multiplied by (e.g., 100 to convert a
+	// ratio to a percentage).
+	Scale float64 `toml:"scale,omitempty"`
+
+	selector *metricSelector
+}
+
+// PrometheusWorker is a polling worker that scrapes metrics from Prometheus
+// exporters and textfiles and turns them into sensors.
+type PrometheusWorker struct {
+	*PollingEntityWorkerData
+	*models.WorkerMetadata
+
+	client *resty.Client
+	prefs  *PrometheusPrefs
+}
+
+// NewPrometheusWorker creates a new worker for sensors from Prometheus
+// exporters.
+func NewPrometheusWorker(_ context.Context) (EntityWorker, error) {
+	worker := &PrometheusWorker{
+		WorkerMetadata:          models.SetWorkerMetadata(prometheusWorkerID, prometheusWorkerDesc),
+		PollingEntityWorkerData: &PollingEntityWorkerData{},
+		client: resty.New().
+			SetTimeout(prometheusRequestTimeout).
+			SetHeader("Accept", prometheusAcceptHeader),
+	}
+
+	defaultPrefs := &PrometheusPrefs{
+		UpdateInterval: prometheusPollInterval.String(),
+	}
+
+	var err error
+
+	worker.prefs, err = LoadWorkerPreferences(prometheusWorkerPreferences, defaultPrefs)
+	if err != nil {
+		return worker, fmt.Errorf("could not load prometheus worker preferences: %w", err)
+	}
+
+	for _, target := range worker.prefs.Targets {
+		for idx := range target.Metrics {
+			target.Metrics[idx].selector, err = parseSelector(target.Metrics[idx].Match)
+			if err != nil {
+				return worker, fmt.Errorf("could not load prometheus worker preferences: %w", err)
+			}
+		}
+	}
+
+	pollInterval, err := time.ParseDuration(worker.prefs.UpdateInterval)
+	if err != nil {
+		pollInterval = prometheusPollInterval
+	}
+
+	worker.Trigger = scheduler.NewPollTriggerWithJitter(pollInterval, prometheusJitterAmount)
+
+	return worker, nil
+}
+
+// IsDisabled returns whether the worker is disabled. The worker is also
+// disabled if there are no targets to scrape.
+func (w *PrometheusWorker) IsDisabled() bool {
+	return w.prefs.IsDisabled() || len(w.prefs.Targets) == 0
+}
+
+// Start schedules the scraping of the targets.
+func (w *PrometheusWorker) Start(ctx context.Context) (<-chan models.Entity, error) {
+	w.OutCh = make(chan models.Entity)
+	if err := SchedulePollingWorker(ctx, w, w.OutCh); err != nil {
+		close(w.OutCh)
+		return w.OutCh, fmt.Errorf("could not start prometheus worker: %w", err)
+	}
+
+	return w.OutCh, nil
+}
+
+// Execute scrapes each target and sends a sensor for each selected series.
+// Targets that cannot be scraped are skipped.
+func (w *PrometheusWorker) Execute(ctx context.Context) error {
+	var errs error
+
+	for _, target := range w.prefs.Targets {
+		families, err := w.scrape(ctx, &target)
+		if err != nil {
+			slogctx.FromCtx(ctx).Debug("Could not scrape Prometheus target.",
+				slog.String("target", target.String()),
+				slog.Any("error", err))
+
+			errs = errors.Join(errs, err)
+
+			continue
+		}
+
+		for _, entity := range target.entities(ctx, families) {
+			w.OutCh <- entity
+		}
+	}
+
+	return errs
+}
+
+// scrape fetches and parses the metric families of the given target.
+func (w *PrometheusWorker) scrape(ctx context.Context, target *PrometheusTarget) ([]*dto.MetricFamily, error) {
+	var sources [][]byte
+
+	switch {
+	case target.URL != "":
+		resp, err := w.client.R().SetContext(ctx).Get(target.URL)
+		if err != nil {
+			return nil, fmt.Errorf("%w %s: %w", ErrScrapeTarget, target, err)
+		}
+
+		if resp.IsError() {
+			return nil, fmt.Errorf("%w %s: %s", ErrScrapeTarget, target, resp.Status())
+		}
+
+		sources = append(sources, resp.Body())
+	case target.Path != "":
+		files, err := textfiles(target.Path)
+		if err != nil {
+			return nil, fmt.Errorf("%w %s: %w", ErrScrapeTarget, target, err)
+		}
+
+		for _, file := range files {
+			data, err := os.ReadFile(file) // #nosec: G304
+			if err != nil {
+				return nil, fmt.Errorf("%w %s: %w", ErrScrapeTarget, target, err)
+			}
+
+			sources = append(sources, data)
+		}
+	default:
+		return nil, fmt.Errorf("%w: no url or path", ErrScrapeTarget)
+	}
+
+	var families []*dto.MetricFamily
+
+	for _, source := range sources {
+		parsed, err := parseMetricFamilies(bytes.NewReader(source))
+		if err != nil {
+			return nil, fmt.Errorf("%w %s: %w", ErrScrapeTarget, target, err)
+		}
+
+		families = append(families, parsed...)
+	}
+
+	return families, nil
+}
+
+// String returns the URL or path of the target.
+func (t PrometheusTarget) String() string {
+	if t.URL != "" {
+		return t.URL
+	}
+
+	return t.Path
+}
+
+// entities returns the sensors for the series in the given metric families
+// that are selected by the metrics of the target.
+func (t *PrometheusTarget) entities(ctx context.Context, families []*dto.MetricFamily) []models.Entity {
+	var entities []models.Entity
+
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			value, ok := metricValue(family.GetType(), metric)
+			if !ok {
+				continue
+			}
+
+			labels := make(map[string]string, len(metric.GetLabel())+1)
+			labels[metricNameLabel] = family.GetName()
+
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+
+			for idx := range t.Metrics {
+				if t.Metrics[idx].selector.matches(labels) {
+					entities = append(entities, t.Metrics[idx].entity(ctx, family.GetType(), labels, value))
+				}
+			}
+		}
+	}
+
+	return entities
+}
+
+// entity returns the sensor for the series with the given type, labels and
+// value.
+func (m *PrometheusMetric) entity(ctx context.Context, metricType dto.MetricType, labels map[string]string, value float64) models.Entity {
+	name := labels[metricNameLabel]
+
+	sensor := ScriptSensor{
+		SensorName:        m.SensorName,
+		SensorIcon:        m.SensorIcon,
+		SensorDeviceClass: m.SensorDeviceClass,
+		SensorStateClass:  m.SensorStateClass,
+		SensorUnits:       m.SensorUnits,
+		SensorAttributes:  map[string]any{"metric": name},
+	}
+
+	if sensor.SensorName == "" {
+		sensor.SensorName = name
+	}
+
+	if sensor.SensorIcon == "" {
+		sensor.SensorIcon = "mdi:chart-line"
+	}
+
+	// Distinguish the series matched by the selector by the values of any labels
+	// that were not matched exactly.
+	var suffix []string
+
+	for _, label := range slices.Sorted(maps.Keys(labels)) {
+		if label != metricNameLabel {
+			sensor.SensorAttributes[label] = labels[label]
+		}
+
+		if !m.selector.fixed(label) && labels[label] != "" {
+			suffix = append(suffix, labels[label])
+		}
+	}
+
+	if len(suffix) > 0 {
+		sensor.SensorName += " " + strings.Join(suffix, " ")
+	}
+
+	if m.SensorID != "" {
+		sensor.SensorID = strcase.ToSnake(strings.Join(append([]string{m.SensorID}, suffix...), "_"))
+	} else {
+		sensor.SensorID = "prometheus_" + strcase.ToSnake(sensor.SensorName)
+	}
+
+	// Derive the units, device class and state class from the metric if they
+	// are not set.
+	if unit, found := prometheusUnits[metricUnit(name)]; found {
+		if sensor.SensorUnits == "" {
+			sensor.SensorUnits = unit.units
+		}
+
+		if sensor.SensorDeviceClass == "" && unit.deviceClass != 0 {
+			sensor.SensorDeviceClass = unit.deviceClass.String()
+		}
+	}
+
+	if sensor.SensorStateClass == "" {
+		if metricType == dto.MetricType_COUNTER {
+			sensor.SensorStateClass = models.StateTotalIncreasing.String()
+		} else {
+			sensor.SensorStateClass = models.StateMeasurement.String()
+		}
+	}
+
+	if m.Scale != 0 {
+		value *= m.Scale
+	}
+
+	sensor.SensorState = value
+
+	return scriptToEntity(ctx, sensor)
+}
+
+// metricUnit returns the unit suffix of the given metric name, ignoring any
+// _total suffix of counters.
+func metricUnit(name string) string {
+	name = strings.TrimSuffix(name, "_total")
+
+	if idx := strings.LastIndex(name, "_"); idx >= 0 {
+		return name[idx+1:]
+	}
+
+	return ""
+}
+
+// metricValue returns the value of the given metric of the given type. Only
+// counters, gauges and untyped metrics with a finite value are supported.
+func metricValue(metricType dto.MetricType, metric *dto.Metric) (float64, bool) {
+	var value float64
+
+	switch metricType {
+	case dto.MetricType_COUNTER:
+		value = metric.GetCounter().GetValue()
+	case dto.MetricType_GAUGE:
+		value = metric.GetGauge().GetValue()
+	case dto.MetricType_UNTYPED:
+		value = metric.GetUntyped().GetValue()
+	default:
+		return 0, false
+	}
+
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, false
+	}
+
+	return value, true
+}
+
+// parseMetricFamilies parses metric families in the Prometheus text format.
+func parseMetricFamilies(in io.Reader) ([]*dto.MetricFamily, error) {
+	parser := expfmt.NewTextParser(model.LegacyValidation)
+
+	families, err := parser.TextToMetricFamilies(in)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse metrics: %w", err)
+	}
+
+	return slices.SortedFunc(maps.Values(families), func(a, b *dto.MetricFamily) int {
+		return strings.Compare(a.GetName(), b.GetName())
+	}), nil
+}
+
+// textfiles returns the given path if it is a file, or the *.prom files in it
+// if it is a directory.
+func textfiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read textfile path: %w", err)
+	}
+
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(path, "*"+prometheusTextfileExt))
+	if err != nil {
+		return nil, fmt.Errorf("could not search for textfiles: %w", err)
+	}
+
+	return files, nil
+}
@@ -0,0 +1,162 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package workers
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// metricNameLabel is the label holding the metric name of a series.
+const metricNameLabel = "__name__"
+
+// ErrInvalidSelector is returned when a metric selector cannot be parsed.
+var ErrInvalidSelector = errors.New("invalid metric selector")
+
+// labelMatcher matches the value of a single label of a series.
+type labelMatcher struct {
+	re    *regexp.Regexp
+	name  string
+	value string
+	op    string
+}
+
+// matches returns whether the given label value is matched. A label that is
+// not present has an empty value.
+func (m *labelMatcher) matches(value string) bool {
+	switch m.op {
+	case "=":
+		return value == m.value
+	case "!=":
+		return value != m.value
+	case "=~":
+		return m.re.MatchString(value)
+	default: // "!~"
+		return !m.re.MatchString(value)
+	}
+}
+
+// metricSelector selects series by metric name and labels, using the same
+// syntax as a Prometheus instant vector selector, for example:
+//
+//	node_hwmon_temp_celsius{chip=~"platform_coretemp_.*",sensor!="temp1"}
+type metricSelector struct {
+	matchers []labelMatcher
+}
+
+// parseSelector parses the given Prometheus-style metric selector.
+//
+//nolint:cyclop
+func parseSelector(selector string) (*metricSelector, error) {
+	selector = strings.TrimSpace(selector)
+	sel := &metricSelector{}
+
+	name, rest, hasLabels := strings.Cut(selector, "{")
+	if name = strings.TrimSpace(name); name != "" {
+		sel.matchers = append(sel.matchers, labelMatcher{name: metricNameLabel, op: "=", value: name})
+	}
+
+	if hasLabels {
+		rest = strings.TrimSpace(rest)
+		if !strings.HasSuffix(rest, "}") {
+			return nil, fmt.Errorf("%w: %q: missing closing brace", ErrInvalidSelector, selector)
+		}
+
+		rest = strings.TrimSuffix(rest, "}")
+
+		for rest = strings.TrimSpace(rest); rest != ""; rest = strings.TrimSpace(rest) {
+			matcher, remaining, err := parseMatcher(rest)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q: %w", ErrInvalidSelector, selector, err)
+			}
+
+			sel.matchers = append(sel.matchers, matcher)
+
+			rest = strings.TrimSpace(remaining)
+			if rest != "" {
+				if rest, hasLabels = strings.CutPrefix(rest, ","); !hasLabels {
+					return nil, fmt.Errorf("%w: %q: expected comma after matcher", ErrInvalidSelector, selector)
+				}
+			}
+		}
+	}
+
+	if len(sel.matchers) == 0 {
+		return nil, fmt.Errorf("%w: %q: no metric name or labels", ErrInvalidSelector, selector)
+	}
+
+	return sel, nil
+}
+
+// parseMatcher parses a single label matcher at the start of the given string
+// and returns it along with the remainder of the string.
+func parseMatcher(str string) (labelMatcher, string, error) {
+	var matcher labelMatcher
+
+	opIdx := strings.IndexAny(str, "=!")
+	if opIdx <= 0 {
+		return matcher, "", errors.New("expected label name and operator")
+	}
+
+	matcher.name = strings.TrimSpace(str[:opIdx])
+	str = str[opIdx:]
+
+	for _, op := range []string{"=~", "!~", "!=", "="} {
+		if strings.HasPrefix(str, op) {
+			matcher.op = op
+			str = strings.TrimSpace(str[len(op):])
+
+			break
+		}
+	}
+
+	if matcher.op == "" {
+		return matcher, "", fmt.Errorf("invalid operator for label %s", matcher.name)
+	}
+
+	quoted, err := strconv.QuotedPrefix(str)
+	if err != nil {
+		return matcher, "", fmt.Errorf("invalid value for label %s: %w", matcher.name, err)
+	}
+
+	if matcher.value, err = strconv.Unquote(quoted); err != nil {
+		return matcher, "", fmt.Errorf("invalid value for label %s: %w", matcher.name, err)
+	}
+
+	if matcher.op == "=~" || matcher.op == "!~" {
+		// Like Prometheus, regular expressions must match the whole value.
+		if matcher.re, err = regexp.Compile("^(?:" + matcher.value + ")$"); err != nil {
+			return matcher, "", fmt.Errorf("invalid regular expression for label %s: %w", matcher.name, err)
+		}
+	}
+
+	return matcher, str[len(quoted):], nil
+}
+
+// matches returns whether the series with the given labels (including the
+// metric name label) is selected.
+func (s *metricSelector) matches(labels map[string]string) bool {
+	for idx := range s.matchers {
+		if !s.matchers[idx].matches(labels[s.matchers[idx].name]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// fixed returns whether the value of the given label is fixed by the selector
+// (i.e., it is matched exactly).
+func (s *metricSelector) fixed(label string) bool {
+	for idx := range s.matchers {
+		if s.matchers[idx].name == label && s.matchers[idx].op == "=" {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,240 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package workers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshuar/go-hass-agent/models"
+)
+
+var testMetrics = `# HELP node_hwmon_temp_celsius Hardware monitor for temperature (input)
+# TYPE node_hwmon_temp_celsius gauge
+node_hwmon_temp_celsius{chip="platform_coretemp_0",sensor="temp1"} 45
+node_hwmon_temp_celsius{chip="platform_coretemp_0",sensor="temp2"} 43.5
+node_hwmon_temp_celsius{chip="thermal_thermal_zone0",sensor="temp0"} 50
+# HELP node_network_receive_bytes_total Network device statistic receive_bytes.
+# TYPE node_network_receive_bytes_total counter
+node_network_receive_bytes_total{device="eth0"} 1024
+node_network_receive_bytes_total{device="lo"} 2048
+# HELP backup_last_success_ratio Ratio of successful backups.
+# TYPE backup_last_success_ratio gauge
+backup_last_success_ratio NaN
+# HELP app_requests Requests handled.
+app_requests 12
+`
+
+func Test_parseSelector(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector string
+		labels   map[string]string
+		want     bool
+		wantErr  bool
+	}{
+		{
+			name:     "name only",
+			selector: "node_load1",
+			labels:   map[string]string{metricNameLabel: "node_load1"},
+			want:     true,
+		},
+		{
+			name:     "name mismatch",
+			selector: "node_load1",
+			labels:   map[string]string{metricNameLabel: "node_load5"},
+		},
+		{
+			name:     "all operators",
+			selector: `node_hwmon_temp_celsius{chip="platform_coretemp_0", sensor=~"temp[0-9]", sensor!="temp2",label!~"x.*"}`,
+			labels:   map[string]string{metricNameLabel: "node_hwmon_temp_celsius", "chip": "platform_coretemp_0", "sensor": "temp1"},
+			want:     true,
+		},
+		{
+			name:     "regex must match whole value",
+			selector: `node_hwmon_temp_celsius{sensor=~"temp"}`,
+			labels:   map[string]string{metricNameLabel: "node_hwmon_temp_celsius", "sensor": "temp1"},
+		},
+		{
+			name:     "name as label",
+			selector: `{__name__=~"node_load.*"}`,
+			labels:   map[string]string{metricNameLabel: "node_load15"},
+			want:     true,
+		},
+		{
+			name:     "escaped value",
+			selector: `app_info{version="\"1.0\""}`,
+			labels:   map[string]string{metricNameLabel: "app_info", "version": `"1.0"`},
+			want:     true,
+		},
+		{name: "empty", selector: "", wantErr: true},
+		{name: "unclosed", selector: `node_load1{cpu="0"`, wantErr: true},
+		{name: "unquoted", selector: `node_load1{cpu=0}`, wantErr: true},
+		{name: "no operator", selector: `node_load1{cpu}`, wantErr: true},
+		{name: "missing comma", selector: `node_load1{cpu="0" mode="idle"}`, wantErr: true},
+		{name: "invalid regex", selector: `node_load1{cpu=~"("}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSelector(tt.selector)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidSelector)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.matches(tt.labels))
+		})
+	}
+}
+
+func Test_metricUnit(t *testing.T) {
+	assert.Equal(t, "celsius", metricUnit("node_hwmon_temp_celsius"))
+	assert.Equal(t, "bytes", metricUnit("node_network_receive_bytes_total"))
+	assert.Empty(t, metricUnit("up"))
+}
+
+func newTestPrometheusTarget(t *testing.T, target PrometheusTarget) *PrometheusTarget {
+	t.Helper()
+
+	for idx := range target.Metrics {
+		var err error
+
+		target.Metrics[idx].selector, err = parseSelector(target.Metrics[idx].Match)
+		require.NoError(t, err)
+	}
+
+	return &target
+}
+
+func TestPrometheusTarget_entities(t *testing.T) {
+	families, err := parseMetricFamilies(strings.NewReader(testMetrics))
+	require.NoError(t, err)
+
+	target := newTestPrometheusTarget(t, PrometheusTarget{
+		Metrics: []PrometheusMetric{
+			{Match: `node_hwmon_temp_celsius{chip="platform_coretemp_0"}`, SensorName: "CPU Temperature"},
+			{Match: `node_network_receive_bytes_total{device!="lo"}`, SensorID: "bytes_received"},
+			{Match: `app_requests`, SensorStateClass: "total", Scale: 2},
+			{Match: `backup_last_success_ratio`},
+		},
+	})
+
+	entities := target.entities(t.Context(), families)
+	sensors := make(map[string]models.Sensor, len(entities))
+
+	for _, entity := range entities {
+		sensor, err := entity.AsSensor()
+		require.NoError(t, err)
+		sensors[sensor.UniqueID] = sensor
+	}
+	// Two temperatures, one network device and the requests. The NaN ratio is
+	// skipped.
+	require.Len(t, sensors, 4)
+
+	temp, found := sensors["prometheus_cpu_temperature_temp_1"]
+	require.True(t, found)
+	assert.Equal(t, "CPU Temperature temp1", temp.Name)
+	assert.InDelta(t, 45, temp.State, 0)
+	assert.Equal(t, "°C", temp.UnitOfMeasurement)
+	assert.Equal(t, models.SensorClassTemperature.String(), temp.DeviceClass)
+	assert.Equal(t, models.StateMeasurement.String(), temp.StateClass)
+	assert.Equal(t, "platform_coretemp_0", temp.Attributes["chip"])
+	assert.Equal(t, "node_hwmon_temp_celsius", temp.Attributes["metric"])
+
+	received, found := sensors["bytes_received_eth_0"]
+	require.True(t, found)
+	assert.Equal(t, "node_network_receive_bytes_total eth0", received.Name)
+	assert.Equal(t, "B", received.UnitOfMeasurement)
+	assert.Equal(t, models.StateTotalIncreasing.String(), received.StateClass)
+
+	requests, found := sensors["prometheus_app_requests"]
+	require.True(t, found)
+	assert.InDelta(t, 24, requests.State, 0)
+	assert.Equal(t, models.StateTotal.String(), requests.StateClass)
+}
+
+func TestPrometheusWorker_scrape(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/metrics" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(testMetrics)) //nolint:errcheck
+	}))
+	t.Cleanup(server.Close)
+
+	textfileDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(textfileDir, "backup.prom"), []byte("backup_last_run_seconds 120\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(textfileDir, "ignored.txt"), []byte("ignored 1\n"), 0o600))
+
+	worker := &PrometheusWorker{client: resty.New()}
+
+	tests := []struct {
+		name    string
+		target  PrometheusTarget
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "endpoint",
+			target: PrometheusTarget{URL: server.URL + "/metrics"},
+			want:   []string{"app_requests", "backup_last_success_ratio", "node_hwmon_temp_celsius", "node_network_receive_bytes_total"},
+		},
+		{
+			name:   "textfile directory",
+			target: PrometheusTarget{Path: textfileDir},
+			want:   []string{"backup_last_run_seconds"},
+		},
+		{
+			name:   "textfile",
+			target: PrometheusTarget{Path: filepath.Join(textfileDir, "ignored.txt")},
+			want:   []string{"ignored"},
+		},
+		{
+			name:    "endpoint error",
+			target:  PrometheusTarget{URL: server.URL + "/missing"},
+			wantErr: true,
+		},
+		{
+			name:    "missing path",
+			target:  PrometheusTarget{Path: filepath.Join(textfileDir, "missing")},
+			wantErr: true,
+		},
+		{
+			name:    "no url or path",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			families, err := worker.scrape(t.Context(), &tt.target)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrScrapeTarget)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			names := make([]string, 0, len(families))
+			for _, family := range families {
+				names = append(names, family.GetName())
+			}
+
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
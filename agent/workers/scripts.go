@@ -10,12 +10,13 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/iancoleman/strcase"
 	"github.com/pelletier/go-toml/v2"
 	"github.com/reugn/go-quartz/quartz"
@@ -45,18 +46,22 @@ var _ quartz.Job = (*Script)(nil)
 type Script struct {
 	path     string
 	schedule string
+	sandbox  *ScriptSandbox
 	outCh    chan models.Entity
+	done     <-chan struct{}
 }
 
 // newScript returns a new script object that can scheduled with the job
-// scheduler by the agent.
-func newScript(ctx context.Context, path string) (*Script, error) {
+// scheduler by the agent. If sandbox is not nil, the script is run with its
+// restrictions.
+func newScript(ctx context.Context, path string, sandbox *ScriptSandbox) (*Script, error) {
 	script := &Script{
 		path:     path,
 		schedule: "",
+		sandbox:  sandbox,
 	}
 
-	scriptOutput, err := script.parse(ctx)
+	scriptOutput, _, err := script.parse(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("cannot add script %s: %w", path, err)
 	}
@@ -67,15 +72,13 @@ func newScript(ctx context.Context, path string) (*Script, error) {
 }
 
 // Start is used to create the output channel for the script. It will also
-// Execute the script once.
+// Execute the script once. The channel is not closed, as the script might be
+// executed by the scheduler at any time. Instead, once the context is canceled,
+// any script output is discarded.
 func (s *Script) Start(ctx context.Context) <-chan models.Entity {
 	// Create the channel for script output.
 	s.outCh = make(chan models.Entity)
-	// Clean-up on agent close.
-	go func() {
-		defer close(s.outCh)
-		<-ctx.Done()
-	}()
+	s.done = ctx.Done()
 	// Send initial update.
 	go func() {
 		if err := s.Execute(ctx); err != nil {
@@ -87,42 +90,56 @@ func (s *Script) Start(ctx context.Context) <-chan models.Entity {
 	return s.outCh
 }
 
+// jobID returns the ID of the script in the scheduler.
+func (s *Script) jobID() string {
+	return strings.ReplaceAll(filepath.Base(s.path), ".", "_")
+}
+
 // Schedule returns the script's cron schedule string.
 func (s *Script) Schedule() string {
 	return s.schedule
 }
 
-// Execute will run the script and send any sensor entities it outputs to the
-// script's output channel. If there was an error running the script, a non-nil
-// error is returned.
+// Execute will run the script and send any entities it outputs, followed by
+// the script health sensor, to the script's output channel. If there was an
+// error running the script, a non-nil error is returned.
 func (s *Script) Execute(ctx context.Context) error {
-	output, err := s.parse(ctx)
-	if err != nil {
-		return errors.Join(ErrExecuteScript, err)
+	entities, err := s.Run(ctx)
+
+	for _, entity := range entities {
+		select {
+		case s.outCh <- entity:
+		case <-s.done:
+			return nil
+		}
 	}
 
-	for _, sensor := range output.Sensors {
-		s.outCh <- scriptToEntity(ctx, sensor)
+	if err != nil {
+		return errors.Join(ErrExecuteScript, err)
 	}
 
 	return nil
 }
 
-// Run will run the script and return a slice of sensor entities. If there was
-// an error running the script, a non-nil error is returned.
+// Run will run the script and return a slice of the sensor, event and location
+// entities it outputs, followed by the script health sensor. If there was an
+// error running the script, a non-nil error is returned, and only the health
+// sensor and any valid entities are returned.
 func (s *Script) Run(ctx context.Context) ([]models.Entity, error) {
-	output, err := s.parse(ctx)
+	output, health, err := s.parse(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("error running script: %w", err)
+		return []models.Entity{health.entity(ctx)}, fmt.Errorf("error running script: %w", err)
 	}
 
-	sensors := make([]models.Entity, 0, len(output.Sensors))
+	entities, err := output.entities(ctx)
+	health.addParseError(err)
+	entities = append(entities, health.entity(ctx))
 
-	for _, s := range output.Sensors {
-		sensors = append(sensors, scriptToEntity(ctx, s))
+	if err != nil {
+		return entities, fmt.Errorf("error running script: %w", err)
 	}
 
-	return sensors, nil
+	return entities, nil
 }
 
 // Description returns a formatted string showing the script path and schedule.
@@ -130,38 +147,83 @@ func (s *Script) Description() string {
 	return fmt.Sprintf("Run %s on schedule %s", s.path, s.schedule)
 }
 
-// parse extracts the script schedule and sensor output from the script output.
-// It will return a non-nil error if there was a problem parsing the script output.
-func (s *Script) parse(ctx context.Context) (*scriptOutput, error) {
-	cmdElems := strings.Split(s.path, " ")
+// parse extracts the script schedule and sensor output from the script output,
+// and tracks the health of the script run. It will return a non-nil error if
+// there was a problem running the script or parsing the script output.
+func (s *Script) parse(ctx context.Context) (*scriptOutput, *scriptHealth, error) {
+	health := newScriptHealth(s.path)
 
-	if len(cmdElems) == 0 {
-		return nil, ErrParseCmd
+	if s.path == "" {
+		return nil, health, ErrParseCmd
 	}
 
 	cmdCtx, cancelFunc := context.WithTimeout(ctx, defaultCommandTimeout)
 	defer cancelFunc()
 
-	out, err := exec.CommandContext(cmdCtx, cmdElems[0], cmdElems[1:]...).Output() // #nosec: G204
+	started := time.Now()
+	out, err := scriptCommand(cmdCtx, s.path, s.sandbox).Output()
+	health.finished(started, err)
+
 	if err != nil {
-		return nil, fmt.Errorf("could not execute script: %w", err)
+		return nil, health, fmt.Errorf("could not execute script: %w", err)
 	}
 
 	output := &scriptOutput{}
 
 	if err := output.Unmarshal(out); err != nil {
-		return nil, fmt.Errorf("parse error: %w", err)
+		health.addParseError(err)
+
+		return nil, health, fmt.Errorf("parse error: %w", err)
 	}
 
-	return output, nil
+	return output, health, nil
 }
 
 // scriptOutput represents the output from a script. The output must be
-// formatted as either valid JSON or YAML. This output is used to define a
-// sensor in Home Assistant.
+// formatted as either valid JSON, YAML or TOML. This output is used to define
+// sensors, events and location updates in Home Assistant.
 type scriptOutput struct {
-	Schedule string         `json:"schedule" yaml:"schedule"`
-	Sensors  []ScriptSensor `json:"sensors"  yaml:"sensors"`
+	Schedule  string           `json:"schedule"  yaml:"schedule"`
+	Sensors   []ScriptSensor   `json:"sensors"   yaml:"sensors"`
+	Events    []ScriptEvent    `json:"events"    yaml:"events"`
+	Locations []ScriptLocation `json:"locations" yaml:"locations"`
+}
+
+// entities returns the entities for the sensors, events and locations in the
+// script output. Any events or locations that are invalid are skipped and a
+// non-nil error is returned listing the problems.
+func (o *scriptOutput) entities(ctx context.Context) ([]models.Entity, error) {
+	var errs error
+
+	entities := make([]models.Entity, 0, len(o.Sensors)+len(o.Events)+len(o.Locations))
+
+	for _, sensor := range o.Sensors {
+		entities = append(entities, scriptToEntity(ctx, sensor))
+	}
+
+	for _, event := range o.Events {
+		entity, err := event.Entity()
+		if err != nil {
+			errs = errors.Join(errs, err)
+
+			continue
+		}
+
+		entities = append(entities, entity)
+	}
+
+	for _, location := range o.Locations {
+		entity, err := location.Entity(ctx)
+		if err != nil {
+			errs = errors.Join(errs, err)
+
+			continue
+		}
+
+		entities = append(entities, entity)
+	}
+
+	return entities, errs
 }
 
 // Unmarshal will attempt to take the raw output from a script execution and
@@ -200,12 +262,16 @@ var ErrNewSensor = errors.New("could not create sensor entity")
 type ScriptSensor struct {
 	SensorState       any            `json:"sensor_state"                  yaml:"sensor_state"                  toml:"sensor_state"`
 	SensorAttributes  map[string]any `json:"sensor_attributes,omitempty"   yaml:"sensor_attributes,omitempty"   toml:"sensor_attributes,omitempty"`
+	Available         *bool          `json:"available,omitempty"           yaml:"available,omitempty"           toml:"available,omitempty"`
 	SensorName        string         `json:"sensor_name"                   yaml:"sensor_name"                   toml:"sensor_name"`
+	SensorID          string         `json:"sensor_id,omitempty"           yaml:"sensor_id,omitempty"           toml:"sensor_id,omitempty"`
 	SensorIcon        string         `json:"sensor_icon,omitempty"         yaml:"sensor_icon,omitempty"         toml:"sensor_icon,omitempty"`
 	SensorDeviceClass string         `json:"sensor_device_class,omitempty" yaml:"sensor_device_class,omitempty" toml:"sensor_device_class,omitempty"`
 	SensorStateClass  string         `json:"sensor_state_class,omitempty"  yaml:"sensor_state_class,omitempty"  toml:"sensor_state_class,omitempty"`
 	SensorStateType   string         `json:"sensor_type,omitempty"         yaml:"sensor_type,omitempty"         toml:"sensor_type,omitempty"`
 	SensorUnits       string         `json:"sensor_units,omitempty"        yaml:"sensor_units,omitempty"        toml:"sensor_units,omitempty"`
+	EntityCategory    string         `json:"entity_category,omitempty"     yaml:"entity_category,omitempty"     toml:"entity_category,omitempty"`
+	DisabledByDefault bool           `json:"disabled_by_default,omitempty" yaml:"disabled_by_default,omitempty" toml:"disabled_by_default,omitempty"`
 }
 
 func scriptToEntity(ctx context.Context, script ScriptSensor) models.Entity {
@@ -218,17 +284,50 @@ func scriptToEntity(ctx context.Context, script ScriptSensor) models.Entity {
 		typeOption = models.AsTypeSensor()
 	}
 
-	return models.NewSensor(ctx,
+	options := []models.SensorOption{
 		models.WithName(script.SensorName),
-		models.WithID(strcase.ToSnake(script.SensorName)),
+		models.WithID(script.ID()),
 		models.WithUnits(script.SensorUnits),
 		models.WithDeviceClass(script.DeviceClass()),
 		models.WithStateClass(script.StateClass()),
+		models.WithCategory(script.Category()),
 		models.WithIcon(script.Icon()),
 		models.WithAttributes(script.Attributes()),
 		models.WithState(script.SensorState),
+		models.AsDisabledByDefault(script.DisabledByDefault),
 		typeOption,
-	)
+	}
+
+	if script.Available != nil && !*script.Available {
+		options = append(options, models.AsUnavailable())
+	}
+
+	return models.NewSensor(ctx, options...)
+}
+
+// Entity returns the sensor entity for the script sensor.
+func (s *ScriptSensor) Entity(ctx context.Context) models.Entity {
+	return scriptToEntity(ctx, *s)
+}
+
+// ID is the unique ID of the script sensor. If the script did not specify an
+// ID, it is derived from the sensor name.
+func (s *ScriptSensor) ID() string {
+	if s.SensorID != "" {
+		return s.SensorID
+	}
+
+	return strcase.ToSnake(s.SensorName)
+}
+
+// Category is the entity category of the script sensor. Only the diagnostic
+// category is supported, any other value is ignored.
+func (s *ScriptSensor) Category() models.EntityCategory {
+	if s.EntityCategory == string(models.EntityCategoryDiagnostic) {
+		return models.EntityCategoryDiagnostic
+	}
+
+	return ""
 }
 
 // Icon is an material design icon to represent the script state.
@@ -242,27 +341,12 @@ func (s *ScriptSensor) Icon() string {
 
 // DeviceClass is a sensor device class for the script state.
 func (s *ScriptSensor) DeviceClass() models.SensorDeviceClass {
-	for d := models.SensorClassMin + 1; d <= models.BinaryClassMax; d++ {
-		if s.SensorDeviceClass == d.String() {
-			return d
-		}
-	}
-
-	return 0
+	return models.ParseSensorDeviceClass(s.SensorDeviceClass)
 }
 
 // StateClass is a sensor state class for the script state.
 func (s *ScriptSensor) StateClass() models.SensorStateClass {
-	switch s.SensorStateClass {
-	case "measurement":
-		return models.StateMeasurement
-	case "total":
-		return models.StateTotal
-	case "total_increasing":
-		return models.StateTotalIncreasing
-	default:
-		return models.StateClassMin
-	}
+	return models.ParseSensorStateClass(s.SensorStateClass)
 }
 
 // Attributes are any additional custom attributes for the script state.
@@ -276,49 +360,173 @@ func (s *ScriptSensor) Attributes() map[string]any {
 	return attributes
 }
 
+var (
+	// ErrNewEvent is returned when a problem occurred creating an event entity.
+	ErrNewEvent = errors.New("could not create event entity")
+	// ErrNewLocation is returned when a problem occurred creating a location
+	// entity.
+	ErrNewLocation = errors.New("could not create location entity")
+)
+
+// ScriptEvent represents an event generated from script output.
+type ScriptEvent struct {
+	EventData map[string]any `json:"event_data,omitempty" yaml:"event_data,omitempty" toml:"event_data,omitempty"`
+	EventType string         `json:"event_type"           yaml:"event_type"           toml:"event_type"`
+}
+
+// Entity returns the event entity for the script event.
+func (e *ScriptEvent) Entity() (models.Entity, error) {
+	if e.EventType == "" {
+		return models.Entity{}, fmt.Errorf("%w: no event_type", ErrNewEvent)
+	}
+
+	data := e.EventData
+	if data == nil {
+		data = make(map[string]any)
+	}
+
+	entity, err := models.NewEvent(e.EventType, data)
+	if err != nil {
+		return models.Entity{}, fmt.Errorf("%w: %w", ErrNewEvent, err)
+	}
+
+	return entity, nil
+}
+
+// ScriptLocation represents a location update generated from script output.
+// The fields match those of a Home Assistant location update.
+type ScriptLocation struct {
+	LocationName     string    `json:"location_name,omitempty"     yaml:"location_name,omitempty"     toml:"location_name,omitempty"`
+	Gps              []float32 `json:"gps"                         yaml:"gps"                         toml:"gps"`
+	GpsAccuracy      int       `json:"gps_accuracy"                yaml:"gps_accuracy"                toml:"gps_accuracy"`
+	Altitude         int       `json:"altitude,omitempty"          yaml:"altitude,omitempty"          toml:"altitude,omitempty"`
+	Battery          int       `json:"battery,omitempty"           yaml:"battery,omitempty"           toml:"battery,omitempty"`
+	Course           int       `json:"course,omitempty"            yaml:"course,omitempty"            toml:"course,omitempty"`
+	Speed            int       `json:"speed,omitempty"             yaml:"speed,omitempty"             toml:"speed,omitempty"`
+	VerticalAccuracy int       `json:"vertical_accuracy,omitempty" yaml:"vertical_accuracy,omitempty" toml:"vertical_accuracy,omitempty"`
+}
+
+// Entity returns the location entity for the script location. The location
+// must have GPS coordinates (latitude and longitude) and a GPS accuracy.
+func (l *ScriptLocation) Entity(ctx context.Context) (models.Entity, error) {
+	if len(l.Gps) != 2 { //nolint:mnd
+		return models.Entity{}, fmt.Errorf("%w: gps must be [latitude, longitude]", ErrNewLocation)
+	}
+
+	if l.GpsAccuracy <= 0 {
+		return models.Entity{}, fmt.Errorf("%w: no gps_accuracy", ErrNewLocation)
+	}
+
+	entity, err := models.NewLocation(ctx,
+		models.WithGPSCoords(l.Gps[0], l.Gps[1]),
+		models.WithGPSAccuracy(l.GpsAccuracy),
+		models.WithAltitude(l.Altitude),
+		models.WithBattery(l.Battery),
+		models.WithCourse(l.Course),
+		models.WithSpeed(l.Speed),
+		models.WithVerticalAccuracy(l.VerticalAccuracy),
+		models.WithLocationName(l.LocationName),
+	)
+	if err != nil {
+		return models.Entity{}, fmt.Errorf("%w: %w", ErrNewLocation, err)
+	}
+
+	return *entity, nil
+}
+
 var (
 	ErrUnknownScript    = errors.New("unknown or nonexistent script")
 	ErrAlreadyStarted   = errors.New("script already started")
 	ErrAlreadyStopped   = errors.New("script already stopped")
 	ErrSchedulingFailed = errors.New("failed to schedule script")
-	ErrParseSchedule    = errors.New("could not parse script schedule")
+	// ErrParseSchedule is returned when a script schedule cannot be parsed. It is an alias of
+	// scheduler.ErrParseSchedule.
+	ErrParseSchedule = scheduler.ErrParseSchedule
 )
 
 const (
 	scriptWorkerID   = "scripts"
 	scriptWorkerDesc = "Custom script-based sensors"
+
+	// scriptsReloadDelay is how long to wait for further changes in the
+	// scripts directory before reloading the scripts. Editors will often
+	// generate several events when saving a file.
+	scriptsReloadDelay = time.Second
 )
 
-// ScriptWorker is a worker for custom scripts.
+// ScriptPrefs are the preferences for the scripts worker.
+type ScriptPrefs struct {
+	CommonWorkerPrefs `toml:",squash"`
+
+	// Streaming is a list of script file names (in the scripts directory) that
+	// are long-running and output newline-delimited JSON records, rather than
+	// being run on a schedule.
+	Streaming []string `toml:"streaming"`
+	// Sandbox is a list of restrictions to apply to scripts.
+	Sandbox []ScriptSandbox `toml:"sandbox"`
+}
+
+// IsStreaming returns whether the script at the given path is a streaming
+// script.
+func (p *ScriptPrefs) IsStreaming(path string) bool {
+	return slices.Contains(p.Streaming, filepath.Base(path))
+}
+
+// ScriptWorker is a worker for custom scripts. Once started, it watches the
+// scripts directory, adding, reloading and removing scripts as they change.
 type ScriptWorker struct {
 	*models.WorkerMetadata
 
-	scripts []*Script
+	path    string
+	scripts map[string]*managedScript
 	outCh   chan models.Entity
-	prefs   *CommonWorkerPrefs
+	prefs   *ScriptPrefs
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+}
+
+// managedScript is a scheduled or streaming script in the scripts directory
+// that is managed by the worker.
+type managedScript struct {
+	script  *Script
+	stream  *StreamScript
+	modTime time.Time
+	cancel  context.CancelFunc
+	done    chan struct{}
+	// sensors are the last sensors output by the script, by ID. They are only
+	// accessed by the goroutine forwarding the script output until done is
+	// closed.
+	sensors map[string]models.Entity
 }
 
 // NewScriptsWorker creates a new worker for custom scripts.
 func NewScriptsWorker(ctx context.Context) (*ScriptWorker, error) {
-	scriptPath := filepath.Join(config.GetPath(), "scripts")
-
 	worker := &ScriptWorker{
 		WorkerMetadata: models.SetWorkerMetadata(scriptWorkerID, scriptWorkerDesc),
+		path:           filepath.Join(config.GetPath(), "scripts"),
+		scripts:        make(map[string]*managedScript),
 	}
 
-	defaultPrefs := &CommonWorkerPrefs{}
+	defaultPrefs := &ScriptPrefs{}
 
-	scripts, err := worker.findScripts(ctx, scriptPath)
-	if err != nil {
-		return worker, fmt.Errorf("could not find scripts: %w", err)
-	}
-	worker.scripts = scripts
+	var err error
 
 	worker.prefs, err = LoadWorkerPreferences("scripts", defaultPrefs)
 	if err != nil {
 		return worker, fmt.Errorf("could not load preferences: %w", err)
 	}
 
+	files, err := findScripts(worker.path)
+	if err != nil {
+		return worker, fmt.Errorf("could not find scripts: %w", err)
+	}
+
+	for path, modTime := range files {
+		if script := worker.newManagedScript(ctx, path, modTime); script != nil {
+			worker.scripts[path] = script
+		}
+	}
+
 	return worker, nil
 }
 
@@ -327,19 +535,24 @@ func (c *ScriptWorker) IsDisabled() bool {
 	return c.prefs.IsDisabled()
 }
 
-// States will execute all running scripts and returns their sensor entities.
+// States will execute all running scripts and returns their entities.
 func (c *ScriptWorker) States(ctx context.Context) []models.Entity {
 	var allSensors []models.Entity
 
-	for _, script := range c.scripts {
-		scriptSensors, err := script.Run(ctx)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, managed := range c.scripts {
+		if managed.script == nil {
+			continue
+		}
+
+		scriptSensors, err := managed.script.Run(ctx)
 		if err != nil {
 			slogctx.FromCtx(ctx).Warn("Could not retrieve script sensors",
-				slog.String("script", script.Description()),
+				slog.String("script", managed.script.Description()),
 				slog.Any("error", err),
 			)
-
-			continue
 		}
 
 		allSensors = append(allSensors, scriptSensors...)
@@ -348,167 +561,384 @@ func (c *ScriptWorker) States(ctx context.Context) []models.Entity {
 	return allSensors
 }
 
+// Start will schedule (or run, for streaming scripts) all scripts and watch
+// the scripts directory for changes. The returned channel is closed once the
+// context is canceled.
 func (c *ScriptWorker) Start(ctx context.Context) (<-chan models.Entity, error) {
 	c.outCh = make(chan models.Entity)
 
-	scriptOutputs := make([]<-chan models.Entity, 0, len(c.scripts))
+	c.mu.Lock()
+	for path, managed := range c.scripts {
+		if err := c.startScript(ctx, managed); err != nil {
+			slogctx.FromCtx(ctx).Warn("Could not schedule script.",
+				slog.String("script", path),
+				slog.Any("error", err))
+			delete(c.scripts, path)
+		}
+	}
+	c.mu.Unlock()
+
+	// The watch is added before returning, so that any changes made to the
+	// scripts directory from now on are noticed.
+	watcher, watchingDir, err := c.newWatcher()
+	if err != nil {
+		slogctx.FromCtx(ctx).Warn("Could not watch scripts directory for changes.",
+			slog.String("path", c.path),
+			slog.Any("error", err))
+	} else {
+		c.wg.Go(func() {
+			c.watch(ctx, watcher, watchingDir)
+		})
+	}
+
+	go func() {
+		<-ctx.Done()
+		c.wg.Wait()
+		close(c.outCh)
+	}()
+
+	return c.outCh, nil
+}
+
+// newManagedScript returns a new managed script for the script at the given
+// path. Non-streaming scripts are run once to determine their schedule. If the
+// script cannot be used, the error is logged and nil is returned.
+func (c *ScriptWorker) newManagedScript(ctx context.Context, path string, modTime time.Time) *managedScript {
+	managed := &managedScript{modTime: modTime}
+
+	if c.prefs.IsStreaming(path) {
+		managed.stream = newStreamScript(path, c.prefs.sandboxFor(path))
+
+		return managed
+	}
+
+	script, err := newScript(ctx, path, c.prefs.sandboxFor(path))
+	if err != nil {
+		slogctx.FromCtx(ctx).Warn("Script error.",
+			slog.Any("error", err),
+		)
+
+		return nil
+	}
+
+	managed.script = script
+
+	return managed
+}
+
+// startScript starts the given script, scheduling it if it is not a streaming
+// script, and forwards its output to the worker output channel until the
+// script is stopped.
+func (c *ScriptWorker) startScript(ctx context.Context, managed *managedScript) error {
+	scriptCtx, cancelFunc := context.WithCancel(ctx)
 
-	for _, script := range c.scripts {
+	var scriptCh <-chan models.Entity
+
+	if managed.stream != nil {
+		scriptCh = managed.stream.Start(scriptCtx)
+	} else {
 		// Parse the script cron schedule as a scheduler trigger.
-		trigger, err := parseSchedule(script.Schedule())
+		trigger, err := scheduler.ParseSchedule(managed.script.Schedule())
 		if err != nil {
-			slogctx.FromCtx(ctx).Warn("Could not schedule script.",
-				slog.String("script", script.Description()),
-				slog.Any("error", err))
+			cancelFunc()
 
-			continue
+			return errors.Join(ErrSchedulingFailed, err)
 		}
+		// Start the script before scheduling it, so that its output channel
+		// is ready when the scheduler runs it.
+		scriptCh = managed.script.Start(scriptCtx)
 		// Schedule the script.
-		id := strings.ReplaceAll(filepath.Base(script.path), ".", "_")
-		if err = scheduler.ScheduleJob(id, script, trigger); err != nil {
-			slogctx.FromCtx(ctx).Warn("Could not schedule script.",
-				slog.String("script", script.Description()),
-				slog.Any("error", err))
+		if err = scheduler.ScheduleJob(managed.script.jobID(), managed.script, trigger); err != nil {
+			cancelFunc()
 
-			continue
+			return errors.Join(ErrSchedulingFailed, err)
 		}
-		// Append to list of managed scripts.
-		scriptOutputs = append(scriptOutputs, script.Start(ctx))
 	}
 
-	return mergeCh(ctx, scriptOutputs...), nil
-}
+	managed.cancel = cancelFunc
+	managed.done = make(chan struct{})
+	managed.sensors = make(map[string]models.Entity)
+
+	c.wg.Go(func() {
+		defer close(managed.done)
 
-func (c *ScriptWorker) Stop() error {
-	close(c.outCh)
+		for {
+			select {
+			case <-scriptCtx.Done():
+				return
+			case entity, ok := <-scriptCh:
+				if !ok {
+					return
+				}
+
+				if entity.IsSensor() {
+					if sensor, err := entity.AsSensor(); err == nil {
+						managed.sensors[sensor.UniqueID] = entity
+					}
+				}
+
+				select {
+				case c.outCh <- entity:
+				case <-scriptCtx.Done():
+					return
+				}
+			}
+		}
+	})
 
 	return nil
 }
 
-// findScripts locates scripts and returns a slice of scripts that the agent can
-// run.
-func (c *ScriptWorker) findScripts(ctx context.Context, path string) ([]*Script, error) {
-	var sensorScripts []*Script
+// stopScript stops the given script, unscheduling it if it is not a streaming
+// script. If removed is true, the sensors of the script are marked as
+// unavailable. The script must already have been removed from the managed
+// scripts, so that the worker lock is not needed.
+func (c *ScriptWorker) stopScript(ctx context.Context, path string, managed *managedScript, removed bool) {
+	if managed.script != nil {
+		if err := scheduler.UnscheduleJob(managed.script.jobID()); err != nil {
+			slogctx.FromCtx(ctx).Debug("Could not unschedule script.",
+				slog.String("script", path),
+				slog.Any("error", err))
+		}
+	}
 
-	files, err := filepath.Glob(path + "/*")
-	if err != nil {
-		return nil, fmt.Errorf("could not search for scripts: %w", err)
+	managed.cancel()
+	<-managed.done
+
+	if !removed {
+		return
 	}
 
-	for _, scriptFile := range files {
-		if isExecutable(scriptFile) {
-			script, err := newScript(ctx, scriptFile)
-			if err != nil {
-				slogctx.FromCtx(ctx).Warn("Script error.",
-					slog.Any("error", err),
-				)
+	for _, entity := range managed.sensors {
+		sensor, err := entity.AsSensor()
+		if err != nil {
+			continue
+		}
+
+		models.AsUnavailable()(&sensor)
+
+		var unavailable models.Entity
+		if err := unavailable.FromSensor(sensor); err != nil {
+			continue
+		}
+
+		select {
+		case c.outCh <- unavailable:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// watch will reload the scripts whenever the scripts directory changes, until
+// the context is canceled. If the scripts directory does not exist, the
+// scripts are reloaded once it is created.
+func (c *ScriptWorker) watch(ctx context.Context, watcher *fsnotify.Watcher, watchingDir bool) {
+	defer watcher.Close() //nolint:errcheck
 
+	reload := time.NewTimer(scriptsReloadDelay)
+	reload.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			reload.Stop()
+
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+
+			switch {
+			case filepath.Clean(event.Name) == filepath.Clean(c.path):
+				// The scripts directory has been created or removed.
+				var err error
+
+				watchingDir, err = c.addWatch(watcher)
+				if err != nil {
+					slogctx.FromCtx(ctx).Warn("Could not watch scripts directory for changes.",
+						slog.String("path", c.path),
+						slog.Any("error", err))
+
+					return
+				}
+			case !watchingDir:
+				// Some other entry in the directory containing the scripts
+				// directory has changed.
 				continue
 			}
 
-			sensorScripts = append(sensorScripts, script)
+			reload.Reset(scriptsReloadDelay)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+
+			slogctx.FromCtx(ctx).Warn("Error watching scripts directory.",
+				slog.Any("error", err))
+		case <-reload.C:
+			if err := c.reload(ctx); err != nil {
+				slogctx.FromCtx(ctx).Warn("Could not reload scripts.",
+					slog.Any("error", err))
+			}
 		}
 	}
-
-	return sensorScripts, nil
 }
 
-// isExecutable is helper to determine if a (script) file is executable.
-func isExecutable(filename string) bool {
-	fi, err := os.Stat(filename)
+// reload finds the scripts in the scripts directory again. New scripts are
+// started, changed scripts are restarted (with any new schedule) and removed
+// scripts are stopped, with their sensors marked as unavailable.
+//
+// The worker lock is only held to find the scripts that have changed. Stopping,
+// running and starting scripts is done without it, as that can take as long as
+// a script runs or the consumer of the worker's entities takes to read them.
+func (c *ScriptWorker) reload(ctx context.Context) error {
+	files, err := findScripts(c.path)
 	if err != nil {
-		return false
+		return fmt.Errorf("could not find scripts: %w", err)
 	}
 
-	return fi.Mode().Perm()&0o111 != 0
-}
+	removed := make(map[string]*managedScript)
+	changed := make(map[string]*managedScript)
+	added := make(map[string]time.Time)
 
-// mergeCh merges a list of channels of any type into a single channel of that
-// type (channel fan-in).
-func mergeCh[T any](ctx context.Context, inCh ...<-chan T) chan T {
-	var wg sync.WaitGroup
+	c.mu.Lock()
+	for path, managed := range c.scripts {
+		if _, found := files[path]; !found {
+			removed[path] = managed
+			delete(c.scripts, path)
+		}
+	}
+
+	for path, modTime := range files {
+		current, found := c.scripts[path]
+		if found && current.modTime.Equal(modTime) {
+			continue
+		}
 
-	outCh := make(chan T)
+		if found {
+			changed[path] = current
+			delete(c.scripts, path)
+		}
 
-	// Start an output goroutine for each input channel in sensorCh.  output
-	// copies values from c to out until c is closed, then calls wg.Done.
-	output := func(ch <-chan T) {
-		defer wg.Done()
+		added[path] = modTime
+	}
+	c.mu.Unlock()
 
-		if ch == nil {
-			return
+	for path, managed := range removed {
+		slogctx.FromCtx(ctx).Info("Script removed.",
+			slog.String("script", path))
+		c.stopScript(ctx, path, managed, true)
+	}
+
+	for path, managed := range changed {
+		slogctx.FromCtx(ctx).Info("Script changed, reloading.",
+			slog.String("script", path))
+		c.stopScript(ctx, path, managed, false)
+	}
+
+	for path, modTime := range added {
+		if _, found := changed[path]; !found {
+			slogctx.FromCtx(ctx).Info("Script added.",
+				slog.String("script", path))
 		}
 
-		for n := range ch {
-			select {
-			case outCh <- n:
-			case <-ctx.Done():
-				return
-			}
+		managed := c.newManagedScript(ctx, path, modTime)
+		if managed == nil {
+			continue
 		}
+
+		if err := c.startScript(ctx, managed); err != nil {
+			slogctx.FromCtx(ctx).Warn("Could not schedule script.",
+				slog.String("script", path),
+				slog.Any("error", err))
+
+			continue
+		}
+
+		c.mu.Lock()
+		c.scripts[path] = managed
+		c.mu.Unlock()
 	}
 
-	wg.Add(len(inCh))
+	return nil
+}
 
-	for _, c := range inCh {
-		go output(c)
+// newWatcher returns a watcher for changes to the scripts directory, and
+// whether the scripts directory itself is being watched (see addWatch).
+func (c *ScriptWorker) newWatcher() (*fsnotify.Watcher, bool, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, false, fmt.Errorf("could not create watcher: %w", err)
 	}
 
-	// Start a goroutine to close out once all the output goroutines are
-	// done.  This must start after the wg.Add call.
-	go func() {
-		wg.Wait()
-		close(outCh)
-	}()
+	watchingDir, err := c.addWatch(watcher)
+	if err != nil {
+		watcher.Close() //nolint:errcheck,gosec
+
+		return nil, false, err
+	}
 
-	return outCh
+	return watcher, watchingDir, nil
 }
 
-// parseSchedule parses a cron schedule string and returns the equivalent quartz
-// Trigger.
-//
-// Cron schedule parsing code adapted from
-// https://github.com/robfig/cron/blob/master/parser.go
-func parseSchedule(sched string) (quartz.Trigger, error) {
-	var (
-		trigger quartz.Trigger
-		err     error
-	)
+// addWatch adds a watch for the scripts directory. If the scripts directory
+// does not exist, the directory containing it is watched instead, so that the
+// scripts directory being created can be noticed. It returns a boolean
+// indicating whether the scripts directory itself is being watched.
+func (c *ScriptWorker) addWatch(watcher *fsnotify.Watcher) (bool, error) {
+	parent := filepath.Dir(c.path)
 
-	// Attempt to parse as a standard cron schedule string.
-	trigger, err = quartz.NewCronTrigger(sched)
-	if err == nil {
-		return trigger, nil
-	}
-
-	// Attempt to parse as one of the year/month/week/day/hour strings.
-	switch sched {
-	case "@yearly", "@annually":
-		trigger, err = quartz.NewCronTrigger("0 0 0 1 1 * *")
-	case "@monthly":
-		trigger, err = quartz.NewCronTrigger("0 0 0 1 * *")
-	case "@weekly":
-		trigger, err = quartz.NewCronTrigger("0 0 0 * * 1")
-	case "@daily", "@midnight":
-		trigger, err = quartz.NewCronTrigger("0 0 0 * * *")
-	case "@hourly":
-		trigger, err = quartz.NewCronTrigger("0 0 * * * *")
-	}
-	// If successfully parsed, return the trigger.
-	if err == nil {
-		return trigger, nil
-	}
-
-	// Else, attempt to parse as an "@every ..." string.
-	const every = "@every "
-	if strings.HasPrefix(sched, every) {
-		duration, err := time.ParseDuration(sched[len(every):])
-		if err != nil {
-			return nil, fmt.Errorf("%w: %w", ErrParseSchedule, err)
+	if err := watcher.Add(c.path); err == nil {
+		_ = watcher.Remove(parent) //nolint:errcheck // the parent might not be watched.
+
+		return true, nil
+	}
+
+	if err := watcher.Add(parent); err != nil {
+		return false, fmt.Errorf("could not watch %s: %w", parent, err)
+	}
+
+	// Check the scripts directory was not created before the parent was
+	// watched.
+	if err := watcher.Add(c.path); err == nil {
+		_ = watcher.Remove(parent) //nolint:errcheck // the parent is watched.
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// findScripts locates the executable scripts in the given directory, returning
+// their paths and modification times.
+func findScripts(path string) (map[string]time.Time, error) {
+	files, err := filepath.Glob(path + "/*")
+	if err != nil {
+		return nil, fmt.Errorf("could not search for scripts: %w", err)
+	}
+
+	scripts := make(map[string]time.Time, len(files))
+
+	for _, scriptFile := range files {
+		fi, err := os.Stat(scriptFile)
+		if err != nil || fi.IsDir() || !isExecutable(scriptFile) {
+			continue
 		}
 
-		return quartz.NewSimpleTrigger(duration), nil
+		scripts[scriptFile] = fi.ModTime()
 	}
 
-	return nil, fmt.Errorf("%w: unknown schedule format %s", ErrParseSchedule, sched)
+	return scripts, nil
+}
+
+// isExecutable is helper to determine if a (script) file is executable.
+func isExecutable(filename string) bool {
+	fi, err := os.Stat(filename)
+	if err != nil {
+		return false
+	}
+
+	return fi.Mode().Perm()&0o111 != 0
 }
@@ -0,0 +1,91 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package workers
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/iancoleman/strcase"
+
+	"github.com/joshuar/go-hass-agent/models"
+)
+
+// scriptHealth tracks the health of the last run of a script: its exit code,
+// how long it ran for and any errors parsing its output.
+type scriptHealth struct {
+	script         string
+	exitCode       int
+	duration       time.Duration
+	parseErrors    int
+	lastParseError string
+}
+
+// newScriptHealth returns a new health tracker for the script at the given
+// path.
+func newScriptHealth(path string) *scriptHealth {
+	return &scriptHealth{script: path}
+}
+
+// finished records the end of a run of the script that started at the given
+// time and exited with the given error.
+func (h *scriptHealth) finished(started time.Time, err error) {
+	h.duration = time.Since(started)
+
+	var exitErr *exec.ExitError
+
+	switch {
+	case err == nil:
+		h.exitCode = 0
+	case errors.As(err, &exitErr):
+		h.exitCode = exitErr.ExitCode()
+	default:
+		h.exitCode = -1
+	}
+}
+
+// addParseError records an error parsing the script output.
+func (h *scriptHealth) addParseError(err error) {
+	if err == nil {
+		return
+	}
+
+	h.parseErrors++
+	h.lastParseError = err.Error()
+}
+
+// entity returns a diagnostic sensor entity for the script health. The state
+// is the exit code of the script. The exit code is -1 if the script could not
+// be run or was killed.
+func (h *scriptHealth) entity(ctx context.Context) models.Entity {
+	name := filepath.Base(h.script)
+
+	attributes := map[string]any{
+		"script":       h.script,
+		"run_duration": h.duration.Seconds(),
+		"parse_errors": h.parseErrors,
+	}
+	if h.lastParseError != "" {
+		attributes["last_parse_error"] = h.lastParseError
+	}
+
+	return models.NewSensor(ctx,
+		models.WithName(name+" Script Health"),
+		models.WithID(scriptID(h.script)+"_health"),
+		models.WithIcon("mdi:script-text-play"),
+		models.WithState(h.exitCode),
+		models.WithAttributes(attributes),
+		models.AsDiagnostic(),
+	)
+}
+
+// scriptID returns an ID for the script at the given path, derived from its
+// file name.
+func scriptID(path string) string {
+	return "script_" + strcase.ToSnake(strings.ReplaceAll(filepath.Base(path), ".", "_"))
+}
@@ -0,0 +1,151 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package workers
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// systemdEnv are the environment variables that are always passed to a script
+// that has resource limits, so that systemd-run can reach the user's service
+// manager.
+var systemdEnv = []string{"XDG_RUNTIME_DIR", "DBUS_SESSION_BUS_ADDRESS"}
+
+// ScriptSandbox contains options that restrict what a script can do. Options
+// that are not set are not restricted.
+//
+// CPU and memory limits are applied by running the script in a transient
+// systemd user scope (with systemd-run). No new privileges and read-only paths
+// are applied with setpriv and bubblewrap (bwrap) respectively. If any of
+// these tools are needed and not installed, the script will fail to run.
+type ScriptSandbox struct {
+	// Script is the file name of the script (in the scripts directory) that
+	// the sandbox applies to. It may be a glob pattern, such as "*" to apply
+	// to all scripts.
+	Script string `toml:"script"`
+	// Env is a list of environment variable names, which may be glob patterns,
+	// that are passed to the script. If not set, the script is passed the
+	// agent's full environment.
+	Env []string `toml:"env,omitempty"`
+	// WorkingDir is the directory the script is run in.
+	WorkingDir string `toml:"working_dir,omitempty"`
+	// CPUQuota is the CPU time the script can use, relative to a single CPU,
+	// as a percentage (e.g., "20%").
+	CPUQuota string `toml:"cpu_quota,omitempty"`
+	// MemoryMax is the maximum memory the script can use, in bytes, with an
+	// optional K, M, G or T suffix, or as a percentage of physical memory.
+	MemoryMax string `toml:"memory_max,omitempty"`
+	// NoNewPrivileges ensures the script (and any process it runs) cannot gain
+	// new privileges (for example, through sudo).
+	NoNewPrivileges bool `toml:"no_new_privileges,omitempty"`
+	// ReadOnlyPaths is a list of paths that the script can read but not write
+	// to.
+	ReadOnlyPaths []string `toml:"read_only_paths,omitempty"`
+}
+
+// sandboxFor returns the first sandbox in the preferences that applies to the
+// script at the given path, or nil if there are none.
+func (p *ScriptPrefs) sandboxFor(scriptPath string) *ScriptSandbox {
+	name := filepath.Base(scriptPath)
+
+	for idx := range p.Sandbox {
+		if matched, err := path.Match(p.Sandbox[idx].Script, name); err == nil && matched {
+			return &p.Sandbox[idx]
+		}
+	}
+
+	return nil
+}
+
+// hasLimits returns whether the sandbox has any resource limits.
+func (s *ScriptSandbox) hasLimits() bool {
+	return s.CPUQuota != "" || s.MemoryMax != ""
+}
+
+// wrap returns the command-line that runs the given script command-line with
+// the sandbox restrictions applied.
+func (s *ScriptSandbox) wrap(cmdElems []string) []string {
+	var wrapped []string
+
+	if s.hasLimits() {
+		wrapped = append(wrapped, "systemd-run", "--user", "--scope", "--quiet", "--collect")
+		if s.CPUQuota != "" {
+			wrapped = append(wrapped, "--property=CPUQuota="+s.CPUQuota)
+		}
+
+		if s.MemoryMax != "" {
+			wrapped = append(wrapped, "--property=MemoryMax="+s.MemoryMax)
+		}
+
+		wrapped = append(wrapped, "--")
+	}
+
+	switch {
+	case len(s.ReadOnlyPaths) > 0:
+		// bwrap always runs its command with no new privileges.
+		wrapped = append(wrapped, "bwrap", "--dev-bind", "/", "/", "--die-with-parent")
+		for _, roPath := range s.ReadOnlyPaths {
+			wrapped = append(wrapped, "--ro-bind", roPath, roPath)
+		}
+
+		wrapped = append(wrapped, "--")
+	case s.NoNewPrivileges:
+		wrapped = append(wrapped, "setpriv", "--no-new-privs", "--")
+	}
+
+	return append(wrapped, cmdElems...)
+}
+
+// environ filters the given environment to the variables allowed by the
+// sandbox.
+func (s *ScriptSandbox) environ(environ []string) []string {
+	if s.Env == nil {
+		return environ
+	}
+
+	allowed := s.Env
+	if s.hasLimits() {
+		allowed = slices.Concat(allowed, systemdEnv)
+	}
+
+	filtered := make([]string, 0, len(allowed))
+
+	for _, variable := range environ {
+		name, _, _ := strings.Cut(variable, "=")
+
+		for _, pattern := range allowed {
+			if matched, err := path.Match(pattern, name); err == nil && matched {
+				filtered = append(filtered, variable)
+
+				break
+			}
+		}
+	}
+
+	return filtered
+}
+
+// scriptCommand returns the command to run the script at the given path, with
+// the restrictions of the given sandbox (if not nil) applied.
+func scriptCommand(ctx context.Context, scriptPath string, sandbox *ScriptSandbox) *exec.Cmd {
+	cmdElems := strings.Split(scriptPath, " ")
+	if sandbox != nil {
+		cmdElems = sandbox.wrap(cmdElems)
+	}
+
+	cmd := exec.CommandContext(ctx, cmdElems[0], cmdElems[1:]...) // #nosec: G204
+
+	if sandbox != nil {
+		cmd.Env = sandbox.environ(os.Environ())
+		cmd.Dir = sandbox.WorkingDir
+	}
+
+	return cmd
+}
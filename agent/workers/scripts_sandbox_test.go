@@ -0,0 +1,148 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package workers
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScriptPrefs_sandboxFor(t *testing.T) {
+	prefs := &ScriptPrefs{
+		Sandbox: []ScriptSandbox{
+			{Script: "backup.sh", MemoryMax: "64M"},
+			{Script: "*.py", NoNewPrivileges: true},
+			{Script: "*", WorkingDir: "/tmp"},
+		},
+	}
+
+	assert.Equal(t, "64M", prefs.sandboxFor("/scripts/backup.sh").MemoryMax)
+	assert.True(t, prefs.sandboxFor("/scripts/weather.py").NoNewPrivileges)
+	assert.Equal(t, "/tmp", prefs.sandboxFor("/scripts/other.sh").WorkingDir)
+	assert.Nil(t, (&ScriptPrefs{}).sandboxFor("/scripts/other.sh"))
+}
+
+func TestScriptSandbox_wrap(t *testing.T) {
+	tests := []struct {
+		name    string
+		sandbox ScriptSandbox
+		want    []string
+	}{
+		{
+			name: "no restrictions",
+			want: []string{"/scripts/test.sh"},
+		},
+		{
+			name:    "limits",
+			sandbox: ScriptSandbox{CPUQuota: "20%", MemoryMax: "64M"},
+			want: []string{
+				"systemd-run", "--user", "--scope", "--quiet", "--collect",
+				"--property=CPUQuota=20%", "--property=MemoryMax=64M", "--",
+				"/scripts/test.sh",
+			},
+		},
+		{
+			name:    "no new privileges",
+			sandbox: ScriptSandbox{NoNewPrivileges: true},
+			want:    []string{"setpriv", "--no-new-privs", "--", "/scripts/test.sh"},
+		},
+		{
+			name:    "read-only paths",
+			sandbox: ScriptSandbox{NoNewPrivileges: true, ReadOnlyPaths: []string{"/home"}},
+			want: []string{
+				"bwrap", "--dev-bind", "/", "/", "--die-with-parent", "--ro-bind", "/home", "/home", "--",
+				"/scripts/test.sh",
+			},
+		},
+		{
+			name:    "all",
+			sandbox: ScriptSandbox{MemoryMax: "10%", ReadOnlyPaths: []string{"/home"}},
+			want: []string{
+				"systemd-run", "--user", "--scope", "--quiet", "--collect", "--property=MemoryMax=10%", "--",
+				"bwrap", "--dev-bind", "/", "/", "--die-with-parent", "--ro-bind", "/home", "/home", "--",
+				"/scripts/test.sh",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.sandbox.wrap([]string{"/scripts/test.sh"}))
+		})
+	}
+}
+
+func TestScriptSandbox_environ(t *testing.T) {
+	environ := []string{"PATH=/usr/bin", "HOME=/home/user", "LC_ALL=C", "LC_TIME=C", "SECRET=hunter2", "XDG_RUNTIME_DIR=/run/user/1000"}
+
+	tests := []struct {
+		name    string
+		sandbox ScriptSandbox
+		want    []string
+	}{
+		{
+			name: "not set",
+			want: environ,
+		},
+		{
+			name:    "empty",
+			sandbox: ScriptSandbox{Env: []string{}},
+			want:    []string{},
+		},
+		{
+			name:    "allowlist",
+			sandbox: ScriptSandbox{Env: []string{"PATH", "LC_*"}},
+			want:    []string{"PATH=/usr/bin", "LC_ALL=C", "LC_TIME=C"},
+		},
+		{
+			name:    "limits",
+			sandbox: ScriptSandbox{Env: []string{"PATH"}, CPUQuota: "20%"},
+			want:    []string{"PATH=/usr/bin", "XDG_RUNTIME_DIR=/run/user/1000"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.sandbox.environ(environ))
+		})
+	}
+}
+
+func Test_scriptCommand(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "test.sh")
+	content := `#!/bin/sh
+echo "dir=$(pwd)"
+echo "allowed=${ALLOWED_VAR}"
+echo "secret=${SECRET_VAR}"
+grep NoNewPrivs /proc/self/status
+`
+	require.NoError(t, os.WriteFile(script, []byte(content), 0o700)) //nolint:gosec
+	t.Setenv("ALLOWED_VAR", "yes")
+	t.Setenv("SECRET_VAR", "hunter2")
+
+	sandbox := &ScriptSandbox{
+		Env:        []string{"PATH", "ALLOWED_*"},
+		WorkingDir: dir,
+	}
+	if _, err := exec.LookPath("setpriv"); err == nil {
+		sandbox.NoNewPrivileges = true
+	}
+
+	out, err := scriptCommand(t.Context(), script, sandbox).Output()
+	require.NoError(t, err)
+
+	output := string(out)
+	assert.Contains(t, output, "dir="+dir)
+	assert.Contains(t, output, "allowed=yes")
+	assert.Contains(t, output, "secret=\n")
+
+	if sandbox.NoNewPrivileges && strings.Contains(output, "NoNewPrivs") {
+		assert.Regexp(t, `NoNewPrivs:\s+1`, output)
+	}
+}
@@ -0,0 +1,264 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package workers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	slogctx "github.com/veqryn/slog-context"
+
+	"github.com/joshuar/go-hass-agent/models"
+)
+
+const (
+	// streamMaxRecordSize is the maximum size of a single record output by a
+	// streaming script.
+	streamMaxRecordSize = 1024 * 1024
+	// streamRestartInitial is the initial delay before restarting a streaming
+	// script that has exited.
+	streamRestartInitial = time.Second
+	// streamRestartMax is the maximum delay before restarting a streaming
+	// script that has exited.
+	streamRestartMax = 5 * time.Minute
+	// streamResetAfter is how long a streaming script needs to have been
+	// running before its restart delay is reset.
+	streamResetAfter = 10 * time.Minute
+	// streamWaitDelay is how long to wait for a stopped streaming script to
+	// exit and its output to be closed, before giving up on it.
+	streamWaitDelay = 5 * time.Second
+)
+
+// ErrInvalidRecord is returned when a record output by a streaming script is
+// not a valid sensor or event.
+var ErrInvalidRecord = errors.New("invalid script record")
+
+// StreamScript represents a long-running script that outputs sensor or event
+// records as newline-delimited JSON on its stdout. The script is kept running
+// by the agent and restarted (with backoff) whenever it exits.
+type StreamScript struct {
+	path    string
+	sandbox *ScriptSandbox
+}
+
+// newStreamScript returns a new streaming script for the given script path. If
+// sandbox is not nil, the script is run with its restrictions.
+func newStreamScript(path string, sandbox *ScriptSandbox) *StreamScript {
+	return &StreamScript{path: path, sandbox: sandbox}
+}
+
+// Description returns a formatted string showing the script path.
+func (s *StreamScript) Description() string {
+	return "Stream " + s.path
+}
+
+// Start will run the script and return a channel on which the entities for
+// the records it outputs are sent. The script is restarted if it exits, until
+// the context is canceled.
+func (s *StreamScript) Start(ctx context.Context) <-chan models.Entity {
+	outCh := make(chan models.Entity)
+
+	go func() {
+		defer close(outCh)
+
+		restart := backoff.NewExponentialBackOff(
+			backoff.WithInitialInterval(streamRestartInitial),
+			backoff.WithMaxInterval(streamRestartMax),
+			backoff.WithMaxElapsedTime(0),
+		)
+
+		for {
+			started := time.Now()
+			health, err := s.run(ctx, outCh)
+
+			if ctx.Err() != nil {
+				return
+			}
+
+			select {
+			case outCh <- health.entity(ctx):
+			case <-ctx.Done():
+				return
+			}
+			// Don't penalise a script that ran fine for a long while.
+			if time.Since(started) > streamResetAfter {
+				restart.Reset()
+			}
+
+			delay := restart.NextBackOff()
+
+			slogctx.FromCtx(ctx).Warn("Streaming script exited, restarting.",
+				slog.String("script", s.path),
+				slog.Duration("restart_in", delay),
+				slog.Any("error", err))
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+		}
+	}()
+
+	return outCh
+}
+
+// run runs the script until it exits or the context is canceled, sending the
+// entities for the records it outputs to the given channel. Records that
+// cannot be parsed are logged and skipped. The health of the script run is
+// returned.
+func (s *StreamScript) run(ctx context.Context, outCh chan<- models.Entity) (*scriptHealth, error) {
+	health := newScriptHealth(s.path)
+	started := time.Now()
+
+	cmd := scriptCommand(ctx, s.path, s.sandbox)
+	cmd.Stderr = &scriptLogger{ctx: ctx, script: s.path}
+	KillProcessGroup(cmd)
+	cmd.WaitDelay = streamWaitDelay
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		health.finished(started, err)
+
+		return health, fmt.Errorf("could not get script output: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		health.finished(started, err)
+
+		return health, errors.Join(ErrExecuteScript, err)
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), streamMaxRecordSize)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		entity, err := parseStreamRecord(ctx, line)
+		if err != nil {
+			health.addParseError(err)
+			slogctx.FromCtx(ctx).Warn("Could not parse streaming script record.",
+				slog.String("script", s.path),
+				slog.Any("error", err))
+
+			continue
+		}
+
+		select {
+		case outCh <- entity:
+		case <-ctx.Done():
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		// Drain any remaining output so the script is not blocked writing.
+		_, _ = io.Copy(io.Discard, stdout) //nolint:errcheck
+		waitErr := cmd.Wait()
+		health.finished(started, waitErr)
+
+		return health, errors.Join(fmt.Errorf("could not read script output: %w", err), waitErr)
+	}
+
+	err = cmd.Wait()
+	health.finished(started, err)
+
+	if err != nil {
+		return health, fmt.Errorf("script exited: %w", err)
+	}
+
+	return health, nil
+}
+
+// streamRecord is a single record output by a streaming script. A record with
+// an event type is an event, a record with GPS coordinates is a location,
+// otherwise it is a sensor.
+type streamRecord struct {
+	ScriptSensor
+	ScriptEvent
+	ScriptLocation
+}
+
+// parseStreamRecord parses a single JSON record output by a streaming script
+// into a sensor, event or location entity.
+func parseStreamRecord(ctx context.Context, data []byte) (models.Entity, error) {
+	var record streamRecord
+
+	if err := json.Unmarshal(data, &record); err != nil {
+		return models.Entity{}, fmt.Errorf("%w: %w", ErrInvalidRecord, err)
+	}
+
+	switch {
+	case record.EventType != "":
+		entity, err := record.ScriptEvent.Entity()
+		if err != nil {
+			return models.Entity{}, fmt.Errorf("%w: %w", ErrInvalidRecord, err)
+		}
+
+		return entity, nil
+	case record.Gps != nil:
+		entity, err := record.ScriptLocation.Entity(ctx)
+		if err != nil {
+			return models.Entity{}, fmt.Errorf("%w: %w", ErrInvalidRecord, err)
+		}
+
+		return entity, nil
+	case record.SensorName != "":
+		return scriptToEntity(ctx, record.ScriptSensor), nil
+	default:
+		return models.Entity{}, fmt.Errorf("%w: no sensor_name, event_type or gps", ErrInvalidRecord)
+	}
+}
+
+// scriptLogger logs anything written to it (i.e., the stderr of a script) at
+// debug level.
+type scriptLogger struct {
+	ctx    context.Context //nolint:containedctx // used for logging only.
+	script string
+}
+
+func (l *scriptLogger) Write(p []byte) (int, error) {
+	for line := range strings.Lines(string(p)) {
+		if line = strings.TrimSpace(line); line != "" {
+			slogctx.FromCtx(l.ctx).Debug("Script output.",
+				slog.String("script", l.script),
+				slog.String("stderr", line))
+		}
+	}
+
+	return len(p), nil
+}
+
+// KillProcessGroup runs the command in its own process group and changes it to
+// kill the whole group when its context is canceled. Scripts and commands are
+// often pipelines (e.g., "journalctl -f | jq") or start other processes, where
+// killing only the command would leave the other processes running and holding
+// its output open. Callers should also set cmd.WaitDelay, in case any processes
+// escape the group.
+func KillProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		err := syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		if errors.Is(err, syscall.ESRCH) {
+			return os.ErrProcessDone
+		}
+
+		return err //nolint:wrapcheck
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package workers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshuar/go-hass-agent/models"
+)
+
+func Test_parseStreamRecord(t *testing.T) {
+	tests := []struct {
+		name       string
+		record     string
+		wantSensor bool
+		wantEvent  bool
+		wantErr    error
+	}{
+		{
+			name:       "sensor",
+			record:     `{"sensor_name": "Log Errors", "sensor_state": 3, "sensor_units": "errors"}`,
+			wantSensor: true,
+		},
+		{
+			name:      "event",
+			record:    `{"event_type": "log_error", "event_data": {"message": "oops"}}`,
+			wantEvent: true,
+		},
+		{
+			name:      "event without data",
+			record:    `{"event_type": "log_rotated"}`,
+			wantEvent: true,
+		},
+		{
+			name:   "location",
+			record: `{"gps": [-33.86, 151.21], "gps_accuracy": 10}`,
+		},
+		{
+			name:    "location without accuracy",
+			record:  `{"gps": [-33.86, 151.21]}`,
+			wantErr: ErrNewLocation,
+		},
+		{
+			name:    "neither",
+			record:  `{"sensor_state": 3}`,
+			wantErr: ErrInvalidRecord,
+		},
+		{
+			name:    "not json",
+			record:  `sensor_state: 3`,
+			wantErr: ErrInvalidRecord,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseStreamRecord(t.Context(), []byte(tt.record))
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSensor, got.IsSensor())
+			assert.Equal(t, tt.wantEvent, got.IsEvent())
+		})
+	}
+}
+
+func TestStreamScript_Start(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "stream.sh")
+	content := `#!/bin/sh
+echo '{"sensor_name": "Stream Sensor", "sensor_state": 1}'
+echo 'garbage'
+echo ''
+echo '{"event_type": "stream_event", "event_data": {"value": 2}}'
+echo 'to stderr' >&2
+exit 3
+`
+	require.NoError(t, os.WriteFile(script, []byte(content), 0o700)) //nolint:gosec
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	defer cancel()
+
+	outCh := newStreamScript(script, nil).Start(ctx)
+
+	// The script exits after its output, so its health should be reported,
+	// and it should be restarted and its records received again.
+	var got []models.Entity
+	for entity := range outCh {
+		got = append(got, entity)
+		if len(got) == 6 {
+			cancel()
+
+			break
+		}
+	}
+
+	require.Len(t, got, 6)
+
+	for i, entity := range got {
+		switch i % 3 {
+		case 0:
+			sensor, err := entity.AsSensor()
+			require.NoError(t, err)
+			assert.Equal(t, "stream_sensor", sensor.UniqueID)
+		case 1:
+			event, err := entity.AsEvent()
+			require.NoError(t, err)
+			assert.Equal(t, "stream_event", event.Type)
+		case 2:
+			health, err := entity.AsSensor()
+			require.NoError(t, err)
+			assert.Equal(t, "script_stream_sh_health", health.UniqueID)
+			assert.InDelta(t, 3, health.State, 0)
+			assert.InDelta(t, 1, health.Attributes["parse_errors"], 0)
+		}
+	}
+}
+
+func TestStreamScript_StartPipeline(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "pipeline.sh")
+	// The records are written by a subshell and passed through cat, so neither
+	// of the processes holding the output open is the script itself.
+	content := `#!/bin/sh
+(while true; do echo '{"sensor_name": "Pipeline Sensor", "sensor_state": 1}'; sleep 0.1; done) | cat
+`
+	require.NoError(t, os.WriteFile(script, []byte(content), 0o700)) //nolint:gosec
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	outCh := newStreamScript(script, nil).Start(ctx)
+
+	entity := <-outCh
+	assert.True(t, entity.IsSensor())
+
+	// Stopping the script should stop the whole pipeline and close the channel.
+	cancel()
+
+	closed := make(chan struct{})
+	go func() {
+		for range outCh { //nolint:revive // drain any remaining entities.
+		}
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(streamWaitDelay):
+		t.Fatal("streaming script pipeline was not stopped")
+	}
+}
@@ -0,0 +1,450 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package workers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshuar/go-hass-agent/models"
+	"github.com/joshuar/go-hass-agent/scheduler"
+)
+
+func Test_scriptOutput_entities(t *testing.T) {
+	tests := []struct {
+		name          string
+		output        string
+		wantSensors   int
+		wantEvents    int
+		wantLocations int
+		wantErr       error
+	}{
+		{
+			name: "json",
+			output: `{
+  "schedule": "@every 5s",
+  "sensors": [{"sensor_name": "random 1", "sensor_state": 1}],
+  "events": [{"event_type": "dice_rolled", "event_data": {"value": 1}}],
+  "locations": [{"gps": [-33.86, 151.21], "gps_accuracy": 10, "speed": 3}]
+}`,
+			wantSensors:   1,
+			wantEvents:    1,
+			wantLocations: 1,
+		},
+		{
+			name: "yaml",
+			output: `schedule: "@every 5s"
+events:
+  - event_type: dice_rolled
+  - event_type: dice_dropped
+    event_data:
+      where: floor
+locations:
+  - gps: [-33.86, 151.21]
+    gps_accuracy: 10
+`,
+			wantEvents:    2,
+			wantLocations: 1,
+		},
+		{
+			name: "toml",
+			output: `schedule = '@every 5s'
+
+[[sensors]]
+sensor_name = 'random 1'
+sensor_state = 3
+
+[[events]]
+event_type = 'dice_rolled'
+event_data = { value = 3 }
+
+[[locations]]
+gps = [-33.86, 151.21]
+gps_accuracy = 10
+location_name = 'home'
+`,
+			wantSensors:   1,
+			wantEvents:    1,
+			wantLocations: 1,
+		},
+		{
+			name: "invalid event and location",
+			output: `{
+  "schedule": "@every 5s",
+  "sensors": [{"sensor_name": "random 1", "sensor_state": 1}],
+  "events": [{"event_data": {"value": 1}}],
+  "locations": [{"gps": [-33.86], "gps_accuracy": 10}, {"gps": [-33.86, 151.21]}]
+}`,
+			wantSensors: 1,
+			wantErr:     ErrNewLocation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := &scriptOutput{}
+			require.NoError(t, output.Unmarshal([]byte(tt.output)))
+			assert.Equal(t, "@every 5s", output.Schedule)
+
+			got, err := output.entities(t.Context())
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			var sensors, events, locations int
+			for _, entity := range got {
+				switch {
+				case entity.IsSensor():
+					sensors++
+				case entity.IsEvent():
+					events++
+				default:
+					location, err := entity.AsLocation()
+					require.NoError(t, err)
+					assert.Len(t, location.Gps, 2)
+					locations++
+				}
+			}
+
+			assert.Equal(t, tt.wantSensors, sensors)
+			assert.Equal(t, tt.wantEvents, events)
+			assert.Equal(t, tt.wantLocations, locations)
+		})
+	}
+}
+
+func Test_scriptToEntity(t *testing.T) {
+	unavailable := false
+
+	tests := []struct {
+		name   string
+		sensor ScriptSensor
+		want   models.Sensor
+	}{
+		{
+			name:   "defaults",
+			sensor: ScriptSensor{SensorName: "Random Sensor", SensorState: 1},
+			want: models.Sensor{
+				UniqueID: "random_sensor",
+				State:    1,
+				Icon:     "mdi:script",
+			},
+		},
+		{
+			name: "metadata",
+			sensor: ScriptSensor{
+				SensorName:        "Random Sensor",
+				SensorID:          "dice",
+				SensorState:       1,
+				EntityCategory:    "diagnostic",
+				DisabledByDefault: true,
+			},
+			want: models.Sensor{
+				UniqueID:       "dice",
+				State:          1,
+				Icon:           "mdi:script",
+				EntityCategory: models.EntityCategoryDiagnostic,
+				Disabled:       true,
+			},
+		},
+		{
+			name:   "unsupported category",
+			sensor: ScriptSensor{SensorName: "Random Sensor", SensorState: 1, EntityCategory: "config"},
+			want: models.Sensor{
+				UniqueID: "random_sensor",
+				State:    1,
+				Icon:     "mdi:script",
+			},
+		},
+		{
+			name:   "unavailable",
+			sensor: ScriptSensor{SensorName: "Random Sensor", SensorState: 1, Available: &unavailable},
+			want: models.Sensor{
+				UniqueID: "random_sensor",
+				State:    models.StateUnavailable,
+				Icon:     "mdi:script",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := scriptToEntity(t.Context(), tt.sensor).AsSensor()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want.UniqueID, got.UniqueID)
+			assert.EqualValues(t, tt.want.State, got.State)
+			assert.Equal(t, tt.want.Icon, got.Icon)
+			assert.Equal(t, tt.want.EntityCategory, got.EntityCategory)
+			assert.Equal(t, tt.want.Disabled, got.Disabled)
+		})
+	}
+}
+
+func TestScript_Run(t *testing.T) {
+	tests := []struct {
+		name            string
+		content         string
+		wantEntities    int
+		wantExitCode    int
+		wantParseErrors int
+		wantErr         bool
+	}{
+		{
+			name: "valid",
+			content: `#!/bin/sh
+echo '{"schedule": "@every 5s", "sensors": [{"sensor_name": "random 1", "sensor_state": 1}]}'
+`,
+			wantEntities: 2,
+		},
+		{
+			name: "invalid event",
+			content: `#!/bin/sh
+echo '{"schedule": "@every 5s", "sensors": [{"sensor_name": "random 1", "sensor_state": 1}], "events": [{}]}'
+`,
+			wantEntities:    2,
+			wantParseErrors: 1,
+			wantErr:         true,
+		},
+		{
+			name: "parse error",
+			content: `#!/bin/sh
+echo '{"schedule": '
+`,
+			wantEntities:    1,
+			wantParseErrors: 1,
+			wantErr:         true,
+		},
+		{
+			name: "failed",
+			content: `#!/bin/sh
+exit 2
+`,
+			wantEntities: 1,
+			wantExitCode: 2,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "test.sh")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o700)) //nolint:gosec
+
+			script := &Script{path: path}
+
+			got, err := script.Run(t.Context())
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Len(t, got, tt.wantEntities)
+
+			health, err := got[len(got)-1].AsSensor()
+			require.NoError(t, err)
+			assert.Equal(t, "script_test_sh_health", health.UniqueID)
+			assert.Equal(t, models.EntityCategoryDiagnostic, health.EntityCategory)
+			assert.InDelta(t, tt.wantExitCode, health.State, 0)
+			assert.InDelta(t, tt.wantParseErrors, health.Attributes["parse_errors"], 0)
+		})
+	}
+}
+
+func TestScriptWorker_reload(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	require.NoError(t, scheduler.Start(ctx))
+
+	dir := t.TempDir()
+	script := filepath.Join(dir, "dice.sh")
+	writeScript := func(state int, modTime time.Time) {
+		content := fmt.Sprintf(`#!/bin/sh
+echo '{"schedule": "@every 1h", "sensors": [{"sensor_name": "Dice", "sensor_state": %d}]}'
+`, state)
+		require.NoError(t, os.WriteFile(script, []byte(content), 0o700)) //nolint:gosec
+		require.NoError(t, os.Chtimes(script, modTime, modTime))
+	}
+
+	worker := &ScriptWorker{
+		WorkerMetadata: models.SetWorkerMetadata(scriptWorkerID, scriptWorkerDesc),
+		path:           dir,
+		scripts:        make(map[string]*managedScript),
+		prefs:          &ScriptPrefs{},
+	}
+	outCh, err := worker.Start(ctx)
+	require.NoError(t, err)
+
+	// waitFor reloads the scripts and waits for the reload to finish and the
+	// dice sensor to have the given state.
+	waitFor := func(state any) {
+		t.Helper()
+
+		reloaded := make(chan error)
+		go func() {
+			reloaded <- worker.reload(ctx)
+		}()
+
+		var found, done bool
+
+		timeout := time.After(10 * time.Second)
+
+		for !found || !done {
+			select {
+			case err := <-reloaded:
+				require.NoError(t, err)
+
+				done = true
+			case entity := <-outCh:
+				sensor, err := entity.AsSensor()
+				require.NoError(t, err)
+
+				if sensor.UniqueID == "dice" && assert.ObjectsAreEqualValues(state, sensor.State) {
+					found = true
+				}
+			case <-timeout:
+				require.Failf(t, "timed out", "waiting for state %v", state)
+			}
+		}
+	}
+
+	now := time.Now()
+
+	// Added.
+	writeScript(1, now)
+	waitFor(1)
+	// Changed.
+	writeScript(2, now.Add(time.Minute))
+	waitFor(2)
+	// Removed.
+	require.NoError(t, os.Remove(script))
+	waitFor(models.StateUnavailable)
+
+	worker.mu.Lock()
+	assert.Empty(t, worker.scripts)
+	worker.mu.Unlock()
+}
+
+func TestScriptWorker_watchCreated(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	require.NoError(t, scheduler.Start(ctx))
+
+	// The scripts directory does not exist when the worker starts.
+	dir := filepath.Join(t.TempDir(), "scripts")
+	worker := &ScriptWorker{
+		WorkerMetadata: models.SetWorkerMetadata(scriptWorkerID, scriptWorkerDesc),
+		path:           dir,
+		scripts:        make(map[string]*managedScript),
+		prefs:          &ScriptPrefs{},
+	}
+	outCh, err := worker.Start(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, os.Mkdir(dir, 0o700))
+	content := `#!/bin/sh
+echo '{"schedule": "@every 1h", "sensors": [{"sensor_name": "Created", "sensor_state": 1}]}'
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "created.sh"), []byte(content), 0o700)) //nolint:gosec
+
+	timeout := time.After(10 * time.Second)
+
+	for {
+		select {
+		case entity := <-outCh:
+			sensor, err := entity.AsSensor()
+			require.NoError(t, err)
+
+			if sensor.UniqueID == "created" {
+				return
+			}
+		case <-timeout:
+			require.FailNow(t, "timed out waiting for script in created scripts directory")
+		}
+	}
+}
+
+func TestScriptWorker_reloadSlowConsumer(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	require.NoError(t, scheduler.Start(ctx))
+
+	dir := t.TempDir()
+	script := filepath.Join(dir, "slow.sh")
+	content := `#!/bin/sh
+echo '{"schedule": "@every 1h", "sensors": [{"sensor_name": "Slow", "sensor_state": 1}]}'
+`
+	require.NoError(t, os.WriteFile(script, []byte(content), 0o700)) //nolint:gosec
+
+	worker := &ScriptWorker{
+		WorkerMetadata: models.SetWorkerMetadata(scriptWorkerID, scriptWorkerDesc),
+		path:           dir,
+		scripts:        make(map[string]*managedScript),
+		prefs:          &ScriptPrefs{},
+	}
+	outCh, err := worker.Start(ctx)
+	require.NoError(t, err)
+
+	reloaded := make(chan error, 1)
+	go func() {
+		reloaded <- worker.reload(ctx)
+	}()
+	require.NoError(t, <-reloaded)
+
+	// Wait for the sensor to be sent, so that it is marked as unavailable when
+	// the script is removed.
+	timeout := time.After(10 * time.Second)
+	for found := false; !found; {
+		select {
+		case entity := <-outCh:
+			sensor, err := entity.AsSensor()
+			require.NoError(t, err)
+			found = sensor.UniqueID == "slow"
+		case <-timeout:
+			require.FailNow(t, "timed out waiting for script sensor")
+		}
+	}
+
+	require.NoError(t, os.Remove(script))
+	go func() {
+		reloaded <- worker.reload(ctx)
+	}()
+
+	// While the reload waits for the unavailable sensor to be read, the worker
+	// lock should not be held.
+	assert.Never(t, func() bool {
+		if worker.mu.TryLock() {
+			worker.mu.Unlock()
+			return false
+		}
+		return true
+	}, 500*time.Millisecond, 10*time.Millisecond)
+
+	var unavailable bool
+	for {
+		select {
+		case entity := <-outCh:
+			sensor, err := entity.AsSensor()
+			require.NoError(t, err)
+			if sensor.UniqueID == "slow" {
+				unavailable = sensor.State == models.StateUnavailable
+			}
+		case err := <-reloaded:
+			require.NoError(t, err)
+			assert.True(t, unavailable)
+			return
+		case <-timeout:
+			require.FailNow(t, "timed out waiting for reload")
+		}
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	slogctx "github.com/veqryn/slog-context"
+
+	"github.com/joshuar/go-hass-agent/agent/workers/mqtt"
+	"github.com/joshuar/go-hass-agent/config"
+	"github.com/joshuar/go-hass-agent/models"
+)
+
+// SinksConfigPrefix is the prefix in the configuration file for the sink
+// preferences of entity workers.
+const SinksConfigPrefix = "sinks"
+
+const (
+	// SinkWebhook sends entities to Home Assistant through the mobile_app
+	// webhook.
+	SinkWebhook Sink = "webhook"
+	// SinkMQTT publishes sensors over MQTT.
+	SinkMQTT Sink = "mqtt"
+	// SinkBoth sends entities through the webhook and also publishes sensors
+	// over MQTT.
+	SinkBoth Sink = "both"
+)
+
+// ErrInvalidSink indicates an unknown sink was specified in the preferences.
+var ErrInvalidSink = errors.New("invalid sink")
+
+// Sink is where the entities of an entity worker are sent.
+type Sink string
+
+// Valid returns whether the sink is a known sink.
+func (s Sink) Valid() bool {
+	switch s {
+	case SinkWebhook, SinkMQTT, SinkBoth:
+		return true
+	default:
+		return false
+	}
+}
+
+// Sinks contains the sink preferences for entity workers. Workers use the
+// default sink, unless a sink is specified for the worker (by its id). Events
+// are always sent through the webhook and are additionally published over
+// MQTT according to the event mode.
+type Sinks struct {
+	Default Sink            `toml:"default"`
+	Workers map[string]Sink `toml:"workers"`
+	Events  mqtt.EventMode  `toml:"mqtt_events"`
+}
+
+// LoadSinks loads the sink preferences of entity workers. If no preferences
+// have been set, all workers use the webhook sink and events are also
+// published as MQTT device triggers.
+func LoadSinks() (*Sinks, error) {
+	sinks := &Sinks{Default: SinkWebhook, Events: mqtt.EventsAsTriggers}
+	if !config.Exists(SinksConfigPrefix) {
+		return sinks, nil
+	}
+	if err := config.Load(SinksConfigPrefix, sinks); err != nil {
+		return sinks, fmt.Errorf("unable to load sink preferences: %w", err)
+	}
+	if sinks.Default == "" {
+		sinks.Default = SinkWebhook
+	}
+	if sinks.Events == "" {
+		sinks.Events = mqtt.EventsAsTriggers
+	}
+	if !sinks.Default.Valid() {
+		return &Sinks{Default: SinkWebhook, Events: mqtt.EventsAsTriggers}, fmt.Errorf("%w: %s", ErrInvalidSink, sinks.Default)
+	}
+	for id, sink := range sinks.Workers {
+		if !sink.Valid() {
+			return &Sinks{Default: SinkWebhook, Events: mqtt.EventsAsTriggers}, fmt.Errorf("%w: %s: %s", ErrInvalidSink, id, sink)
+		}
+	}
+	if !sinks.Events.Valid() {
+		return &Sinks{Default: SinkWebhook, Events: mqtt.EventsAsTriggers}, fmt.Errorf("%w: events: %s", ErrInvalidSink, sinks.Events)
+	}
+	return sinks, nil
+}
+
+// For returns the sink for the worker with the given id.
+func (s *Sinks) For(id string) Sink {
+	if s == nil {
+		return SinkWebhook
+	}
+	if sink, found := s.Workers[id]; found {
+		return sink
+	}
+	return s.Default
+}
+
+// EventsToMQTT returns whether events are published over MQTT.
+func (s *Sinks) EventsToMQTT() bool {
+	return s != nil && s.Events != mqtt.EventsDisabled
+}
+
+// UsesMQTT returns whether any worker publishes sensors or events over MQTT.
+func (s *Sinks) UsesMQTT() bool {
+	if s == nil {
+		return false
+	}
+	if s.Default != SinkWebhook || s.EventsToMQTT() {
+		return true
+	}
+	for _, sink := range s.Workers {
+		if sink != SinkWebhook {
+			return true
+		}
+	}
+	return false
+}
+
+// routeEntities sends the entities of the worker with the given id to the sinks
+// for the worker. Sensors for the MQTT sink are sent to the given MQTT channel.
+// Events are always sent to the returned channel (for the webhook) and also to
+// the MQTT channel if requested. All other entities (such as location updates,
+// which are only supported by the webhook) are sent to the returned channel.
+//
+// Sending to the MQTT channel never blocks, so that the worker's entities for
+// the webhook are not held up while the MQTT connection is unavailable. If the
+// MQTT channel is full, the entity is not published over MQTT.
+func routeEntities(ctx context.Context, id string, sink Sink, events bool, workerCh <-chan models.Entity, mqttCh chan<- models.Entity) <-chan models.Entity {
+	if mqttCh == nil || (sink == SinkWebhook && !events) {
+		return workerCh
+	}
+
+	if sink != SinkWebhook {
+		slogctx.FromCtx(ctx).Debug("Publishing worker sensors over MQTT.",
+			slog.String("worker", id),
+			slog.String("sink", string(sink)))
+	}
+
+	outCh := make(chan models.Entity)
+
+	go func() {
+		defer close(outCh)
+
+		// Track whether entities are being dropped, to only log when that
+		// changes.
+		var dropping bool
+
+		for entity := range workerCh {
+			var toMQTT, toWebhook bool
+
+			switch {
+			case entity.IsEvent():
+				toMQTT, toWebhook = events, true
+			case entity.IsSensor():
+				toMQTT, toWebhook = sink != SinkWebhook, sink != SinkMQTT
+			default:
+				toWebhook = true
+			}
+
+			if toMQTT {
+				select {
+				case mqttCh <- entity:
+					if dropping {
+						dropping = false
+						slogctx.FromCtx(ctx).Info("Publishing worker entities over MQTT again.",
+							slog.String("worker", id))
+					}
+				default:
+					if !dropping {
+						dropping = true
+						slogctx.FromCtx(ctx).Warn("MQTT is not keeping up, not publishing worker entities over MQTT.",
+							slog.String("worker", id))
+					}
+				}
+			}
+
+			if toWebhook {
+				select {
+				case outCh <- entity:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return outCh
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package workers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshuar/go-hass-agent/agent/workers/mqtt"
+	"github.com/joshuar/go-hass-agent/models"
+)
+
+func TestSinks_For(t *testing.T) {
+	sinks := &Sinks{
+		Default: SinkWebhook,
+		Workers: map[string]Sink{"cpu_usage": SinkMQTT},
+	}
+
+	assert.Equal(t, SinkMQTT, sinks.For("cpu_usage"))
+	assert.Equal(t, SinkWebhook, sinks.For("other"))
+	assert.True(t, sinks.UsesMQTT())
+
+	var noSinks *Sinks
+	assert.Equal(t, SinkWebhook, noSinks.For("cpu_usage"))
+	assert.False(t, noSinks.UsesMQTT())
+	assert.False(t, (&Sinks{Default: SinkWebhook, Events: mqtt.EventsDisabled}).UsesMQTT())
+	assert.True(t, (&Sinks{Default: SinkWebhook, Events: mqtt.EventsAsTriggers}).UsesMQTT())
+}
+
+func Test_routeEntities(t *testing.T) {
+	tests := []struct {
+		name        string
+		sink        Sink
+		events      bool
+		wantWebhook int
+		wantMQTT    int
+	}{
+		{name: "webhook", sink: SinkWebhook, wantWebhook: 2},
+		{name: "mqtt", sink: SinkMQTT, wantWebhook: 1, wantMQTT: 1},
+		{name: "both", sink: SinkBoth, wantWebhook: 2, wantMQTT: 1},
+		{name: "webhook with events", sink: SinkWebhook, events: true, wantWebhook: 2, wantMQTT: 1},
+		{name: "mqtt with events", sink: SinkMQTT, events: true, wantWebhook: 1, wantMQTT: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+			event, err := models.NewEvent("test_event", map[string]any{"value": 1})
+			require.NoError(t, err)
+
+			workerCh := make(chan models.Entity, 2)
+			workerCh <- models.NewSensor(ctx, models.WithName("Test"), models.WithID("test"), models.WithState(1))
+			workerCh <- event
+			close(workerCh)
+
+			mqttCh := make(chan models.Entity, 2)
+			var gotWebhook int
+			for range routeEntities(ctx, "test", tt.sink, tt.events, workerCh, mqttCh) {
+				gotWebhook++
+			}
+			assert.Equal(t, tt.wantWebhook, gotWebhook)
+			assert.Len(t, mqttCh, tt.wantMQTT)
+		})
+	}
+}
+
+func Test_routeEntities_mqttFull(t *testing.T) {
+	ctx := t.Context()
+
+	workerCh := make(chan models.Entity, 3)
+	for range 3 {
+		workerCh <- models.NewSensor(ctx, models.WithName("Test"), models.WithID("test"), models.WithState(1))
+	}
+	close(workerCh)
+
+	// Nothing reads from the MQTT channel, for example, while the MQTT
+	// connection is unavailable.
+	mqttCh := make(chan models.Entity, 1)
+
+	var gotWebhook int
+	for range routeEntities(ctx, "test", SinkBoth, false, workerCh, mqttCh) {
+		gotWebhook++
+	}
+	assert.Equal(t, 3, gotWebhook)
+	assert.Len(t, mqttCh, 1)
+}
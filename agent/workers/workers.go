@@ -16,6 +16,7 @@ import (
 
 	"github.com/joshuar/go-hass-agent/agent/workers/mqtt"
 	"github.com/joshuar/go-hass-agent/config"
+	"github.com/joshuar/go-hass-agent/metrics"
 	"github.com/joshuar/go-hass-agent/models"
 	"github.com/joshuar/go-hass-agent/scheduler"
 )
@@ -135,8 +136,14 @@ type Manager struct {
 	mu sync.Mutex
 
 	workerCancelFuncs []context.CancelFunc
+	sinks             *Sinks
+	mqttEntityCh      chan<- models.Entity
+	observer          Observer
 }
 
+// Observer watches the entities sent on the given channel, passing them through, unchanged, to the returned channel.
+type Observer func(ctx context.Context, entityCh <-chan models.Entity) <-chan models.Entity
+
 // NewManager creates a new manager object.
 func NewManager() *Manager {
 	return &Manager{
@@ -144,6 +151,26 @@ func NewManager() *Manager {
 	}
 }
 
+// SetSinks sets the sink preferences used for any entity workers started afterwards. Sensors of workers that use the
+// MQTT sink and any events to publish over MQTT are sent to the given channel, rather than (or as well as) being
+// returned by StartEntityWorkers.
+func (m *Manager) SetSinks(sinks *Sinks, mqttEntityCh chan<- models.Entity) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sinks = sinks
+	m.mqttEntityCh = mqttEntityCh
+}
+
+// SetObserver sets the observer used for any entity workers started afterwards. The observer watches all entities of
+// each worker, before they are sent to the sinks for the worker.
+func (m *Manager) SetObserver(observer Observer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.observer = observer
+}
+
 // StartEntityWorkers starts the given EntityWorkers. Any errors will be logged.
 func (m *Manager) StartEntityWorkers(ctx context.Context, workers ...EntityWorker) <-chan models.Entity {
 	m.mu.Lock()
@@ -166,8 +193,14 @@ func (m *Manager) StartEntityWorkers(ctx context.Context, workers ...EntityWorke
 				slog.String("worker", worker.ID()),
 				slog.Any("errors", err))
 		} else {
+			slogctx.FromCtx(ctx).Debug("Started entity worker.",
+				slog.String("worker", worker.ID()))
 			m.workerCancelFuncs = append(m.workerCancelFuncs, cancelFunc)
-			outCh = append(outCh, workerCh)
+			entityCh := metrics.ObserveEntities(workerCtx, worker.ID(), workerCh)
+			if m.observer != nil {
+				entityCh = m.observer(workerCtx, entityCh)
+			}
+			outCh = append(outCh, routeEntities(workerCtx, worker.ID(), m.sinks.For(worker.ID()), m.sinks.EventsToMQTT(), entityCh, m.mqttEntityCh))
 		}
 		go func() {
 			defer cancelFunc()
@@ -269,3 +302,88 @@ func MergeCh[T any](ctx context.Context, inCh ...<-chan T) chan T {
 
 	return outCh
 }
+
+// FanOutCh copies every value received on the given channel to the given number
+// of output channels (channel fan-out). Each output channel is a separate queue,
+// buffered with the given size (minimum 1). Copying never blocks on a consumer:
+// when an output channel is full, its oldest value is dropped to make room, so
+// that a slow or stuck consumer does not hold up the others. The given function
+// (if not nil) is called with the index of the output and the value for every
+// value dropped. All output channels are closed when the input channel is closed
+// or the context is canceled.
+func FanOutCh[T any](ctx context.Context, inCh <-chan T, count, size int, onDrop func(output int, value T)) []<-chan T {
+	outChs := make([]chan T, count)
+	for idx := range outChs {
+		outChs[idx] = make(chan T, max(size, 1))
+	}
+
+	go func() {
+		defer func() {
+			for _, ch := range outChs {
+				close(ch)
+			}
+		}()
+
+		// Track which outputs are dropping values, to only log when that changes.
+		dropping := make([]bool, count)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case value, ok := <-inCh:
+				if !ok {
+					return
+				}
+
+				for idx, ch := range outChs {
+					dropped := offer(ch, value, func(old T) {
+						if onDrop != nil {
+							onDrop(idx, old)
+						}
+					})
+					if dropped != dropping[idx] {
+						dropping[idx] = dropped
+						if dropped {
+							slogctx.FromCtx(ctx).Warn("Output channel is full, dropping oldest values.",
+								slog.Int("output", idx))
+						} else {
+							slogctx.FromCtx(ctx).Info("Output channel is no longer full.",
+								slog.Int("output", idx))
+						}
+					}
+				}
+			}
+		}
+	}()
+
+	results := make([]<-chan T, count)
+	for idx, ch := range outChs {
+		results[idx] = ch
+	}
+
+	return results
+}
+
+// offer sends the given value on the given buffered channel without blocking.
+// If the channel is full, its oldest value is dropped to make room and passed to
+// the given function. It returns a boolean indicating whether a value was
+// dropped.
+func offer[T any](ch chan T, value T, dropFunc func(T)) bool {
+	var dropped bool
+
+	for {
+		select {
+		case ch <- value:
+			return dropped
+		default:
+		}
+
+		select {
+		case old := <-ch:
+			dropped = true
+			dropFunc(old)
+		default:
+		}
+	}
+}
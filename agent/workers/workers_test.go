@@ -0,0 +1,116 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package workers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshuar/go-hass-agent/models"
+)
+
+// staticWorker is an entity worker that sends the given entities.
+type staticWorker struct {
+	id       string
+	entities []models.Entity
+}
+
+func (w *staticWorker) IsDisabled() bool { return false }
+
+func (w *staticWorker) ID() string { return w.id }
+
+func (w *staticWorker) Start(_ context.Context) (<-chan models.Entity, error) {
+	outCh := make(chan models.Entity, len(w.entities))
+	for _, entity := range w.entities {
+		outCh <- entity
+	}
+	close(outCh)
+
+	return outCh, nil
+}
+
+func TestManager_StartEntityWorkers_observer(t *testing.T) {
+	ctx := t.Context()
+
+	mqttCh := make(chan models.Entity, 1)
+	manager := NewManager()
+	manager.SetSinks(&Sinks{Default: SinkMQTT}, mqttCh)
+
+	var observed []string
+	manager.SetObserver(func(ctx context.Context, entityCh <-chan models.Entity) <-chan models.Entity {
+		outCh := make(chan models.Entity)
+		go func() {
+			defer close(outCh)
+			for entity := range entityCh {
+				sensor, err := entity.AsSensor()
+				assert.NoError(t, err)
+				observed = append(observed, sensor.UniqueID)
+				outCh <- entity
+			}
+		}()
+		return outCh
+	})
+
+	worker := &staticWorker{
+		id:       "test",
+		entities: []models.Entity{models.NewSensor(ctx, models.WithName("Test"), models.WithID("test"), models.WithState(1))},
+	}
+
+	// The sensor is only published over MQTT, but it is still observed.
+	assert.Empty(t, drain(manager.StartEntityWorkers(ctx, worker)))
+	assert.Len(t, mqttCh, 1)
+	assert.Equal(t, []string{"test"}, observed)
+}
+
+func TestFanOutCh(t *testing.T) {
+	inCh := make(chan int)
+	outChs := FanOutCh(t.Context(), inCh, 2, 1, nil)
+	require.Len(t, outChs, 2)
+
+	// The second output is never read. That should not stop values being copied to the first output.
+	for value := range 100 {
+		inCh <- value
+		assert.Equal(t, value, <-outChs[0])
+	}
+
+	close(inCh)
+
+	_, ok := <-outChs[0]
+	assert.False(t, ok)
+	// The stuck output should only contain the most recent value.
+	assert.Equal(t, []int{99}, drain(outChs[1]))
+}
+
+func TestFanOutCh_dropsOldest(t *testing.T) {
+	inCh := make(chan int)
+	var dropped []int
+	outChs := FanOutCh(t.Context(), inCh, 2, 3, func(output, value int) {
+		dropped = append(dropped, output*10+value)
+	})
+
+	for value := range 5 {
+		inCh <- value
+	}
+
+	close(inCh)
+
+	for _, outCh := range outChs {
+		assert.Equal(t, []int{2, 3, 4}, drain(outCh))
+	}
+	// The oldest values of each output were dropped.
+	assert.Equal(t, []int{0, 10, 1, 11}, dropped)
+}
+
+// drain returns all values received on the given channel, until it is closed.
+func drain[T any](ch <-chan T) []T {
+	var values []T
+	for value := range ch {
+		values = append(values, value)
+	}
+
+	return values
+}
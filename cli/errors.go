@@ -0,0 +1,63 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package cli
+
+import (
+	"errors"
+
+	"github.com/alecthomas/kong"
+)
+
+// Exit codes returned by commands, which allow scripts to determine why a command failed.
+const (
+	// ExitCodeError is returned for any general failure.
+	ExitCodeError = 1
+	// ExitCodeInvalidOptions is returned when the options given to a command are missing or invalid.
+	ExitCodeInvalidOptions = 2
+	// ExitCodeDiscoveryFailed is returned when a Home Assistant server could not be (unambiguously) discovered.
+	ExitCodeDiscoveryFailed = 3
+	// ExitCodeRegistrationFailed is returned when registration with Home Assistant failed.
+	ExitCodeRegistrationFailed = 4
+)
+
+var _ kong.ExitCoder = (*exitError)(nil)
+
+// exitError is an error with an associated exit code.
+type exitError struct {
+	err  error
+	code int
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// ExitCode returns the exit code for the error.
+func (e *exitError) ExitCode() int {
+	return e.code
+}
+
+// withExitCode wraps the given error so that the command exits with the given code.
+func withExitCode(code int, err error) error {
+	return &exitError{err: err, code: code}
+}
+
+// ExitCode returns the exit code that should be used for the given command error. Errors without an explicit exit
+// code return ExitCodeError.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var coder kong.ExitCoder
+	if errors.As(err, &coder) {
+		return coder.ExitCode()
+	}
+
+	return ExitCodeError
+}
@@ -0,0 +1,45 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{
+			name: "no error",
+			want: 0,
+		},
+		{
+			name: "general error",
+			err:  errors.New("failed"),
+			want: ExitCodeError,
+		},
+		{
+			name: "error with exit code",
+			err:  withExitCode(ExitCodeInvalidOptions, errors.New("invalid")),
+			want: ExitCodeInvalidOptions,
+		},
+		{
+			name: "wrapped error with exit code",
+			err:  fmt.Errorf("register: %w", withExitCode(ExitCodeDiscoveryFailed, errors.New("no servers"))),
+			want: ExitCodeDiscoveryFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ExitCode(tt.err))
+		})
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/joshuar/go-hass-agent/config"
+	"github.com/joshuar/go-hass-agent/hass/notifications"
+)
+
+// NotificationsCmd contains the command-line options for working with the notification history.
+type NotificationsCmd struct {
+	List  ListNotificationsCmd  `cmd:"" help:"List received notifications."`
+	Clear ClearNotificationsCmd `cmd:"" help:"Clear the notification history."`
+}
+
+// ListNotificationsCmd lists notifications from the notification history.
+type ListNotificationsCmd struct {
+	Search string `short:"s" help:"Only show notifications whose title, message or action contain this text."`
+	Limit  int    `short:"n" help:"Show at most this many notifications (0 for all)." default:"0"`
+	JSON   bool   `help:"Output notifications as JSON."`
+}
+
+// Run lists the notification history.
+func (r *ListNotificationsCmd) Run() error {
+	history, err := notifications.Load(config.GetPath())
+	if err != nil {
+		return fmt.Errorf("list notifications: %w", err)
+	}
+
+	records := history.Search(r.Search)
+	if r.Limit > 0 && len(records) > r.Limit {
+		records = records[:r.Limit]
+	}
+
+	if r.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(records); err != nil {
+			return fmt.Errorf("list notifications: %w", err)
+		}
+		return nil
+	}
+
+	for _, record := range records {
+		fmt.Printf("[%s] %s: %s (displayed: %t", record.Received.Format(time.DateTime), record.Title, record.Message,
+			record.Displayed)
+		if record.Action != "" {
+			fmt.Printf(", action: %s", record.Action)
+		}
+		fmt.Println(")")
+	}
+
+	return nil
+}
+
+// ClearNotificationsCmd removes all notifications from the notification history.
+type ClearNotificationsCmd struct{}
+
+// Run clears the notification history.
+func (r *ClearNotificationsCmd) Run() error {
+	history, err := notifications.Load(config.GetPath())
+	if err != nil {
+		return fmt.Errorf("clear notifications: %w", err)
+	}
+
+	if err := history.Clear(); err != nil {
+		return fmt.Errorf("clear notifications: %w", err)
+	}
+
+	return nil
+}
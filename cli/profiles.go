@@ -0,0 +1,78 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package cli
+
+import (
+	"fmt"
+
+	"github.com/joshuar/go-hass-agent/config"
+	"github.com/joshuar/go-hass-agent/hass"
+)
+
+// ProfilesCmd contains the command-line options for managing server profiles.
+type ProfilesCmd struct {
+	List    ListProfilesCmd   `cmd:"" help:"List server profiles."`
+	Enable  EnableProfileCmd  `cmd:"" help:"Enable sending data to the server of a profile."`
+	Disable DisableProfileCmd `cmd:"" help:"Disable sending data to the server of a profile."`
+}
+
+// ListProfilesCmd lists all server profiles.
+type ListProfilesCmd struct{}
+
+// Run lists the server profiles.
+func (r *ListProfilesCmd) Run() error {
+	profiles, err := hass.Profiles()
+	if err != nil {
+		return fmt.Errorf("list profiles: %w", err)
+	}
+
+	for _, profile := range profiles {
+		server, _ := config.Get[string](profile.ConfigPrefix() + "registration.server") //nolint:errcheck // zero value is fine.
+		if profile.IsDefault() {
+			fmt.Printf("%s: %s\n", profile.Name, server)
+			continue
+		}
+		fmt.Printf("%s: %s (Registered: %t, Enabled: %t)\n", profile.Name, server, profile.Registered, profile.Enabled)
+	}
+
+	return nil
+}
+
+// EnableProfileCmd enables a server profile.
+type EnableProfileCmd struct {
+	Name string `arg:"" help:"Name of the profile."`
+}
+
+// Run enables the server profile.
+func (r *EnableProfileCmd) Run() error {
+	return setProfileEnabled(r.Name, true)
+}
+
+// DisableProfileCmd disables a server profile.
+type DisableProfileCmd struct {
+	Name string `arg:"" help:"Name of the profile."`
+}
+
+// Run disables the server profile.
+func (r *DisableProfileCmd) Run() error {
+	return setProfileEnabled(r.Name, false)
+}
+
+func setProfileEnabled(name string, enabled bool) error {
+	profile, err := hass.GetProfile(name)
+	if err != nil {
+		return fmt.Errorf("set profile state: %w", err)
+	}
+
+	if profile.IsDefault() {
+		return fmt.Errorf("set profile state: %w: the default profile cannot be enabled/disabled", hass.ErrInvalidProfile)
+	}
+
+	profile.Enabled = enabled
+	if err := profile.Save(); err != nil {
+		return fmt.Errorf("set profile state: %w", err)
+	}
+
+	return nil
+}
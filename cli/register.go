@@ -5,11 +5,14 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	slogctx "github.com/veqryn/slog-context"
 
@@ -18,13 +21,30 @@ import (
 	"github.com/joshuar/go-hass-agent/hass"
 )
 
+// RegistrationOptions are the options common to commands that register with Home Assistant.
+type RegistrationOptions struct {
+	hass.RegistrationRequest
+
+	Profile          string        `help:"Register with an additional Home Assistant server, using the given profile name." name:"server-profile"`
+	TokenFile        string        `help:"Path to a file containing the Personal Access Token (instead of --token)."       env:"GOHASSAGENT_TOKEN_FILE"`
+	Discover         bool          `help:"Search the local network for a Home Assistant server, if no server is given."      env:"GOHASSAGENT_DISCOVER"`
+	DiscoveryTimeout time.Duration `help:"How long to search the local network for Home Assistant servers."                 default:"5s"`
+}
+
 // Register represents the options for the `register` command.
 type Register struct {
-	hass.RegistrationRequest
+	RegistrationOptions
 
 	Force bool `help:"Force registration."`
 }
 
+var (
+	// ErrNoServerFound is returned when discovery does not find any Home Assistant servers.
+	ErrNoServerFound = errors.New("no Home Assistant server found")
+	// ErrMultipleServersFound is returned when discovery finds more than one Home Assistant server.
+	ErrMultipleServersFound = errors.New("multiple Home Assistant servers found")
+)
+
 // Run processes the register command.
 func (r *Register) Run(_ *Opts) error {
 	ctx, cancelFunc := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
@@ -37,15 +57,19 @@ func (r *Register) Run(_ *Opts) error {
 		return fmt.Errorf("unable to run: %w", err)
 	}
 
+	// Registering an additional server profile is handled separately.
+	if r.Profile != "" && r.Profile != hass.DefaultProfileName {
+		return r.registerProfile(ctx)
+	}
+
 	// Don't continue if agent is registered unless force option is set.
 	if agent.IsRegistered() && !r.Force {
 		slogctx.FromCtx(ctx).Warn("Already registered and force not set.")
 		return nil
 	}
 
-	// Validate registration options.
-	valid, err := r.Valid()
-	if !valid || err != nil {
+	// Resolve and validate registration options.
+	if err := r.resolve(ctx); err != nil {
 		return fmt.Errorf("unable to register: %w", err)
 	}
 
@@ -58,7 +82,7 @@ func (r *Register) Run(_ *Opts) error {
 	// Perform registration.
 	err = hass.Register(ctx, deviceCfg.ID, &r.RegistrationRequest)
 	if err != nil {
-		return fmt.Errorf("unable to register: %w", err)
+		return withExitCode(ExitCodeRegistrationFailed, fmt.Errorf("unable to register: %w", err))
 	}
 
 	// If force option set, reset the agent.
@@ -78,3 +102,143 @@ func (r *Register) Run(_ *Opts) error {
 
 	return nil
 }
+
+// registerProfile handles registering an additional server profile.
+func (r *Register) registerProfile(ctx context.Context) error {
+	profile, err := hass.GetProfile(r.Profile)
+	switch {
+	case errors.Is(err, hass.ErrUnknownProfile):
+		profile, err = hass.NewProfile(r.Profile)
+		if err != nil {
+			return withExitCode(ExitCodeInvalidOptions, fmt.Errorf("unable to register: %w", err))
+		}
+	case err != nil:
+		return fmt.Errorf("unable to register: %w", err)
+	}
+
+	ctx = slogctx.With(ctx, slog.String("profile", profile.Name))
+
+	// Don't continue if profile is registered unless force option is set.
+	if profile.Registered && !r.Force {
+		slogctx.FromCtx(ctx).Warn("Profile already registered and force not set.")
+		return nil
+	}
+
+	// Resolve and validate registration options.
+	if err := r.resolve(ctx); err != nil {
+		return fmt.Errorf("unable to register: %w", err)
+	}
+
+	// Get the device config.
+	deviceCfg, err := device.GetConfig()
+	if err != nil {
+		return fmt.Errorf("unable to register: get device details failed: %w", err)
+	}
+
+	// Perform registration.
+	if err := profile.Register(ctx, deviceCfg.ID, &r.RegistrationRequest); err != nil {
+		return withExitCode(ExitCodeRegistrationFailed, fmt.Errorf("unable to register: %w", err))
+	}
+
+	// If force option set, reset the profile registry.
+	if r.Force {
+		if err := profile.Reset(); err != nil {
+			slogctx.FromCtx(ctx).Warn("Could not reset registry state.",
+				slog.Any("error", err))
+		}
+	}
+
+	slogctx.FromCtx(ctx).Info("Profile registered! Restart the agent to start sending data to this server.")
+
+	return nil
+}
+
+// resolve fills in any registration details not given directly on the command-line, reading the token from a file
+// and discovering the server if requested. The resulting details are then validated.
+func (o *RegistrationOptions) resolve(ctx context.Context) error {
+	if err := o.resolveToken(); err != nil {
+		return err
+	}
+
+	if err := o.resolveServer(ctx); err != nil {
+		return err
+	}
+
+	if valid, err := o.Valid(); !valid || err != nil {
+		return withExitCode(ExitCodeInvalidOptions, err)
+	}
+
+	return nil
+}
+
+// resolveToken reads the token from the token file, if one was given.
+func (o *RegistrationOptions) resolveToken() error {
+	if o.TokenFile == "" {
+		return nil
+	}
+
+	if o.Token != "" {
+		return withExitCode(ExitCodeInvalidOptions, errors.New("only one of token or token file can be given"))
+	}
+
+	token, err := readTokenFile(o.TokenFile)
+	if err != nil {
+		return withExitCode(ExitCodeInvalidOptions, err)
+	}
+
+	o.Token = token
+
+	return nil
+}
+
+// resolveServer discovers the server, if no server was given and discovery was requested.
+func (o *RegistrationOptions) resolveServer(ctx context.Context) error {
+	if o.Server != "" || !o.Discover {
+		return nil
+	}
+
+	server, err := discoverServer(ctx, o.DiscoveryTimeout)
+	if err != nil {
+		return withExitCode(ExitCodeDiscoveryFailed, err)
+	}
+
+	slogctx.FromCtx(ctx).Info("Using discovered Home Assistant server.",
+		slog.String("server", server))
+
+	o.Server = server
+
+	return nil
+}
+
+// readTokenFile reads a token from the given file. Any surrounding whitespace is removed.
+func readTokenFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read token file: %w", err)
+	}
+
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("read token file: %s is empty", path)
+	}
+
+	return token, nil
+}
+
+// discoverServer searches the local network for a Home Assistant server. Exactly one server must be found, so that
+// registration never silently picks the wrong server.
+func discoverServer(ctx context.Context, timeout time.Duration) (string, error) {
+	servers, err := hass.DiscoverServers(ctx, timeout)
+	if err != nil {
+		return "", err
+	}
+
+	switch len(servers) {
+	case 0:
+		return "", ErrNoServerFound
+	case 1:
+		return servers[0], nil
+	default:
+		return "", fmt.Errorf("%w: %s: use --server to choose one", ErrMultipleServersFound, strings.Join(servers, ", "))
+	}
+}
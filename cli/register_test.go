@@ -0,0 +1,86 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshuar/go-hass-agent/hass"
+)
+
+func TestRegistrationOptions_resolve(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("  abcd1234\n"), 0o600))
+	emptyFile := filepath.Join(dir, "empty")
+	require.NoError(t, os.WriteFile(emptyFile, []byte("\n"), 0o600))
+
+	tests := []struct {
+		name      string
+		options   *RegistrationOptions
+		wantToken string
+		wantCode  int
+	}{
+		{
+			name: "token from flag",
+			options: &RegistrationOptions{
+				RegistrationRequest: hass.RegistrationRequest{Server: "http://localhost:8123", Token: "abcd1234"},
+			},
+			wantToken: "abcd1234",
+		},
+		{
+			name: "token from file",
+			options: &RegistrationOptions{
+				RegistrationRequest: hass.RegistrationRequest{Server: "http://localhost:8123"},
+				TokenFile:           tokenFile,
+			},
+			wantToken: "abcd1234",
+		},
+		{
+			name: "token and token file",
+			options: &RegistrationOptions{
+				RegistrationRequest: hass.RegistrationRequest{Server: "http://localhost:8123", Token: "abcd1234"},
+				TokenFile:           tokenFile,
+			},
+			wantCode: ExitCodeInvalidOptions,
+		},
+		{
+			name: "empty token file",
+			options: &RegistrationOptions{
+				RegistrationRequest: hass.RegistrationRequest{Server: "http://localhost:8123"},
+				TokenFile:           emptyFile,
+			},
+			wantCode: ExitCodeInvalidOptions,
+		},
+		{
+			name: "missing token file",
+			options: &RegistrationOptions{
+				RegistrationRequest: hass.RegistrationRequest{Server: "http://localhost:8123"},
+				TokenFile:           filepath.Join(dir, "missing"),
+			},
+			wantCode: ExitCodeInvalidOptions,
+		},
+		{
+			name: "no server",
+			options: &RegistrationOptions{
+				RegistrationRequest: hass.RegistrationRequest{Token: "abcd1234"},
+			},
+			wantCode: ExitCodeInvalidOptions,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.options.resolve(t.Context())
+			assert.Equal(t, tt.wantCode, ExitCode(err))
+			if tt.wantCode == 0 {
+				assert.Equal(t, tt.wantToken, tt.options.Token)
+			}
+		})
+	}
+}
@@ -6,7 +6,7 @@ package cli
 import (
 	"fmt"
 
-	"github.com/joshuar/go-hass-agent/config"
+	"github.com/joshuar/go-hass-agent/hass"
 	"github.com/joshuar/go-hass-agent/hass/registry"
 )
 
@@ -15,11 +15,18 @@ type RegistryCmd struct {
 	List ListRegistryCmd `cmd:"" help:"List local registry."`
 }
 
-type ListRegistryCmd struct{}
+type ListRegistryCmd struct {
+	Profile string `name:"server-profile" help:"List the registry of the given server profile."`
+}
 
 // Run starts the agent.
 func (r *ListRegistryCmd) Run() error {
-	reg, err := registry.Load(config.GetPath())
+	profile, err := hass.GetProfile(r.Profile)
+	if err != nil {
+		return fmt.Errorf("load registry: %w", err)
+	}
+
+	reg, err := registry.Load(profile.RegistryPath())
 	if err != nil {
 		return fmt.Errorf("load registry: %w", err)
 	}
@@ -0,0 +1,209 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	slogctx "github.com/veqryn/slog-context"
+
+	"github.com/joshuar/go-hass-agent/agent"
+	"github.com/joshuar/go-hass-agent/config"
+	"github.com/joshuar/go-hass-agent/device"
+	"github.com/joshuar/go-hass-agent/hass"
+)
+
+// ResetOptions are the options common to commands that reset the agent registration.
+type ResetOptions struct {
+	ResetMQTT      bool `help:"Also remove any entities published via MQTT from Home Assistant." name:"reset-mqtt"`
+	RotateDeviceID bool `help:"Generate a new device ID. Home Assistant will treat the agent as a new device."`
+}
+
+// Reset represents the options for the `reset` command.
+type Reset struct {
+	ResetOptions
+
+	Profile string `help:"Reset the registration of the given server profile." name:"server-profile"`
+}
+
+// Help shows a help message about the reset command.
+func (r *Reset) Help() string {
+	return "Reset the registration of Go Hass Agent. The agent will need to be registered again before it sends any data."
+}
+
+// Run processes the reset command.
+func (r *Reset) Run(_ *Opts) error {
+	ctx, cancelFunc := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancelFunc()
+	ctx = slogctx.NewCtx(ctx, slog.Default())
+
+	profile, err := hass.GetProfile(r.Profile)
+	if err != nil {
+		return withExitCode(ExitCodeInvalidOptions, fmt.Errorf("unable to reset: %w", err))
+	}
+	ctx = slogctx.With(ctx, slog.String("profile", profile.Name))
+
+	agent, err := agent.New()
+	if err != nil {
+		return fmt.Errorf("unable to reset: %w", err)
+	}
+
+	if err := r.prepare(ctx, profile); err != nil {
+		return fmt.Errorf("unable to reset: %w", err)
+	}
+
+	if err := profile.Unregister(); err != nil {
+		return fmt.Errorf("unable to reset: %w", err)
+	}
+
+	if profile.IsDefault() {
+		agent.Reset(ctx)
+	}
+
+	slogctx.FromCtx(ctx).Info("Registration reset. Use the register command to register again.")
+
+	return nil
+}
+
+// prepare will remove any MQTT entities and generate a new device ID, if requested. MQTT entities are published with
+// the device ID, so they are removed first.
+func (o *ResetOptions) prepare(ctx context.Context, profile *hass.Profile) error {
+	if o.ResetMQTT {
+		if profile.IsDefault() {
+			if err := agent.ResetMQTT(ctx); err != nil {
+				return err
+			}
+		} else {
+			slogctx.FromCtx(ctx).Warn("MQTT is only used with the default profile, not resetting MQTT.")
+		}
+	}
+
+	if o.RotateDeviceID {
+		if err := device.NewConfig(); err != nil {
+			return fmt.Errorf("rotate device id: %w", err)
+		}
+
+		slogctx.FromCtx(ctx).Info("Generated new device ID.")
+	}
+
+	return nil
+}
+
+// Reregister represents the options for the `reregister` command.
+type Reregister struct {
+	RegistrationOptions
+	ResetOptions
+}
+
+// Help shows a help message about the reregister command.
+func (r *Reregister) Help() string {
+	return `Register Go Hass Agent again, for example, after Home Assistant has moved to a new address.
+
+If the server is the same Home Assistant instance the agent is already registered with, the existing registration
+(including the webhook ID and entity IDs) is kept and only its address is updated. Otherwise, the agent is registered
+again. Any server or token not given will be taken from the existing registration.`
+}
+
+// Run processes the reregister command.
+func (r *Reregister) Run(_ *Opts) error {
+	ctx, cancelFunc := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancelFunc()
+	ctx = slogctx.NewCtx(ctx, slog.Default())
+
+	profile, err := hass.GetProfile(r.Profile)
+	if err != nil {
+		return withExitCode(ExitCodeInvalidOptions, fmt.Errorf("unable to reregister: %w", err))
+	}
+	ctx = slogctx.With(ctx, slog.String("profile", profile.Name))
+
+	agent, err := agent.New()
+	if err != nil {
+		return fmt.Errorf("unable to reregister: %w", err)
+	}
+
+	if (profile.IsDefault() && !agent.IsRegistered()) || (!profile.IsDefault() && !profile.Registered) {
+		return withExitCode(ExitCodeInvalidOptions,
+			errors.New("unable to reregister: not registered, use the register command instead"))
+	}
+
+	// Resolve the new registration details, falling back to the existing ones.
+	if err := r.resolveToken(); err != nil {
+		return fmt.Errorf("unable to reregister: %w", err)
+	}
+	if err := r.resolveServer(ctx); err != nil {
+		return fmt.Errorf("unable to reregister: %w", err)
+	}
+	r.useExisting(profile)
+
+	// Try to keep the existing registration, unless a new device ID was requested (which requires a new registration).
+	if !r.RotateDeviceID {
+		err := profile.Migrate(ctx, &r.RegistrationRequest)
+		switch {
+		case err == nil:
+			// Keeping the registration does not rotate the device ID, but any MQTT entities should still be removed
+			// if requested.
+			if err := r.prepare(ctx, profile); err != nil {
+				return fmt.Errorf("registration moved but unable to reset MQTT: %w", err)
+			}
+			slogctx.FromCtx(ctx).Info("Registration moved to new server address. Restart the agent to use it.",
+				slog.String("server", r.Server))
+			return nil
+		case errors.Is(err, hass.ErrRegistrationNotFound):
+			slogctx.FromCtx(ctx).Info("Existing registration not found on server, registering again.",
+				slog.String("server", r.Server))
+		default:
+			return withExitCode(ExitCodeRegistrationFailed, fmt.Errorf("unable to reregister: %w", err))
+		}
+	}
+
+	if valid, err := r.Valid(); !valid || err != nil {
+		return withExitCode(ExitCodeInvalidOptions, fmt.Errorf("unable to reregister: %w", err))
+	}
+
+	if err := r.prepare(ctx, profile); err != nil {
+		return fmt.Errorf("unable to reregister: %w", err)
+	}
+
+	deviceCfg, err := device.GetConfig()
+	if err != nil {
+		return fmt.Errorf("unable to reregister: get device details failed: %w", err)
+	}
+
+	if err := profile.Register(ctx, deviceCfg.ID, &r.RegistrationRequest); err != nil {
+		return withExitCode(ExitCodeRegistrationFailed, fmt.Errorf("unable to reregister: %w", err))
+	}
+
+	// The new registration has a new webhook, so all entities need to be registered again.
+	if err := profile.Reset(); err != nil {
+		slogctx.FromCtx(ctx).Warn("Could not reset registry state.",
+			slog.Any("error", err))
+	}
+
+	if profile.IsDefault() {
+		agent.Reset(ctx)
+		agent.Register(ctx)
+	}
+
+	slogctx.FromCtx(ctx).Info("Registered again. Restart the agent to start sending data.",
+		slog.String("server", r.Server))
+
+	return nil
+}
+
+// useExisting fills in any server or token not given with those of the existing registration of the profile.
+func (r *Reregister) useExisting(profile *hass.Profile) {
+	if r.Server == "" {
+		r.Server, _ = config.Get[string](profile.ConfigPrefix() + "registration.server") //nolint:errcheck // zero value is fine.
+	}
+
+	if r.Token == "" {
+		r.Token, _ = config.Get[string](profile.ConfigPrefix() + "registration.token") //nolint:errcheck // zero value is fine.
+	}
+}
@@ -27,6 +27,7 @@ type Run struct {
 	ServerHTTPSKey  string `help:"Path to key file for using https for web server component."`
 	ServerHostname  string `help:"Hostname that web server component will listen on."          default:"localhost"`
 	ServerPort      string `help:"Port that web server component will listen on."              default:"8223"`
+	ServerMetrics   bool   `help:"Expose Prometheus/OpenMetrics metrics at /metrics on the web server component."`
 }
 
 // Help shows a help message about the run command.
@@ -65,6 +66,7 @@ func (r *Run) Run(opts *Opts) error {
 		server.WithPort(r.ServerPort),
 		server.WithCertFile(r.ServerHTTPSCert),
 		server.WithKeyFile(r.ServerHTTPSKey),
+		server.WithMetrics(r.ServerMetrics),
 	)
 	if err != nil {
 		return fmt.Errorf("unable to run: %w", err)
@@ -79,7 +81,10 @@ func (r *Run) Run(opts *Opts) error {
 
 	if !agent.IsRegistered() {
 		xdgOpen, err := exec.LookPath("xdg-open")
-		if err != nil {
+		if !hasDisplay() {
+			slogctx.FromCtx(ctx).
+				Info("Agent is not registered. Please register with `go-hass-agent register --server URL --token TOKEN` or open your web browser to " + server.ShowAddress() + "/register to register the agent with Home Assistant")
+		} else if err != nil {
 			slogctx.FromCtx(ctx).
 				Info("Agent is not registered. Please open your web browser to " + server.ShowAddress() + "/register to register the agent with Home Assistant")
 		} else {
@@ -99,3 +104,9 @@ func (r *Run) Run(opts *Opts) error {
 
 	return nil
 }
+
+// hasDisplay returns whether the agent appears to be running in a graphical session, where a web browser could be
+// opened.
+func hasDisplay() bool {
+	return os.Getenv("DISPLAY") != "" || os.Getenv("WAYLAND_DISPLAY") != ""
+}
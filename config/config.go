@@ -171,6 +171,14 @@ func Exists(path string) bool {
 	return globalConfig.src.Exists(path)
 }
 
+// Keys returns the keys of the map located at the given path in the config. It returns an empty slice if the path
+// does not exist or is not a map.
+func Keys(path string) []string {
+	globalConfig.mu.Lock()
+	defer globalConfig.mu.Unlock()
+	return globalConfig.src.MapKeys(path)
+}
+
 // save will save the new values of the specified preferences to the existing
 // preferences file.
 func save() error {
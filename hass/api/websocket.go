@@ -58,18 +58,38 @@ type Websocket struct {
 	nextID    uint64
 }
 
+// WebsocketOption is a functional option for the websocket.
+type WebsocketOption func(*websocketOptions)
+
+type websocketOptions struct {
+	configPrefix string
+}
+
+// WithConfigPrefix option sets a prefix to be prepended to the preferences paths from which the websocket url,
+// webhookid and token are loaded. This is used for connecting to servers other than the default.
+func WithConfigPrefix(prefix string) WebsocketOption {
+	return func(o *websocketOptions) {
+		o.configPrefix = prefix
+	}
+}
+
 // NewWebsocket creates a new websocket object using the given websocket url,
 // webhookid and token.
-func NewWebsocket(ctx context.Context) (*Websocket, error) {
+func NewWebsocket(ctx context.Context, options ...WebsocketOption) (*Websocket, error) {
+	opts := &websocketOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+
 	var hassCfg hassConfig
 	// Load the hass config.
-	err := config.Load(hassConfigPrefix, &hassCfg)
+	err := config.Load(opts.configPrefix+hassConfigPrefix, &hassCfg)
 	if err != nil {
 		return nil, fmt.Errorf("unable to load hass config: %w", err)
 	}
 	var regCfg regConfig
 	// Load the registration config.
-	err = config.Load(regConfigPrefix, &regCfg)
+	err = config.Load(opts.configPrefix+regConfigPrefix, &regCfg)
 	if err != nil {
 		return nil, fmt.Errorf("unable to load registration config: %w", err)
 	}
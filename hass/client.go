@@ -9,13 +9,13 @@ import (
 	"fmt"
 	"log/slog"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/reugn/go-quartz/job"
 	"github.com/reugn/go-quartz/quartz"
 	slogctx "github.com/veqryn/slog-context"
 
-	"github.com/joshuar/go-hass-agent/config"
 	"github.com/joshuar/go-hass-agent/hass/api"
 	"github.com/joshuar/go-hass-agent/hass/event"
 	"github.com/joshuar/go-hass-agent/hass/location"
@@ -23,6 +23,7 @@ import (
 	"github.com/joshuar/go-hass-agent/hass/sensor"
 	"github.com/joshuar/go-hass-agent/hass/tracker"
 	"github.com/joshuar/go-hass-agent/logging"
+	"github.com/joshuar/go-hass-agent/metrics"
 	"github.com/joshuar/go-hass-agent/models"
 	"github.com/joshuar/go-hass-agent/scheduler"
 	"github.com/joshuar/go-hass-agent/validation"
@@ -55,23 +56,34 @@ type Client struct {
 	sensorRegistry sensorRegistry
 	sensorTracker  sensorTracker
 	config         *Config
+	profile        *Profile
+	// configScheduled tracks whether fetching the Home Assistant config has been scheduled for this client.
+	configScheduled atomic.Bool
 }
 
 // ErrSendRequest indicates an error occurred when sending a request to Home Assistant.
 var ErrSendRequest = errors.New("send request failed")
 
-// NewClient creates a new hass client, which tracks last sensor status,
-// sensor registration status and handles sending and processing requests to the
-// Home Assistant REST API.
+var (
+	clients   = make(map[string]*Client)
+	clientsMu sync.Mutex
+)
+
+// setupClient creates the client for the given profile. Clients are created only once per profile.
+func setupClient(profile *Profile) (*Client, error) {
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
 
-var setupClient = sync.OnceValues(func() (*Client, error) {
-	var hasscfg Config
+	if client, found := clients[profile.Name]; found {
+		return client, nil
+	}
 	// Load the hass config.
-	if err := config.Load(ConfigPrefix, &hasscfg); err != nil {
+	hasscfg, err := profile.LoadConfig()
+	if err != nil {
 		return nil, fmt.Errorf("unable to load hass config: %w", err)
 	}
 	// Load the registry.
-	reg, err := registry.Load(config.GetPath())
+	reg, err := registry.Load(profile.RegistryPath())
 	if err != nil {
 		return nil, fmt.Errorf("unable to create hass client: %w", err)
 	}
@@ -79,29 +91,51 @@ var setupClient = sync.OnceValues(func() (*Client, error) {
 	client := &Client{
 		sensorRegistry: reg,
 		sensorTracker:  tracker.NewTracker(),
-		config:         &hasscfg,
+		config:         hasscfg,
+		profile:        profile,
 	}
+	clients[profile.Name] = client
+
 	return client, nil
-})
+}
 
+// NewClient creates a new hass client for the default profile, which tracks last sensor status, sensor registration
+// status and handles sending and processing requests to the Home Assistant REST API.
 func NewClient(ctx context.Context, agent agent) (*Client, error) {
-	client, err := setupClient()
+	return NewProfileClient(ctx, agent, DefaultProfile())
+}
+
+// NewProfileClient creates a new hass client for the given profile.
+func NewProfileClient(ctx context.Context, agent agent, profile *Profile) (*Client, error) {
+	client, err := setupClient(profile)
 	if err != nil {
 		return nil, fmt.Errorf("could not create client: %w", err)
 	}
-	// Run the job one-time initially to get the config.
-	if agent.IsRegistered() {
+	registered := profile.Registered
+	if profile.IsDefault() {
+		registered = agent.IsRegistered()
+	}
+	// Run the job one-time initially to get the config. Only one caller gets to schedule it. If that fails, another
+	// caller can try again.
+	if registered && client.configScheduled.CompareAndSwap(false, true) {
 		if updated, err := client.UpdateConfig(ctx); !updated || err != nil {
+			client.configScheduled.Store(false)
 			return nil, fmt.Errorf("could not create client: %w", err)
 		}
 		// Schedule a job to get the Home Assistant on a regular interval.
 		if err := client.scheduleConfigUpdates(ctx); err != nil {
+			client.configScheduled.Store(false)
 			return nil, fmt.Errorf("could not create client: %w", err)
 		}
 	}
 	return client, nil
 }
 
+// Profile returns the profile of the client.
+func (c *Client) Profile() *Profile {
+	return c.profile
+}
+
 func (c *Client) RestAPIURL() string {
 	return c.config.APIURL
 }
@@ -213,7 +247,11 @@ func (c *Client) EntityHandler(ctx context.Context, entityCh <-chan models.Entit
 // SendRequest will send the given request to the specified URL. It will handle
 // marshaling the request and unmarshaling the response. It will also handle
 // retrying the request with an exponential backoff if requested.
-func (c *Client) SendRequest(ctx context.Context, url string, req api.RequestData) (api.ResponseData, error) {
+func (c *Client) SendRequest(ctx context.Context, url string, req api.RequestData) (resp api.ResponseData, err error) {
+	defer func(started time.Time) {
+		metrics.ObserveRequest(c.Profile().Name, string(req.Type), time.Since(started), err)
+	}(time.Now())
+
 	slogctx.FromCtx(ctx).
 		LogAttrs(ctx, logging.LevelTrace,
 			"Sending request.",
@@ -225,7 +263,6 @@ func (c *Client) SendRequest(ctx context.Context, url string, req api.RequestDat
 			),
 		)
 
-	var resp api.ResponseData
 	apiResp, err := api.NewRequest(
 		api.WithBody(req),
 		api.WithResult(&resp),
@@ -289,9 +326,9 @@ func (c *Client) RegisterSensor(id models.UniqueID) error {
 	return nil
 }
 
-// Reset performs a reset of the client. It will remove existing registry data.
+// Reset performs a reset of the client for the default profile. It will remove existing registry data.
 func Reset() error {
-	if err := registry.Reset(config.GetPath()); err != nil {
+	if err := DefaultProfile().Reset(); err != nil {
 		return fmt.Errorf("unable to reset client: %w", err)
 	}
 	return nil
@@ -358,8 +395,12 @@ func (c *Client) scheduleConfigUpdates(ctx context.Context) error {
 	}
 	getConfigJob := job.NewFunctionJobWithDesc(c.UpdateConfig, "Fetch Home Assistant Configuration.")
 	const configCheckTimeout = 30 * time.Second
+	jobID := "update_hass_config"
+	if !c.profile.IsDefault() {
+		jobID += "_" + c.profile.Name
+	}
 	if err := scheduler.ScheduleJob(
-		"update_hass_config",
+		jobID,
 		getConfigJob,
 		quartz.NewSimpleTrigger(configCheckTimeout),
 	); err != nil {
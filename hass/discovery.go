@@ -0,0 +1,79 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package hass
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/grandcat/zeroconf"
+	slogctx "github.com/veqryn/slog-context"
+
+	"github.com/joshuar/go-hass-agent/logging"
+)
+
+const (
+	// DefaultDiscoveryTimeout is the default amount of time to spend looking for Home Assistant servers on the local
+	// network.
+	DefaultDiscoveryTimeout = 5 * time.Second
+
+	discoveryService = "_home-assistant._tcp"
+	discoveryDomain  = "local."
+)
+
+// DiscoverServers searches the local network (using zeroconf) for Home Assistant servers for the given amount of
+// time. It returns the (unique) base URLs of any servers found.
+func DiscoverServers(ctx context.Context, timeout time.Duration) ([]string, error) {
+	resolver, err := zeroconf.NewResolver(nil)
+	if err != nil {
+		return nil, fmt.Errorf("discover servers: %w", err)
+	}
+
+	entries := make(chan *zeroconf.ServiceEntry)
+	results := make(chan []string, 1)
+
+	go func() {
+		var servers []string
+
+		for entry := range entries {
+			server := serverFromEntry(entry)
+			if server == "" {
+				slogctx.FromCtx(ctx).Log(ctx, logging.LevelTrace,
+					"Found a server malformed server, will not use.", slog.String("server", entry.HostName))
+				continue
+			}
+			if !slices.Contains(servers, server) {
+				servers = append(servers, server)
+			}
+		}
+		results <- servers
+	}()
+
+	slogctx.FromCtx(ctx).Info("Looking for Home Assistant servers on the local network...")
+
+	searchCtx, searchCancel := context.WithTimeout(ctx, timeout)
+	defer searchCancel()
+
+	// The resolver closes the entries channel when the search context is done.
+	if err := resolver.Browse(searchCtx, discoveryService, discoveryDomain, entries); err != nil {
+		return nil, fmt.Errorf("discover servers: %w", err)
+	}
+
+	return <-results, nil
+}
+
+// serverFromEntry extracts the base URL of the Home Assistant server from the zeroconf service entry.
+func serverFromEntry(entry *zeroconf.ServiceEntry) string {
+	for _, t := range entry.Text {
+		if value, found := strings.CutPrefix(t, "base_url="); found {
+			return value
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package hass
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/joshuar/go-hass-agent/config"
+	"github.com/joshuar/go-hass-agent/hass/api"
+)
+
+// ErrRegistrationNotFound is returned when the existing registration of the agent is not known to a Home Assistant
+// server.
+var ErrRegistrationNotFound = errors.New("registration not found on server")
+
+// Migrate moves the existing registration for this profile to the Home Assistant server at the given address, without
+// registering again. This is only possible where the server is the same Home Assistant instance that the agent is
+// registered with (for example, it has moved to a new address), so that the existing webhook ID is still valid. As the
+// webhook ID is kept, all entities keep their IDs. If the server does not know about the existing registration,
+// ErrRegistrationNotFound is returned.
+func (p *Profile) Migrate(ctx context.Context, details *RegistrationRequest) error {
+	hasscfg, err := p.LoadConfig()
+	if err != nil || hasscfg.WebHookID == "" {
+		return fmt.Errorf("migrate registration: %w", ErrRegistrationNotFound)
+	}
+
+	serverURL, err := url.Parse(details.Server)
+	if err != nil {
+		return fmt.Errorf("migrate registration: %w", err)
+	}
+
+	// Use the existing webhook to ask the new server for its config. Home Assistant will respond with an empty body
+	// (or an error) for webhooks it does not know about.
+	apiResp, err := api.NewRequest(
+		api.WithBody(api.RequestData{Type: api.GetConfig}),
+	).Do(ctx, serverURL.JoinPath(webHookPath, hasscfg.WebHookID).String())
+	if err != nil {
+		return fmt.Errorf("migrate registration: %w", err)
+	}
+	if apiResp.IsError() {
+		return fmt.Errorf("migrate registration: %w: %s", ErrRegistrationNotFound, apiResp.Status())
+	}
+
+	var configResp api.ConfigResponse
+	if err := json.Unmarshal(apiResp.Body(), &configResp); err != nil || configResp.Version == "" {
+		return fmt.Errorf("migrate registration: %w", ErrRegistrationNotFound)
+	}
+
+	// The config response also contains any cloud URLs, in the same format as the registration response.
+	var resp api.DeviceRegistrationResponse
+	if err := json.Unmarshal(apiResp.Body(), &resp); err != nil {
+		return fmt.Errorf("migrate registration: %w", err)
+	}
+	resp.WebhookID = hasscfg.WebHookID
+
+	restAPIURL, err := generateAPIURL(&resp, details)
+	if err != nil {
+		return fmt.Errorf("migrate registration: %w", err)
+	}
+	websocketAPIURL, err := generateWebsocketURL(details.Server)
+	if err != nil {
+		return fmt.Errorf("migrate registration: %w", err)
+	}
+
+	prefix := p.ConfigPrefix()
+	values := map[string]any{
+		prefix + ConfigPrefix + "." + ConfigAPIURL:       restAPIURL,
+		prefix + ConfigPrefix + "." + ConfigWebsocketURL: websocketAPIURL,
+		prefix + registrationConfigPrefix + ".server":    details.Server,
+	}
+	// The existing token is kept unless a new one was given.
+	if details.Token != "" {
+		values[prefix+registrationConfigPrefix+".token"] = details.Token
+	}
+	if err := config.Set(values); err != nil {
+		return fmt.Errorf("migrate registration: %w", err)
+	}
+
+	return nil
+}
+
+// Unregister will mark this profile as no longer registered and remove its registry data. The registration status of
+// the default profile is tracked by the agent, so only its registry data is removed.
+func (p *Profile) Unregister() error {
+	if !p.IsDefault() {
+		p.Registered = false
+		if err := p.Save(); err != nil {
+			return fmt.Errorf("unregister profile %s: %w", p.Name, err)
+		}
+	}
+
+	// There is nothing to reset if no entities were ever registered.
+	if err := p.Reset(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
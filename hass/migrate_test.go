@@ -0,0 +1,93 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package hass
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshuar/go-hass-agent/config"
+)
+
+func TestProfile_Migrate(t *testing.T) {
+	webhookID := "abcd1234"
+	// The server responds to the get_config request for the known webhook only, as Home Assistant does.
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == webHookPath+webhookID {
+			res.Header().Set("Content-Type", "application/json")
+			res.Write([]byte(`{"version":"2026.10.0","cloudhook_url":"https://hooks.nabu.casa/xyz"}`)) //nolint:errcheck
+			return
+		}
+		res.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		webhookID  string
+		details    *RegistrationRequest
+		wantAPIURL string
+		wantToken  string
+		wantErr    error
+	}{
+		{
+			name:       "same instance",
+			webhookID:  webhookID,
+			details:    &RegistrationRequest{Server: server.URL},
+			wantAPIURL: "https://hooks.nabu.casa/xyz",
+			wantToken:  "oldtoken",
+		},
+		{
+			name:       "same instance ignore urls new token",
+			webhookID:  webhookID,
+			details:    &RegistrationRequest{Server: server.URL, Token: "newtoken", IgnoreHassURLS: true},
+			wantAPIURL: server.URL + webHookPath + webhookID,
+			wantToken:  "newtoken",
+		},
+		{
+			name:      "different instance",
+			webhookID: "unknown",
+			details:   &RegistrationRequest{Server: server.URL},
+			wantErr:   ErrRegistrationNotFound,
+		},
+		{
+			name:    "not registered",
+			details: &RegistrationRequest{Server: server.URL},
+			wantErr: ErrRegistrationNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.SetPath(t.TempDir())
+			require.NoError(t, config.Set(map[string]any{
+				"hass.apiurl":         "http://old.example.com/api/webhook/" + tt.webhookID,
+				"hass.webhook_id":     tt.webhookID,
+				"registration.server": "http://old.example.com",
+				"registration.token":  "oldtoken",
+			}))
+
+			err := DefaultProfile().Migrate(t.Context(), tt.details)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			hasscfg, err := DefaultProfile().LoadConfig()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantAPIURL, hasscfg.APIURL)
+			assert.Equal(t, tt.webhookID, hasscfg.WebHookID)
+			server, err := config.Get[string]("registration.server")
+			require.NoError(t, err)
+			assert.Equal(t, tt.details.Server, server)
+			token, err := config.Get[string]("registration.token")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantToken, token)
+		})
+	}
+}
@@ -0,0 +1,321 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+// Package notifications handles keeping a local history of notifications received from Home Assistant.
+package notifications
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	nanoid "github.com/matoous/go-nanoid/v2"
+)
+
+const (
+	historyDir       = "notifications"
+	historyFile      = "history.json"
+	defaultFilePerms = 0o600
+
+	// DefaultHistorySize is the maximum number of notifications kept in the history.
+	DefaultHistorySize = 200
+
+	// ActionEventType is the event type sent to Home Assistant when a notification action is chosen. It matches the
+	// event type fired by the Home Assistant companion apps.
+	ActionEventType = "mobile_app_notification_action"
+)
+
+// ErrNotFound is returned when a notification could not be found in the history.
+var ErrNotFound = errors.New("notification not found")
+
+// Record is a single notification stored in the history.
+type Record struct {
+	// Data contains any additional data sent with the notification.
+	Data any `json:"data,omitempty"`
+	// Received is the time the notification was received by the agent.
+	Received time.Time `json:"received"`
+	// ID is a unique identifier for the notification in the history.
+	ID string `json:"id"`
+	// Title is the title of the notification.
+	Title string `json:"title,omitempty"`
+	// Message is the message body of the notification.
+	Message string `json:"message"`
+	// Profile is the name of the server profile the notification was received from. It is empty for the default
+	// server.
+	Profile string `json:"profile,omitempty"`
+	// Action is the notification action chosen (if any).
+	Action string `json:"action,omitempty"`
+	// Displayed indicates whether the notification was successfully displayed on the desktop.
+	Displayed bool `json:"displayed"`
+}
+
+// NewRecord creates a new notification record with the given title, message and data, received now.
+func NewRecord(title, message string, data any) (*Record, error) {
+	id, err := nanoid.New()
+	if err != nil {
+		return nil, fmt.Errorf("generate notification id: %w", err)
+	}
+
+	return &Record{
+		ID:       id,
+		Title:    title,
+		Message:  message,
+		Data:     data,
+		Received: time.Now(),
+	}, nil
+}
+
+// Actions returns any actions that were included in the notification data.
+func (r *Record) Actions() []Action {
+	data, ok := r.Data.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	rawActions, ok := data["actions"].([]any)
+	if !ok {
+		return nil
+	}
+
+	actions := make([]Action, 0, len(rawActions))
+	for raw := range slices.Values(rawActions) {
+		values, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		key, _ := values["action"].(string) //nolint:errcheck // zero value is fine.
+		if key == "" {
+			continue
+		}
+
+		title, _ := values["title"].(string) //nolint:errcheck // zero value is fine.
+		if title == "" {
+			title = key
+		}
+
+		actions = append(actions, Action{Key: key, Title: title})
+	}
+
+	return actions
+}
+
+// HasAction returns a boolean indicating whether the given action key is one of the actions included in the
+// notification data.
+func (r *Record) HasAction(key string) bool {
+	return slices.ContainsFunc(r.Actions(), func(action Action) bool {
+		return action.Key == key
+	})
+}
+
+// Matches returns a boolean indicating whether the record title, message or chosen action contains the given search
+// term. Matching is case-insensitive. An empty search term will match all records.
+func (r *Record) Matches(term string) bool {
+	if term == "" {
+		return true
+	}
+
+	term = strings.ToLower(term)
+
+	return strings.Contains(strings.ToLower(r.Title), term) ||
+		strings.Contains(strings.ToLower(r.Message), term) ||
+		strings.Contains(strings.ToLower(r.Action), term)
+}
+
+// Action represents an actionable notification action.
+type Action struct {
+	Key   string
+	Title string
+}
+
+// History is a bounded, on-disk history of notifications.
+type History struct {
+	file    string
+	records []Record
+	size    int
+	mu      sync.Mutex
+}
+
+// Load will load the notification history stored under the given path. If no history exists, an empty history is
+// returned.
+func Load(path string) (*History, error) {
+	history := &History{
+		file: filepath.Join(path, historyDir, historyFile),
+		size: DefaultHistorySize,
+	}
+
+	if err := os.MkdirAll(filepath.Dir(history.file), 0o700); err != nil {
+		return nil, fmt.Errorf("load notification history: %w", err)
+	}
+
+	history.mu.Lock()
+	defer history.mu.Unlock()
+
+	if err := history.read(); err != nil {
+		return nil, fmt.Errorf("load notification history: %w", err)
+	}
+
+	return history, nil
+}
+
+// Add will add the given record to the history. If the history has reached its maximum size, the oldest records are
+// removed.
+func (h *History) Add(record *Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := h.read(); err != nil {
+		return fmt.Errorf("add notification to history: %w", err)
+	}
+
+	h.records = append(h.records, *record)
+	if len(h.records) > h.size {
+		h.records = slices.Clone(h.records[len(h.records)-h.size:])
+	}
+
+	if err := h.write(); err != nil {
+		return fmt.Errorf("add notification to history: %w", err)
+	}
+
+	return nil
+}
+
+// SetAction records the chosen action against the notification with the given id.
+func (h *History) SetAction(id, action string) error {
+	if err := h.update(id, func(r *Record) { r.Action = action }); err != nil {
+		return fmt.Errorf("set notification action: %w", err)
+	}
+
+	return nil
+}
+
+// SetDisplayed records whether the notification with the given id has been displayed.
+func (h *History) SetDisplayed(id string, displayed bool) error {
+	if err := h.update(id, func(r *Record) { r.Displayed = displayed }); err != nil {
+		return fmt.Errorf("set notification displayed: %w", err)
+	}
+
+	return nil
+}
+
+// update will apply the given function to the notification with the given id and save the history.
+func (h *History) update(id string, updateFunc func(r *Record)) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := h.read(); err != nil {
+		return err
+	}
+
+	idx := slices.IndexFunc(h.records, func(r Record) bool { return r.ID == id })
+	if idx == -1 {
+		return ErrNotFound
+	}
+
+	updateFunc(&h.records[idx])
+
+	return h.write()
+}
+
+// Get retrieves the notification with the given id.
+func (h *History) Get(id string) (*Record, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	idx := slices.IndexFunc(h.records, func(r Record) bool { return r.ID == id })
+	if idx == -1 {
+		return nil, ErrNotFound
+	}
+
+	record := h.records[idx]
+
+	return &record, nil
+}
+
+// Search returns all notifications matching the given search term, newest first. An empty search term will return all
+// notifications.
+func (h *History) Search(term string) []Record {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var found []Record
+
+	for idx := len(h.records) - 1; idx >= 0; idx-- {
+		if h.records[idx].Matches(term) {
+			found = append(found, h.records[idx])
+		}
+	}
+
+	return found
+}
+
+// List returns all notifications in the history, newest first.
+func (h *History) List() []Record {
+	return h.Search("")
+}
+
+// Clear removes all notifications from the history.
+func (h *History) Clear() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.records = nil
+
+	if err := h.write(); err != nil {
+		return fmt.Errorf("clear notification history: %w", err)
+	}
+
+	return nil
+}
+
+// write will write the history to disk. The history is written to a temporary file first and then moved into place,
+// so that concurrent readers never see a partially written file.
+func (h *History) write() error {
+	data, err := json.Marshal(h.records)
+	if err != nil {
+		return fmt.Errorf("encode history: %w", err)
+	}
+
+	tmpFile := h.file + ".tmp"
+	if err := os.WriteFile(tmpFile, data, defaultFilePerms); err != nil {
+		return fmt.Errorf("write history: %w", err)
+	}
+
+	if err := os.Rename(tmpFile, h.file); err != nil {
+		return fmt.Errorf("write history: %w", err)
+	}
+
+	return nil
+}
+
+// read will read the history from disk. The history is re-read before any modification, so that multiple instances
+// (i.e., the agent and the command-line) do not overwrite each other's changes.
+func (h *History) read() error {
+	h.records = nil
+
+	data, err := os.ReadFile(h.file)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("read history: %w", err)
+	}
+
+	if len(data) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &h.records); err != nil {
+		return fmt.Errorf("decode history: %w", err)
+	}
+
+	return nil
+}
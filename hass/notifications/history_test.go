@@ -0,0 +1,242 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package notifications
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMockHistory(t *testing.T, path string, size int, count int) *History {
+	t.Helper()
+
+	history, err := Load(path)
+	require.NoError(t, err)
+	history.size = size
+	for range count {
+		record, err := NewRecord("title", "message", nil)
+		require.NoError(t, err)
+		require.NoError(t, history.Add(record))
+	}
+	return history
+}
+
+func TestLoad(t *testing.T) {
+	validPath := t.TempDir()
+	newMockHistory(t, validPath, DefaultHistorySize, 3)
+
+	invalidPath := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(invalidPath, historyDir), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(invalidPath, historyDir, historyFile), []byte(`invalid`), 0o600))
+
+	tests := []struct {
+		name    string
+		path    string
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "existing history",
+			path: validPath,
+			want: 3,
+		},
+		{
+			name: "no history",
+			path: t.TempDir(),
+			want: 0,
+		},
+		{
+			name:    "invalid contents",
+			path:    invalidPath,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Load() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr {
+				assert.Len(t, got.List(), tt.want)
+			}
+		})
+	}
+}
+
+func TestHistory_Add(t *testing.T) {
+	tests := []struct {
+		name  string
+		size  int
+		count int
+		want  int
+	}{
+		{
+			name:  "below limit",
+			size:  5,
+			count: 3,
+			want:  3,
+		},
+		{
+			name:  "above limit",
+			size:  5,
+			count: 8,
+			want:  5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := t.TempDir()
+			history := newMockHistory(t, path, tt.size, tt.count)
+			assert.Len(t, history.List(), tt.want)
+			// Check the history was persisted.
+			reloaded, err := Load(path)
+			require.NoError(t, err)
+			assert.Len(t, reloaded.List(), tt.want)
+		})
+	}
+}
+
+func TestHistory_Search(t *testing.T) {
+	history, err := Load(t.TempDir())
+	require.NoError(t, err)
+
+	first, err := NewRecord("Door", "The front door is open.", nil)
+	require.NoError(t, err)
+	require.NoError(t, history.Add(first))
+	second, err := NewRecord("Laundry", "The washing machine has finished.", nil)
+	require.NoError(t, err)
+	require.NoError(t, history.Add(second))
+	require.NoError(t, history.SetAction(second.ID, "ACKNOWLEDGE"))
+
+	tests := []struct {
+		name string
+		term string
+		want []string
+	}{
+		{
+			name: "all newest first",
+			want: []string{second.ID, first.ID},
+		},
+		{
+			name: "match title",
+			term: "door",
+			want: []string{first.ID},
+		},
+		{
+			name: "match message",
+			term: "WASHING",
+			want: []string{second.ID},
+		},
+		{
+			name: "match action",
+			term: "acknowledge",
+			want: []string{second.ID},
+		},
+		{
+			name: "no match",
+			term: "garage",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, record := range history.Search(tt.term) {
+				got = append(got, record.ID)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestHistory_SetAction(t *testing.T) {
+	path := t.TempDir()
+	history := newMockHistory(t, path, DefaultHistorySize, 1)
+	id := history.List()[0].ID
+
+	tests := []struct {
+		name    string
+		id      string
+		wantErr bool
+	}{
+		{
+			name: "existing notification",
+			id:   id,
+		},
+		{
+			name:    "unknown notification",
+			id:      "unknown",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := history.SetAction(tt.id, "OPEN")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("History.SetAction() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr {
+				reloaded, err := Load(path)
+				require.NoError(t, err)
+				got, err := reloaded.Get(tt.id)
+				require.NoError(t, err)
+				assert.Equal(t, "OPEN", got.Action)
+			}
+		})
+	}
+}
+
+func TestRecord_Actions(t *testing.T) {
+	tests := []struct {
+		name string
+		data any
+		want []Action
+	}{
+		{
+			name: "no data",
+		},
+		{
+			name: "no actions",
+			data: map[string]any{"tag": "something"},
+		},
+		{
+			name: "actions",
+			data: map[string]any{"actions": []any{
+				map[string]any{"action": "OPEN", "title": "Open Door"},
+				map[string]any{"action": "IGNORE"},
+				map[string]any{"title": "Invalid"},
+			}},
+			want: []Action{{Key: "OPEN", Title: "Open Door"}, {Key: "IGNORE", Title: "IGNORE"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Record{Data: tt.data}
+			got := r.Actions()
+			if len(tt.want) == 0 {
+				assert.Empty(t, got)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRecord_HasAction(t *testing.T) {
+	r := &Record{Data: map[string]any{"actions": []any{
+		map[string]any{"action": "OPEN", "title": "Open Door"},
+	}}}
+
+	assert.True(t, r.HasAction("OPEN"))
+	assert.False(t, r.HasAction("Open Door"))
+	assert.False(t, r.HasAction("CLOSE"))
+	assert.False(t, r.HasAction(""))
+	assert.False(t, (&Record{}).HasAction("OPEN"))
+}
@@ -0,0 +1,46 @@
+// Code generated by "stringer -type=Priority,Decision -output router.gen.go -linecomment"; DO NOT EDIT.
+
+package notifications
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[PriorityLow-0]
+	_ = x[PriorityNormal-1]
+	_ = x[PriorityHigh-2]
+}
+
+const _Priority_name = "lownormalhigh"
+
+var _Priority_index = [...]uint8{0, 3, 9, 13}
+
+func (i Priority) String() string {
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_Priority_index)-1 {
+		return "Priority(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _Priority_name[_Priority_index[idx]:_Priority_index[idx+1]]
+}
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[Displayed-0]
+	_ = x[Queued-1]
+	_ = x[Suppressed-2]
+}
+
+const _Decision_name = "displayedqueuedsuppressed"
+
+var _Decision_index = [...]uint8{0, 9, 15, 25}
+
+func (i Decision) String() string {
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_Decision_index)-1 {
+		return "Decision(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _Decision_name[_Decision_index[idx]:_Decision_index[idx+1]]
+}
@@ -0,0 +1,256 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package notifications
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+
+	slogctx "github.com/veqryn/slog-context"
+
+	"github.com/joshuar/go-hass-agent/models"
+)
+
+const (
+	// doNotDisturbSensorID is the id of the sensor reporting the desktop do-not-disturb status.
+	doNotDisturbSensorID = "do_not_disturb"
+	// screenLockSensorID is the id of the sensor reporting the screen lock status.
+	screenLockSensorID = "screen_lock"
+)
+
+//go:generate go tool stringer -type=Priority,Decision -output router.gen.go -linecomment
+const (
+	PriorityLow    Priority = iota // low
+	PriorityNormal                 // normal
+	PriorityHigh                   // high
+)
+
+// Priority represents the priority of a notification.
+type Priority int
+
+const (
+	Displayed  Decision = iota // displayed
+	Queued                     // queued
+	Suppressed                 // suppressed
+)
+
+// Decision represents the outcome of routing a notification.
+type Decision int
+
+// DisplayFunc is a function that will display the given notification.
+type DisplayFunc func(record *Record) error
+
+// Router handles routing notifications for display, taking into account whether the user is able or wants to see
+// them. While do-not-disturb is enabled or the screen is locked, high priority notifications are displayed, normal
+// priority notifications are queued until do-not-disturb is disabled/the screen is unlocked and low priority
+// notifications are suppressed. All notifications are recorded in the history, regardless of the routing decision.
+type Router struct {
+	history *History
+	display DisplayFunc
+	queue   []*Record
+	dnd     bool
+	locked  bool
+	mu      sync.Mutex
+}
+
+// NewRouter creates a new notification router. Notifications will be displayed with the given display function and
+// recorded in the given history (if not nil).
+func NewRouter(history *History, display DisplayFunc) *Router {
+	return &Router{
+		history: history,
+		display: display,
+	}
+}
+
+// Route will route the given notification, displaying, queuing or suppressing it as appropriate. The routing decision
+// is made with the router lock held, but the notification is displayed and recorded after releasing it, so that a slow
+// display or history does not hold up changes to the do-not-disturb/screen lock status.
+func (r *Router) Route(ctx context.Context, record *Record) Decision {
+	r.mu.Lock()
+	dnd, locked := r.dnd, r.locked
+	r.mu.Unlock()
+
+	decision := Displayed
+	if dnd || locked {
+		switch PriorityOf(record.Data) {
+		case PriorityHigh:
+			decision = Displayed
+		case PriorityNormal:
+			decision = Queued
+		default:
+			decision = Suppressed
+		}
+	}
+
+	if decision == Displayed {
+		record.Displayed = r.show(ctx, record)
+	}
+
+	slogctx.FromCtx(ctx).Debug("Routed notification.",
+		slog.String("decision", decision.String()),
+		slog.Bool("do_not_disturb", dnd),
+		slog.Bool("screen_locked", locked),
+	)
+
+	if r.history != nil {
+		if err := r.history.Add(record); err != nil {
+			slogctx.FromCtx(ctx).Warn("Unable to record notification.",
+				slog.Any("error", err))
+		}
+	}
+
+	// The notification is only queued once it has been recorded, so that the history can be updated when it is
+	// displayed. If notifications are no longer being held back, it is displayed straight away.
+	if decision == Queued {
+		r.mu.Lock()
+		r.queue = append(r.queue, record)
+		queued := r.release()
+		r.mu.Unlock()
+
+		r.flush(ctx, queued)
+	}
+
+	return decision
+}
+
+// Observe will watch the given entity channel for changes to the do-not-disturb and screen lock status, updating the
+// router as required. All entities are passed through, unchanged, to the returned channel.
+func (r *Router) Observe(ctx context.Context, entityCh <-chan models.Entity) <-chan models.Entity {
+	outCh := make(chan models.Entity)
+
+	go func() {
+		defer close(outCh)
+
+		for entity := range entityCh {
+			if sensor, err := entity.AsSensor(); err == nil {
+				switch sensor.UniqueID {
+				case doNotDisturbSensorID:
+					if value, ok := sensor.State.(bool); ok {
+						r.SetDoNotDisturb(ctx, value)
+					}
+				case screenLockSensorID:
+					// The screen lock sensor state is true when unlocked.
+					if value, ok := sensor.State.(bool); ok {
+						r.SetScreenLocked(ctx, !value)
+					}
+				}
+			}
+			select {
+			case outCh <- entity:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return outCh
+}
+
+// SetDoNotDisturb sets the do-not-disturb status. If notifications are no longer being held back, any queued
+// notifications will be displayed in the background.
+func (r *Router) SetDoNotDisturb(ctx context.Context, value bool) {
+	r.mu.Lock()
+	r.dnd = value
+	queued := r.release()
+	r.mu.Unlock()
+
+	r.flush(ctx, queued)
+}
+
+// SetScreenLocked sets the screen lock status. If notifications are no longer being held back, any queued
+// notifications will be displayed in the background.
+func (r *Router) SetScreenLocked(ctx context.Context, value bool) {
+	r.mu.Lock()
+	r.locked = value
+	queued := r.release()
+	r.mu.Unlock()
+
+	r.flush(ctx, queued)
+}
+
+// release will empty and return the queue, if neither do-not-disturb is enabled nor the screen is locked. It must be
+// called with the router lock held.
+func (r *Router) release() []*Record {
+	if r.dnd || r.locked || len(r.queue) == 0 {
+		return nil
+	}
+
+	queued := r.queue
+	r.queue = nil
+
+	return queued
+}
+
+// flush will display the given (previously queued) notifications in the background, so that displaying them does not
+// hold up the caller.
+func (r *Router) flush(ctx context.Context, queued []*Record) {
+	if len(queued) == 0 {
+		return
+	}
+
+	go func() {
+		for _, record := range queued {
+			if !r.show(ctx, record) || r.history == nil {
+				continue
+			}
+
+			if err := r.history.SetDisplayed(record.ID, true); err != nil {
+				slogctx.FromCtx(ctx).Warn("Unable to update notification history.",
+					slog.Any("error", err))
+			}
+		}
+	}()
+}
+
+// show will display the notification, returning a boolean indicating success.
+func (r *Router) show(ctx context.Context, record *Record) bool {
+	if err := r.display(record); err != nil {
+		slogctx.FromCtx(ctx).Warn("Unable to display notification.",
+			slog.Any("error", err))
+
+		return false
+	}
+
+	return true
+}
+
+// PriorityOf determines the priority of a notification from its data. It understands the priority/importance options
+// used by the Home Assistant Android app and the interruption level used by the iOS app. Notifications without any
+// priority details have normal priority.
+func PriorityOf(data any) Priority {
+	values, ok := data.(map[string]any)
+	if !ok {
+		return PriorityNormal
+	}
+
+	if push, ok := values["push"].(map[string]any); ok {
+		if level, ok := push["interruption-level"].(string); ok {
+			switch strings.ToLower(level) {
+			case "critical", "time-sensitive":
+				return PriorityHigh
+			case "passive":
+				return PriorityLow
+			}
+		}
+	}
+
+	for _, key := range []string{"priority", "importance"} {
+		if value, ok := values[key].(string); ok {
+			switch strings.ToLower(value) {
+			case "high", "max":
+				return PriorityHigh
+			case "low", "min":
+				return PriorityLow
+			}
+		}
+	}
+
+	if channel, ok := values["channel"].(string); ok && strings.EqualFold(channel, "alarm_stream") {
+		return PriorityHigh
+	}
+
+	return PriorityNormal
+}
@@ -0,0 +1,240 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package notifications
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshuar/go-hass-agent/models"
+)
+
+func TestPriorityOf(t *testing.T) {
+	tests := []struct {
+		name string
+		data any
+		want Priority
+	}{
+		{
+			name: "no data",
+			want: PriorityNormal,
+		},
+		{
+			name: "android high priority",
+			data: map[string]any{"priority": "high"},
+			want: PriorityHigh,
+		},
+		{
+			name: "android low importance",
+			data: map[string]any{"importance": "min"},
+			want: PriorityLow,
+		},
+		{
+			name: "android alarm stream",
+			data: map[string]any{"channel": "alarm_stream"},
+			want: PriorityHigh,
+		},
+		{
+			name: "ios critical",
+			data: map[string]any{"push": map[string]any{"interruption-level": "critical"}},
+			want: PriorityHigh,
+		},
+		{
+			name: "ios passive",
+			data: map[string]any{"push": map[string]any{"interruption-level": "passive"}},
+			want: PriorityLow,
+		},
+		{
+			name: "unrelated data",
+			data: map[string]any{"tag": "something"},
+			want: PriorityNormal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, PriorityOf(tt.data))
+		})
+	}
+}
+
+func TestRouter_Route(t *testing.T) {
+	tests := []struct {
+		name        string
+		dnd         bool
+		locked      bool
+		data        any
+		displayErr  error
+		want        Decision
+		wantDisplay bool
+	}{
+		{
+			name:        "not quiet",
+			want:        Displayed,
+			wantDisplay: true,
+		},
+		{
+			name:        "dnd high priority",
+			dnd:         true,
+			data:        map[string]any{"priority": "high"},
+			want:        Displayed,
+			wantDisplay: true,
+		},
+		{
+			name: "dnd normal priority",
+			dnd:  true,
+			want: Queued,
+		},
+		{
+			name:   "locked low priority",
+			locked: true,
+			data:   map[string]any{"priority": "low"},
+			want:   Suppressed,
+		},
+		{
+			name:       "display failed",
+			displayErr: errors.New("no notification server"),
+			want:       Displayed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history, err := Load(t.TempDir())
+			require.NoError(t, err)
+			var displayed int
+			router := NewRouter(history, func(_ *Record) error {
+				displayed++
+				return tt.displayErr
+			})
+			router.dnd = tt.dnd
+			router.locked = tt.locked
+
+			record, err := NewRecord("title", "message", tt.data)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, router.Route(t.Context(), record))
+			got, err := history.Get(record.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantDisplay, got.Displayed)
+		})
+	}
+}
+
+func TestRouter_Observe(t *testing.T) {
+	history, err := Load(t.TempDir())
+	require.NoError(t, err)
+	displayed := make(chan string, 1)
+	router := NewRouter(history, func(record *Record) error {
+		displayed <- record.ID
+		return nil
+	})
+	ctx := t.Context()
+
+	entityCh := make(chan models.Entity)
+	outCh := router.Observe(ctx, entityCh)
+	send := func(id string, state bool) {
+		entityCh <- models.NewSensor(ctx,
+			models.WithName(id),
+			models.WithID(id),
+			models.AsTypeBinarySensor(),
+			models.WithState(state))
+		<-outCh
+	}
+
+	// Enable DND and lock the screen. A notification should be queued.
+	send(doNotDisturbSensorID, true)
+	send(screenLockSensorID, false)
+	record, err := NewRecord("title", "message", nil)
+	require.NoError(t, err)
+	assert.Equal(t, Queued, router.Route(ctx, record))
+
+	// Disable DND. Screen is still locked so notification should still be queued.
+	send(doNotDisturbSensorID, false)
+	assert.Empty(t, displayed)
+
+	// Unlock the screen. Notification should now be displayed (in the background) and recorded as such.
+	send(screenLockSensorID, true)
+	assert.Equal(t, record.ID, <-displayed)
+	assert.Eventually(t, func() bool {
+		got, err := history.Get(record.ID)
+		return err == nil && got.Displayed
+	}, time.Second, 10*time.Millisecond)
+
+	close(entityCh)
+}
+
+func TestRouter_ObserveSlowDisplay(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	router := NewRouter(nil, func(_ *Record) error {
+		<-release
+		return nil
+	})
+	router.locked = true
+	ctx := t.Context()
+
+	record, err := NewRecord("title", "message", nil)
+	require.NoError(t, err)
+	assert.Equal(t, Queued, router.Route(ctx, record))
+
+	entityCh := make(chan models.Entity, 2)
+	outCh := router.Observe(ctx, entityCh)
+
+	// Unlocking the screen should not wait for the queued notification to be displayed.
+	entityCh <- models.NewSensor(ctx,
+		models.WithName(screenLockSensorID),
+		models.WithID(screenLockSensorID),
+		models.AsTypeBinarySensor(),
+		models.WithState(true))
+	entityCh <- models.NewSensor(ctx, models.WithName("other"), models.WithID("other"), models.WithState(1))
+	close(entityCh)
+
+	var passed int
+	for range outCh {
+		passed++
+	}
+
+	assert.Equal(t, 2, passed)
+}
+
+func TestRouter_RouteSlowDisplay(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	router := NewRouter(nil, func(_ *Record) error {
+		close(started)
+		<-release
+		return nil
+	})
+	ctx := t.Context()
+
+	record, err := NewRecord("title", "message", nil)
+	require.NoError(t, err)
+
+	routed := make(chan Decision)
+	go func() {
+		routed <- router.Route(ctx, record)
+	}()
+	<-started
+
+	// Changing the do-not-disturb status should not wait for the notification to be displayed.
+	changed := make(chan struct{})
+	go func() {
+		router.SetDoNotDisturb(ctx, true)
+		close(changed)
+	}()
+
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timed out waiting for do-not-disturb change")
+	}
+
+	close(release)
+	assert.Equal(t, Displayed, <-routed)
+}
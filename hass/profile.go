@@ -0,0 +1,171 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package hass
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+
+	"github.com/joshuar/go-hass-agent/config"
+	"github.com/joshuar/go-hass-agent/hass/registry"
+)
+
+const (
+	// ProfilesConfigPrefix is the prefix/heading under which server profiles are found in the preferences file.
+	ProfilesConfigPrefix = "profiles"
+	// DefaultProfileName is the name of the default profile, which is the Home Assistant server the agent was
+	// initially registered with.
+	DefaultProfileName = "default"
+
+	registrationConfigPrefix = "registration"
+)
+
+var (
+	// ErrInvalidProfile is returned when a profile name is not valid.
+	ErrInvalidProfile = errors.New("invalid profile name")
+	// ErrUnknownProfile is returned when a profile does not exist.
+	ErrUnknownProfile = errors.New("unknown profile")
+)
+
+var validProfileName = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// Profile represents a Home Assistant server the agent can be registered with. The default profile uses the
+// top-level hass/registration preferences. Any additional profiles store their preferences under
+// profiles.<name>.hass/registration and have their own sensor registry.
+type Profile struct {
+	Name       string `toml:"-"`
+	Enabled    bool   `toml:"enabled"`
+	Registered bool   `toml:"registered"`
+}
+
+// DefaultProfile returns the default profile.
+func DefaultProfile() *Profile {
+	return &Profile{Name: DefaultProfileName, Enabled: true}
+}
+
+// NewProfile creates a new (enabled) profile with the given name. If the name is empty, the default profile is
+// returned.
+func NewProfile(name string) (*Profile, error) {
+	if name == "" || name == DefaultProfileName {
+		return DefaultProfile(), nil
+	}
+
+	if !validProfileName.MatchString(name) {
+		return nil, fmt.Errorf("%w: %s: must only contain lowercase letters, numbers, '-' or '_'", ErrInvalidProfile, name)
+	}
+
+	return &Profile{Name: name, Enabled: true}, nil
+}
+
+// GetProfile retrieves the profile with the given name from the preferences. If the name is empty, the default
+// profile is returned.
+func GetProfile(name string) (*Profile, error) {
+	profile, err := NewProfile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if profile.IsDefault() {
+		return profile, nil
+	}
+
+	if !config.Exists(profile.configPath()) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProfile, name)
+	}
+
+	if err := config.Load(profile.configPath(), profile); err != nil {
+		return nil, fmt.Errorf("load profile %s: %w", name, err)
+	}
+
+	return profile, nil
+}
+
+// Profiles returns all profiles. The default profile is always returned first.
+func Profiles() ([]*Profile, error) {
+	profiles := []*Profile{DefaultProfile()}
+
+	var errs error
+
+	for _, name := range config.Keys(ProfilesConfigPrefix) {
+		profile, err := GetProfile(name)
+		if err != nil {
+			errs = errors.Join(errs, err)
+			continue
+		}
+
+		profiles = append(profiles, profile)
+	}
+
+	return profiles, errs
+}
+
+// IsDefault returns whether this profile is the default profile.
+func (p *Profile) IsDefault() bool {
+	return p.Name == DefaultProfileName
+}
+
+// IsActive returns whether entities should be sent to this profile. Additional profiles need to be both enabled and
+// registered. The default profile is always active (its registration state is tracked by the agent).
+func (p *Profile) IsActive() bool {
+	return p.IsDefault() || (p.Enabled && p.Registered)
+}
+
+// ConfigPrefix returns the prefix that should be prepended to the hass/registration preferences for this profile.
+func (p *Profile) ConfigPrefix() string {
+	if p.IsDefault() {
+		return ""
+	}
+
+	return p.configPath() + "."
+}
+
+// RegistryPath returns the path under which the sensor registry for this profile is stored.
+func (p *Profile) RegistryPath() string {
+	if p.IsDefault() {
+		return config.GetPath()
+	}
+
+	return filepath.Join(config.GetPath(), ProfilesConfigPrefix, p.Name)
+}
+
+// Save will save the profile to the preferences. The default profile has no preferences to save.
+func (p *Profile) Save() error {
+	if p.IsDefault() {
+		return nil
+	}
+
+	if err := config.Set(map[string]any{
+		p.configPath() + ".enabled":    p.Enabled,
+		p.configPath() + ".registered": p.Registered,
+	}); err != nil {
+		return fmt.Errorf("save profile %s: %w", p.Name, err)
+	}
+
+	return nil
+}
+
+// LoadConfig loads the hass config for this profile.
+func (p *Profile) LoadConfig() (*Config, error) {
+	var hasscfg Config
+	if err := config.Load(p.ConfigPrefix()+ConfigPrefix, &hasscfg); err != nil {
+		return nil, fmt.Errorf("load %s config for profile %s: %w", ConfigPrefix, p.Name, err)
+	}
+
+	return &hasscfg, nil
+}
+
+// Reset will remove the existing registry data for this profile.
+func (p *Profile) Reset() error {
+	if err := registry.Reset(p.RegistryPath()); err != nil {
+		return fmt.Errorf("unable to reset profile %s: %w", p.Name, err)
+	}
+
+	return nil
+}
+
+func (p *Profile) configPath() string {
+	return ProfilesConfigPrefix + "." + p.Name
+}
@@ -0,0 +1,146 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package hass
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshuar/go-hass-agent/config"
+)
+
+func TestNewProfile(t *testing.T) {
+	configDir := t.TempDir()
+	config.SetPath(configDir)
+
+	tests := []struct {
+		name             string
+		profileName      string
+		wantDefault      bool
+		wantConfigPrefix string
+		wantRegistryPath string
+		wantErr          bool
+	}{
+		{
+			name:             "empty name",
+			wantDefault:      true,
+			wantRegistryPath: configDir,
+		},
+		{
+			name:             "default name",
+			profileName:      DefaultProfileName,
+			wantDefault:      true,
+			wantRegistryPath: configDir,
+		},
+		{
+			name:             "valid name",
+			profileName:      "staging",
+			wantConfigPrefix: "profiles.staging.",
+			wantRegistryPath: filepath.Join(configDir, "profiles", "staging"),
+		},
+		{
+			name:        "invalid name",
+			profileName: "Staging Server",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewProfile(tt.profileName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewProfile() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidProfile)
+				return
+			}
+			assert.Equal(t, tt.wantDefault, got.IsDefault())
+			assert.Equal(t, tt.wantConfigPrefix, got.ConfigPrefix())
+			assert.Equal(t, tt.wantRegistryPath, got.RegistryPath())
+		})
+	}
+}
+
+func TestProfile_IsActive(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile *Profile
+		want    bool
+	}{
+		{
+			name:    "default",
+			profile: DefaultProfile(),
+			want:    true,
+		},
+		{
+			name:    "enabled and registered",
+			profile: &Profile{Name: "staging", Enabled: true, Registered: true},
+			want:    true,
+		},
+		{
+			name:    "disabled",
+			profile: &Profile{Name: "staging", Enabled: false, Registered: true},
+		},
+		{
+			name:    "not registered",
+			profile: &Profile{Name: "staging", Enabled: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.profile.IsActive())
+		})
+	}
+}
+
+func TestGetProfile(t *testing.T) {
+	config.SetPath(t.TempDir())
+	require.NoError(t, config.Set(map[string]any{
+		"profiles.staging.enabled":    false,
+		"profiles.staging.registered": true,
+	}))
+
+	tests := []struct {
+		name        string
+		profileName string
+		want        *Profile
+		wantErr     error
+	}{
+		{
+			name:        "existing",
+			profileName: "staging",
+			want:        &Profile{Name: "staging", Enabled: false, Registered: true},
+		},
+		{
+			name:        "unknown",
+			profileName: "production",
+			wantErr:     ErrUnknownProfile,
+		},
+		{
+			name: "default",
+			want: DefaultProfile(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetProfile(tt.profileName)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	profiles, err := Profiles()
+	require.NoError(t, err)
+	require.Len(t, profiles, 2)
+	assert.True(t, profiles[0].IsDefault())
+	assert.Equal(t, "staging", profiles[1].Name)
+}
@@ -26,9 +26,9 @@ const (
 // RegistrationRequest are the preferences that defines how Go Hass Agent registers
 // with Home Assistant.
 type RegistrationRequest struct {
-	Server         string `toml:"server" form:"server"           validate:"required,http_url" help:"URL of the Home Assistant server."`
-	Token          string `toml:"token"  form:"token"            validate:"required"          help:"Personal Access Token obtained from Home Assistant."`
-	IgnoreHassURLS bool   `toml:"-"      form:"ignore_hass_urls" validate:"omitempty,boolean" help:"Ignore URLs returned by Home Assistant and use provided server for access." json:"-"`
+	Server         string `toml:"server" form:"server"           validate:"required,http_url" env:"GOHASSAGENT_SERVER"           help:"URL of the Home Assistant server."`
+	Token          string `toml:"token"  form:"token"            validate:"required"          env:"GOHASSAGENT_TOKEN"            help:"Personal Access Token obtained from Home Assistant."`
+	IgnoreHassURLS bool   `toml:"-"      form:"ignore_hass_urls" validate:"omitempty,boolean" env:"GOHASSAGENT_IGNORE_HASS_URLS" help:"Ignore URLs returned by Home Assistant and use provided server for access." json:"-"`
 }
 
 // Valid checks whether the registration request details are valid.
@@ -47,9 +47,16 @@ func (r *RegistrationRequest) Sanitise() error {
 	return nil
 }
 
-// Register will register the device with Home Assistant. It uses the details entered by the user for the server/token
-// and receives back from Home Assistant URLs and details needed for subsequent API requests.
+// Register will register the device with Home Assistant, using the default profile. It uses the details entered by the
+// user for the server/token and receives back from Home Assistant URLs and details needed for subsequent API requests.
 func Register(ctx context.Context, id string, details *RegistrationRequest) error {
+	return DefaultProfile().Register(ctx, id, details)
+}
+
+// Register will register the device with the Home Assistant server for this profile. It uses the details entered by
+// the user for the server/token and receives back from Home Assistant URLs and details needed for subsequent API
+// requests.
+func (p *Profile) Register(ctx context.Context, id string, details *RegistrationRequest) error {
 	req := newDeviceRegistration(ctx, id)
 	resp := api.DeviceRegistrationResponse{}
 
@@ -80,17 +87,23 @@ func Register(ctx context.Context, id string, details *RegistrationRequest) erro
 	}
 
 	// Save options to config.
+	prefix := p.ConfigPrefix()
 	err = config.Set(map[string]any{
-		ConfigPrefix + "." + ConfigAPIURL:       restAPIURL,
-		ConfigPrefix + "." + ConfigWebsocketURL: websocketAPIURL,
-		ConfigPrefix + "." + ConfigWebhookID:    resp.WebhookID,
-		ConfigPrefix + "." + ConfigSecret:       resp.Secret,
-		"registration.server":                   details.Server,
-		"registration.token":                    details.Token,
+		prefix + ConfigPrefix + "." + ConfigAPIURL:       restAPIURL,
+		prefix + ConfigPrefix + "." + ConfigWebsocketURL: websocketAPIURL,
+		prefix + ConfigPrefix + "." + ConfigWebhookID:    resp.WebhookID,
+		prefix + ConfigPrefix + "." + ConfigSecret:       resp.Secret,
+		prefix + registrationConfigPrefix + ".server":    details.Server,
+		prefix + registrationConfigPrefix + ".token":     details.Token,
 	})
 	if err != nil {
 		return fmt.Errorf("unable to register: %w", err)
 	}
+	// Mark the profile as registered.
+	p.Registered = true
+	if err := p.Save(); err != nil {
+		return fmt.Errorf("unable to register: %w", err)
+	}
 
 	return nil
 }
@@ -25,15 +25,18 @@ var content embed.FS
 var CLI struct {
 	logging.Options
 
-	Run cli.Run `cmd:"" help:"Run Go Hass Agent."`
-	// Reset        cmd.Reset            `cmd:"" help:"Reset Go Hass Agent."`
+	Run     cli.Run     `cmd:"" help:"Run Go Hass Agent."`
 	Version cli.Version `cmd:"" help:"Show the Go Hass Agent version."`
 	// Upgrade      cmd.Upgrade          `cmd:"" help:"Attempt to upgrade from previous version."`
-	ProfileFlags logging.ProfileFlags `name:"profile" help:"Set profiling flags."`
-	Config       cli.Config           `cmd:"" help:"Configure Go Hass Agent."`
-	Register     cli.Register         `cmd:"" help:"Register with Home Assistant."`
-	Registry     cli.RegistryCmd      `cmd:"" help:"Registry actions"`
-	Path         string               `name:"path" default:"${defaultPath}" help:"Specify a custom path to store preferences/logs/data (for debugging)."`
+	ProfileFlags  logging.ProfileFlags `name:"profile" help:"Set profiling flags."`
+	Config        cli.Config           `cmd:"" help:"Configure Go Hass Agent."`
+	Register      cli.Register         `cmd:"" help:"Register with Home Assistant."`
+	Reregister    cli.Reregister       `cmd:"" help:"Register again with Home Assistant, keeping the existing registration where possible."`
+	Reset         cli.Reset            `cmd:"" help:"Reset the registration of Go Hass Agent."`
+	Registry      cli.RegistryCmd      `cmd:"" help:"Registry actions"`
+	Notifications cli.NotificationsCmd `cmd:"" help:"Show or clear the history of received notifications."`
+	Profiles      cli.ProfilesCmd      `cmd:"" help:"Manage additional Home Assistant server profiles."`
+	Path          string               `name:"path" default:"${defaultPath}" help:"Specify a custom path to store preferences/logs/data (for debugging)."`
 }
 
 func init() {
@@ -84,7 +87,8 @@ func main() {
 		}
 	}
 	// Run the requested command with the provided options.
-	if err := cmdCtx.Run(&cli.Opts{Path: CLI.Path, StaticContent: content}); err != nil {
+	err = cmdCtx.Run(&cli.Opts{Path: CLI.Path, StaticContent: content})
+	if err != nil {
 		slog.Error("Command failed.",
 			slog.String("command", cmdCtx.Command()),
 			slog.Any("error", err))
@@ -96,4 +100,8 @@ func main() {
 				slog.Any("error", err))
 		}
 	}
+	// Exit with an appropriate code if the command failed.
+	if err != nil {
+		os.Exit(cli.ExitCode(err))
+	}
 }
@@ -0,0 +1,159 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+// Package metrics provides Prometheus/OpenMetrics metrics for the agent. This
+// includes the values of numeric sensors and agent-internal metrics, such as
+// request latency and queue depth.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/joshuar/go-hass-agent/models"
+)
+
+const namespace = "go_hass_agent"
+
+var (
+	enabled  atomic.Bool
+	registry = prometheus.NewRegistry()
+
+	sensors = newSensorCollector()
+
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "request_duration_seconds",
+		Help:      "Duration of requests sent to Home Assistant.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"profile", "type", "result"})
+
+	entitiesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "entities_total",
+		Help:      "Number of entities generated by workers.",
+	}, []string{"worker", "type"})
+
+	entitiesDroppedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "entities_dropped_total",
+		Help:      "Number of entities dropped because a queue was full.",
+	}, []string{"queue"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		sensors,
+		requestDuration,
+		entitiesTotal,
+		entitiesDroppedTotal,
+	)
+}
+
+// Enable enables the collection of sensor metrics. It should be called before
+// any workers are started.
+func Enable() {
+	enabled.Store(true)
+}
+
+// Enabled returns whether metrics are enabled.
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// Handler returns a HTTP handler that serves the metrics, in either Prometheus
+// text or OpenMetrics format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{
+		EnableOpenMetrics: true,
+	})
+}
+
+// ObserveRequest records the duration and result of a request of the given
+// type sent to the Home Assistant server of the given profile.
+func ObserveRequest(profile, requestType string, duration time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+
+	requestDuration.WithLabelValues(profile, requestType, result).Observe(duration.Seconds())
+}
+
+// RegisterQueue registers a metric for the depth of the queue with the given
+// name, as reported by the given function.
+func RegisterQueue(name string, depth func() int) {
+	gauge := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace:   namespace,
+		Name:        "queue_depth",
+		Help:        "Number of entities waiting to be sent.",
+		ConstLabels: prometheus.Labels{"queue": name},
+	}, func() float64 {
+		return float64(depth())
+	})
+
+	var alreadyRegistered prometheus.AlreadyRegisteredError
+	if err := registry.Register(gauge); err != nil && errors.As(err, &alreadyRegistered) {
+		registry.Unregister(alreadyRegistered.ExistingCollector)
+		registry.MustRegister(gauge)
+	}
+}
+
+// ObserveDropped records an entity being dropped from the queue with the given
+// name.
+func ObserveDropped(queue string) {
+	entitiesDroppedTotal.WithLabelValues(queue).Inc()
+}
+
+// ObserveEntities records metrics for the entities sent by the worker with the
+// given ID on the given channel. The entities are passed through unchanged on
+// the returned channel. If metrics are not enabled, the given channel is
+// returned.
+func ObserveEntities(ctx context.Context, worker string, inCh <-chan models.Entity) <-chan models.Entity {
+	if !Enabled() {
+		return inCh
+	}
+
+	outCh := make(chan models.Entity)
+
+	go func() {
+		defer close(outCh)
+
+		for entity := range inCh {
+			observeEntity(worker, entity)
+
+			select {
+			case outCh <- entity:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return outCh
+}
+
+// observeEntity records the metrics for the given entity.
+func observeEntity(worker string, entity models.Entity) {
+	switch {
+	case entity.IsEvent():
+		entitiesTotal.WithLabelValues(worker, "event").Inc()
+	case entity.IsSensor():
+		entitiesTotal.WithLabelValues(worker, "sensor").Inc()
+
+		if sensor, err := entity.AsSensor(); err == nil {
+			sensors.observe(worker, &sensor)
+		}
+	default:
+		entitiesTotal.WithLabelValues(worker, "location").Inc()
+	}
+}
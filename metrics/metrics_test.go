@@ -0,0 +1,145 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshuar/go-hass-agent/models"
+)
+
+func Test_numericValue(t *testing.T) {
+	tests := []struct {
+		name   string
+		state  any
+		want   float64
+		wantOk bool
+	}{
+		{name: "float", state: 1.5, want: 1.5, wantOk: true},
+		{name: "int", state: 42, want: 42, wantOk: true},
+		{name: "uint64", state: uint64(7), want: 7, wantOk: true},
+		{name: "true", state: true, want: 1, wantOk: true},
+		{name: "false", state: false, want: 0, wantOk: true},
+		{name: "string", state: "on"},
+		{name: "unavailable", state: models.StateUnavailable},
+		{name: "nil", state: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := numericValue(tt.state)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.InDelta(t, tt.want, got, 0)
+		})
+	}
+}
+
+func Test_sensorCollector(t *testing.T) {
+	ctx := t.Context()
+	collector := newSensorCollector()
+
+	temperature := models.NewSensor(ctx,
+		models.WithName("Temperature"),
+		models.WithID("temperature"),
+		models.WithState(21.5),
+		models.WithUnits("°C"),
+		models.WithDeviceClass(models.SensorClassTemperature),
+		models.WithStateClass(models.StateMeasurement),
+	)
+	received := models.NewSensor(ctx,
+		models.WithName("Bytes Received"),
+		models.WithID("bytes_received"),
+		models.WithState(1024),
+		models.WithUnits("B"),
+		models.WithStateClass(models.StateTotalIncreasing),
+	)
+	status := models.NewSensor(ctx,
+		models.WithName("Status"),
+		models.WithID("status"),
+		models.WithState("running"),
+	)
+
+	for _, entity := range []models.Entity{temperature, received, status} {
+		sensor, err := entity.AsSensor()
+		require.NoError(t, err)
+		collector.observe("test", &sensor)
+	}
+
+	expected := `
+# HELP go_hass_agent_sensor_total Value of a sensor that is a total that only increases.
+# TYPE go_hass_agent_sensor_total counter
+go_hass_agent_sensor_total{device_class="",name="Bytes Received",sensor="bytes_received",units="B",worker="test"} 1024
+# HELP go_hass_agent_sensor_value Value of a sensor.
+# TYPE go_hass_agent_sensor_value gauge
+go_hass_agent_sensor_value{device_class="temperature",name="Temperature",sensor="temperature",units="°C",worker="test"} 21.5
+`
+	require.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+
+	// A sensor that becomes unavailable should be removed.
+	sensor, err := temperature.AsSensor()
+	require.NoError(t, err)
+	models.AsUnavailable()(&sensor)
+	collector.observe("test", &sensor)
+	assert.Equal(t, 1, testutil.CollectAndCount(collector))
+}
+
+func TestObserveEntities(t *testing.T) {
+	ctx := t.Context()
+
+	event, err := models.NewEvent("test_event", map[string]any{"value": 1})
+	require.NoError(t, err)
+
+	inCh := make(chan models.Entity, 2)
+	inCh <- models.NewSensor(ctx, models.WithName("Observed"), models.WithID("observed"), models.WithState(1))
+	inCh <- event
+	close(inCh)
+
+	// Disabled metrics should pass the channel through untouched.
+	assert.Equal(t, (<-chan models.Entity)(inCh), ObserveEntities(ctx, "observe_test", inCh))
+
+	Enable()
+	t.Cleanup(func() { enabled.Store(false) })
+
+	var got int
+	for range ObserveEntities(ctx, "observe_test", inCh) {
+		got++
+	}
+	assert.Equal(t, 2, got)
+	assert.InDelta(t, 1, testutil.ToFloat64(entitiesTotal.WithLabelValues("observe_test", "sensor")), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(entitiesTotal.WithLabelValues("observe_test", "event")), 0)
+}
+
+func TestObserveRequest(t *testing.T) {
+	ObserveRequest("default", "update_sensor_states", time.Second, nil)
+	ObserveRequest("default", "update_sensor_states", time.Second, errors.New("failed"))
+
+	assert.Equal(t, 2, testutil.CollectAndCount(requestDuration, "go_hass_agent_request_duration_seconds"))
+}
+
+func TestRegisterQueue(t *testing.T) {
+	depth := 3
+	RegisterQueue("test", func() int { return depth })
+	// Registering the same queue again should replace it.
+	RegisterQueue("test", func() int { return depth * 2 })
+
+	expected := `
+# HELP go_hass_agent_queue_depth Number of entities waiting to be sent.
+# TYPE go_hass_agent_queue_depth gauge
+go_hass_agent_queue_depth{queue="test"} 6
+`
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "go_hass_agent_queue_depth"))
+}
+
+func TestObserveDropped(t *testing.T) {
+	ObserveDropped("dropped_test")
+	ObserveDropped("dropped_test")
+
+	assert.InDelta(t, 2, testutil.ToFloat64(entitiesDroppedTotal.WithLabelValues("dropped_test")), 0)
+}
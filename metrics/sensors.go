@@ -0,0 +1,129 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/joshuar/go-hass-agent/models"
+)
+
+var sensorLabels = []string{"sensor", "name", "worker", "units", "device_class"}
+
+// sensorSample is the last value of a numeric sensor.
+type sensorSample struct {
+	labels  []string
+	value   float64
+	counter bool
+}
+
+// sensorCollector is a Prometheus collector for the values of numeric sensors.
+// Sensors with a total_increasing state class are exposed as counters, all
+// other sensors as gauges. Binary sensors are exposed as gauges with a value
+// of 0 (off) or 1 (on).
+type sensorCollector struct {
+	gauge   *prometheus.Desc
+	counter *prometheus.Desc
+	samples map[models.UniqueID]sensorSample
+	mu      sync.Mutex
+}
+
+func newSensorCollector() *sensorCollector {
+	return &sensorCollector{
+		gauge: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "sensor", "value"),
+			"Value of a sensor.",
+			sensorLabels, nil),
+		counter: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "sensor", "total"),
+			"Value of a sensor that is a total that only increases.",
+			sensorLabels, nil),
+		samples: make(map[models.UniqueID]sensorSample),
+	}
+}
+
+// Describe sends the descriptors of the sensor metrics.
+func (c *sensorCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.gauge
+	ch <- c.counter
+}
+
+// Collect sends the last value of each numeric sensor.
+func (c *sensorCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, sample := range c.samples {
+		desc, valueType := c.gauge, prometheus.GaugeValue
+		if sample.counter {
+			desc, valueType = c.counter, prometheus.CounterValue
+		}
+
+		ch <- prometheus.MustNewConstMetric(desc, valueType, sample.value, sample.labels...)
+	}
+}
+
+// observe records the value of the given sensor from the given worker. If the
+// sensor state is not numeric (for example, it has become unavailable), any
+// previous value is removed.
+func (c *sensorCollector) observe(worker string, sensor *models.Sensor) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	value, ok := numericValue(sensor.State)
+	if !ok {
+		delete(c.samples, sensor.UniqueID)
+
+		return
+	}
+
+	c.samples[sensor.UniqueID] = sensorSample{
+		labels:  []string{sensor.UniqueID, sensor.Name, worker, sensor.UnitOfMeasurement, sensor.DeviceClass},
+		value:   value,
+		counter: sensor.StateClass == models.StateTotalIncreasing.String(),
+	}
+}
+
+// numericValue returns the given sensor state as a float, if it is numeric or
+// boolean.
+//
+//nolint:cyclop
+func numericValue(state any) (float64, bool) {
+	switch value := state.(type) {
+	case float64:
+		return value, true
+	case float32:
+		return float64(value), true
+	case int:
+		return float64(value), true
+	case int8:
+		return float64(value), true
+	case int16:
+		return float64(value), true
+	case int32:
+		return float64(value), true
+	case int64:
+		return float64(value), true
+	case uint:
+		return float64(value), true
+	case uint8:
+		return float64(value), true
+	case uint16:
+		return float64(value), true
+	case uint32:
+		return float64(value), true
+	case uint64:
+		return float64(value), true
+	case bool:
+		if value {
+			return 1, true
+		}
+
+		return 0, true
+	default:
+		return 0, false
+	}
+}
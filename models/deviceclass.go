@@ -102,3 +102,15 @@ type SensorDeviceClass int
 func (c SensorDeviceClass) Valid() bool {
 	return c > SensorClassMin && c != SensorClassMax && c != BinaryClassMin && c < BinaryClassMax
 }
+
+// ParseSensorDeviceClass returns the device class with the given name. If the name is not a valid device class,
+// SensorClassMin (i.e., no device class) is returned.
+func ParseSensorDeviceClass(name string) SensorDeviceClass {
+	for class := SensorClassMin + 1; class < BinaryClassMax; class++ {
+		if class.Valid() && class.String() == name {
+			return class
+		}
+	}
+
+	return SensorClassMin
+}
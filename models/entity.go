@@ -10,3 +10,22 @@ package models
 func (e *Entity) Valid() bool {
 	return e.union != nil
 }
+
+// IsEvent returns whether the entity contains event data.
+func (e *Entity) IsEvent() bool {
+	event, err := e.AsEvent()
+	return err == nil && event.Valid()
+}
+
+// IsSensor returns whether the entity contains sensor data, rather than an
+// event or location update.
+func (e *Entity) IsSensor() bool {
+	if e.IsEvent() {
+		return false
+	}
+	if location, err := e.AsLocation(); err == nil && location.Valid() {
+		return false
+	}
+	sensor, err := e.AsSensor()
+	return err == nil && sensor.UniqueID != ""
+}
@@ -41,6 +41,36 @@ func WithAltitude(altitude int) LocationOption {
 	}
 }
 
+// WithCourse option sets the course (direction of travel) value for the
+// location.
+func WithCourse(course int) LocationOption {
+	return func(l *Location) {
+		l.Course = course
+	}
+}
+
+// WithVerticalAccuracy option sets the accuracy of the altitude value for the
+// location.
+func WithVerticalAccuracy(accuracy int) LocationOption {
+	return func(l *Location) {
+		l.VerticalAccuracy = accuracy
+	}
+}
+
+// WithBattery option sets the battery percentage value for the location.
+func WithBattery(battery int) LocationOption {
+	return func(l *Location) {
+		l.Battery = battery
+	}
+}
+
+// WithLocationName option sets the name of the zone the device is in.
+func WithLocationName(name string) LocationOption {
+	return func(l *Location) {
+		l.LocationName = name
+	}
+}
+
 // NewLocation provides a way to build a location entity with the given options.
 func NewLocation(_ context.Context, options ...LocationOption) (*Entity, error) {
 	location := Location{}
@@ -39,6 +39,9 @@ func (s *SensorRegistration) Valid() (bool, error) {
 	return true, nil
 }
 
+// StateUnavailable is the state of a sensor that is unavailable.
+const StateUnavailable = "unavailable"
+
 // SensorOption is a functional option for a sensor.
 type SensorOption Option[*Sensor]
 
@@ -172,6 +175,22 @@ func AsDiagnostic() SensorOption {
 	}
 }
 
+// AsDisabledByDefault option sets the sensor entity to be disabled when it is
+// registered in Home Assistant. It can be enabled from the Home Assistant UI.
+func AsDisabledByDefault(value bool) SensorOption {
+	return func(s *Sensor) {
+		s.Disabled = value
+	}
+}
+
+// AsUnavailable option sets the state of the sensor to unavailable, which Home
+// Assistant will show as such.
+func AsUnavailable() SensorOption {
+	return func(s *Sensor) {
+		s.State = StateUnavailable
+	}
+}
+
 // AsRetryableRequest option sets a flag on the sensor that indicates the requests sent
 // to Home Assistant related to this sensor should be retried.
 func AsRetryableRequest(value bool) SensorOption {
@@ -19,3 +19,15 @@ type SensorStateClass int
 func (c SensorStateClass) Valid() bool {
 	return c > StateClassMin && c < StateClassMax
 }
+
+// ParseSensorStateClass returns the state class with the given name. If the name is not a valid state class,
+// StateClassMin (i.e., no state class) is returned.
+func ParseSensorStateClass(name string) SensorStateClass {
+	for class := StateClassMin + 1; class.Valid(); class++ {
+		if class.String() == name {
+			return class
+		}
+	}
+
+	return StateClassMin
+}
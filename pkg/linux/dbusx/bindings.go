@@ -0,0 +1,100 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package dbusx
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// CallMethod calls the given method on the object at the given path of the given destination and stores the values of
+// its reply in results, which must be pointers to values of the types of the method's out arguments. It is used by the
+// bindings generated by dbusxgen. If the call fails or the reply cannot be stored, a non-nil error is returned.
+func CallMethod(ctx context.Context, bus *Bus, dest, path, method string, args []any, results ...any) error {
+	if bus == nil {
+		return ErrNoBus
+	}
+
+	bus.traceLog("Calling method.", slog.String("path", path), slog.String("dest", dest), slog.String("method", method))
+
+	called := bus.getObject(dest, path).CallWithContext(ctx, method, 0, args...)
+	if called.Err != nil {
+		return fmt.Errorf("%s: unable to call method %s: %w", bus.busType.String(), method, called.Err)
+	}
+
+	if len(results) == 0 {
+		return nil
+	}
+
+	if err := called.Store(results...); err != nil {
+		return fmt.Errorf("%s: unable to store method %s results: %w", bus.busType.String(), method, err)
+	}
+
+	return nil
+}
+
+// WatchSignal watches for the given signal of the given interface, sent from the object at the given path (or any
+// object if path is empty). The arguments of each signal are stored in a value of type S, a struct with one exported
+// field per signal argument, and sent on the returned channel. The channel is closed when the context is canceled. It
+// is used by the bindings generated by dbusxgen.
+func WatchSignal[S any](ctx context.Context, bus *Bus, path, intr, name string) (<-chan S, error) {
+	if bus == nil {
+		return nil, ErrNoBus
+	}
+
+	options := []WatchOption{MatchInterface(intr), MatchMembers(name)}
+	if path != "" {
+		options = append(options, MatchPath(path))
+	}
+
+	triggerCh, err := NewWatch(options...).Start(ctx, bus)
+	if err != nil {
+		return nil, fmt.Errorf("watch signal %s.%s: %w", intr, name, err)
+	}
+
+	signalCh := make(chan S)
+
+	go func() {
+		defer close(signalCh)
+
+		for trigger := range triggerCh {
+			if trigger.Signal != intr+"."+name {
+				continue
+			}
+
+			signal, err := storeSignal[S](trigger.Content)
+			if err != nil {
+				bus.traceLog("Ignoring signal.", slog.String("signal", trigger.Signal), slog.Any("error", err))
+
+				continue
+			}
+
+			select {
+			case signalCh <- signal:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return signalCh, nil
+}
+
+// storeSignal stores the given signal arguments in the fields of a struct of type S.
+func storeSignal[S any](args []any) (S, error) {
+	var signal S
+
+	if args == nil {
+		args = []any{}
+	}
+
+	if err := dbus.Store([]any{args}, &signal); err != nil {
+		return signal, fmt.Errorf("unable to store signal arguments as %T: %w", signal, err)
+	}
+
+	return signal, nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package dbusx
+
+import (
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_storeSignal(t *testing.T) {
+	type sessionSignal struct {
+		SessionID  string
+		ObjectPath dbus.ObjectPath
+	}
+
+	type seat struct {
+		ID   string
+		Path dbus.ObjectPath
+	}
+
+	type seatsSignal struct {
+		Seats []seat
+	}
+
+	t.Run("arguments", func(t *testing.T) {
+		got, err := storeSignal[sessionSignal]([]any{"2", dbus.ObjectPath("/org/freedesktop/login1/session/_32")})
+		require.NoError(t, err)
+		assert.Equal(t, sessionSignal{SessionID: "2", ObjectPath: "/org/freedesktop/login1/session/_32"}, got)
+	})
+
+	t.Run("struct arguments", func(t *testing.T) {
+		got, err := storeSignal[seatsSignal]([]any{[][]any{{"seat0", dbus.ObjectPath("/org/freedesktop/login1/seat/seat0")}}})
+		require.NoError(t, err)
+		assert.Equal(t, seatsSignal{Seats: []seat{{ID: "seat0", Path: "/org/freedesktop/login1/seat/seat0"}}}, got)
+	})
+
+	t.Run("no arguments", func(t *testing.T) {
+		_, err := storeSignal[struct{}](nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("wrong number of arguments", func(t *testing.T) {
+		_, err := storeSignal[sessionSignal]([]any{"2"})
+		require.Error(t, err)
+	})
+
+	t.Run("wrong argument type", func(t *testing.T) {
+		_, err := storeSignal[sessionSignal]([]any{"2", true})
+		require.Error(t, err)
+	})
+}
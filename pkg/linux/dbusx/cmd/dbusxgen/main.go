@@ -0,0 +1,88 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+// Command dbusxgen generates typed Go bindings for D-Bus interfaces from their introspection XML. It is intended to
+// be used with go generate, e.g.:
+//
+//	//go:generate go run github.com/joshuar/go-hass-agent/pkg/linux/dbusx/cmd/dbusxgen -output login1.gen.go login1.xml
+//
+// The introspection XML for a D-Bus object can be retrieved with:
+//
+//	busctl introspect --xml-interface org.freedesktop.login1 /org/freedesktop/login1
+//
+// See the dbusxgen package for the annotations that change the generated names.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/joshuar/go-hass-agent/pkg/linux/dbusx/dbusxgen"
+)
+
+func main() {
+	var (
+		pkg        = flag.String("package", os.Getenv("GOPACKAGE"), "package name of the generated code (default $GOPACKAGE)")
+		output     = flag.String("output", "", "output file name (default stdout)")
+		interfaces = flag.String("interfaces", "", "comma-separated list of interfaces to generate (default all)")
+	)
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: dbusxgen [flags] introspection.xml\n")
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	if flag.NArg() != 1 || *pkg == "" {
+		flag.Usage()
+		os.Exit(2) //nolint:mnd
+	}
+
+	if err := run(flag.Arg(0), *pkg, *output, *interfaces); err != nil {
+		fmt.Fprintf(os.Stderr, "dbusxgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(input, pkg, output, interfaces string) error {
+	file, err := os.Open(input)
+	if err != nil {
+		return fmt.Errorf("open introspection: %w", err)
+	}
+	defer file.Close() //nolint:errcheck
+
+	node, err := dbusxgen.Parse(file)
+	if err != nil {
+		return fmt.Errorf("%s: %w", input, err)
+	}
+
+	options := dbusxgen.Options{
+		Package: pkg,
+		Source:  filepath.Base(input),
+	}
+
+	if interfaces != "" {
+		options.Interfaces = strings.Split(interfaces, ",")
+	}
+
+	src, err := dbusxgen.Generate(node, options)
+	if err != nil {
+		return fmt.Errorf("%s: %w", input, err)
+	}
+
+	if output == "" {
+		_, err = os.Stdout.Write(src)
+	} else {
+		err = os.WriteFile(output, src, 0o644) //nolint:gosec,mnd
+	}
+
+	if err != nil {
+		return fmt.Errorf("write bindings: %w", err)
+	}
+
+	return nil
+}
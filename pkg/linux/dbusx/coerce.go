@@ -0,0 +1,528 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package dbusx
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+)
+
+var (
+	// ErrInvalidArgs indicates the arguments for a method do not match its
+	// signature.
+	ErrInvalidArgs = errors.New("invalid arguments")
+	// ErrUnsupportedSignature indicates a value cannot be converted to a D-Bus
+	// type.
+	ErrUnsupportedSignature = errors.New("unsupported signature")
+)
+
+// CoerceArgs validates the given arguments against the given (introspected)
+// method arguments, converting each to the Go type for the D-Bus type of the
+// argument. Only the input arguments of the method are considered. This allows
+// arguments from untyped sources, such as JSON, to be passed to a method. If
+// the number of arguments does not match or an argument cannot be converted, a
+// non-nil error is returned.
+func CoerceArgs(args []any, methodArgs []introspect.Arg) ([]any, error) {
+	inArgs := make([]introspect.Arg, 0, len(methodArgs))
+	for _, arg := range methodArgs {
+		if arg.Direction != "out" {
+			inArgs = append(inArgs, arg)
+		}
+	}
+
+	if len(args) != len(inArgs) {
+		return nil, fmt.Errorf("%w: expected %d arguments, got %d", ErrInvalidArgs, len(inArgs), len(args))
+	}
+
+	coerced := make([]any, len(args))
+
+	for idx, arg := range inArgs {
+		value, err := CoerceValue(args[idx], arg.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%w: argument %d (%s): %w", ErrInvalidArgs, idx, arg.Name, err)
+		}
+
+		coerced[idx] = value
+	}
+
+	return coerced, nil
+}
+
+// CoerceValue converts the given value to the Go type for the given D-Bus type
+// signature (which must be a single complete type). Numbers are range-checked
+// and can also be given as strings. Arrays, dicts and structs are converted
+// from slices, maps and slices respectively.
+func CoerceValue(value any, signature string) (any, error) {
+	coerced, err := coerce(value, signature)
+	if err != nil {
+		return nil, err
+	}
+
+	return coerced.Interface(), nil
+}
+
+// PlainValue converts the given value received from D-Bus into a value made up
+// of only basic Go types, maps and slices, suitable for encoding (e.g., as
+// JSON). Variants are replaced by their values, object paths and signatures by
+// their string form and structs by a slice of their fields.
+func PlainValue(value any) any {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case dbus.Variant:
+		return PlainValue(v.Value())
+	case dbus.ObjectPath:
+		return string(v)
+	case dbus.Signature:
+		return v.String()
+	case []byte:
+		return v
+	}
+
+	rv := reflect.ValueOf(value)
+
+	switch rv.Kind() { //nolint:exhaustive // other kinds are already plain values.
+	case reflect.Slice, reflect.Array:
+		values := make([]any, rv.Len())
+		for idx := range rv.Len() {
+			values[idx] = PlainValue(rv.Index(idx).Interface())
+		}
+
+		return values
+	case reflect.Map:
+		values := make(map[string]any, rv.Len())
+		for iter := rv.MapRange(); iter.Next(); {
+			values[fmt.Sprint(PlainValue(iter.Key().Interface()))] = PlainValue(iter.Value().Interface())
+		}
+
+		return values
+	case reflect.Struct:
+		values := make([]any, 0, rv.NumField())
+		for idx := range rv.NumField() {
+			if rv.Type().Field(idx).IsExported() {
+				values = append(values, PlainValue(rv.Field(idx).Interface()))
+			}
+		}
+
+		return values
+	default:
+		return value
+	}
+}
+
+// coerce converts the given value to the Go type for the given D-Bus type
+// signature.
+//
+//nolint:cyclop,funlen
+func coerce(value any, signature string) (reflect.Value, error) {
+	typ, err := typeFor(signature)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	if value == nil {
+		return reflect.Value{}, fmt.Errorf("missing value for type %s", signature)
+	}
+
+	rv := reflect.ValueOf(value)
+	result := reflect.New(typ).Elem()
+
+	switch signature[0] {
+	case 'y', 'q', 'u', 't':
+		n, err := toUint(rv)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		if result.OverflowUint(n) {
+			return reflect.Value{}, fmt.Errorf("%d overflows type %s", n, signature)
+		}
+
+		result.SetUint(n)
+	case 'n', 'i', 'x':
+		n, err := toInt(rv)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		if result.OverflowInt(n) {
+			return reflect.Value{}, fmt.Errorf("%d overflows type %s", n, signature)
+		}
+
+		result.SetInt(n)
+	case 'd':
+		n, err := toFloat(rv)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		result.SetFloat(n)
+	case 'b':
+		b, err := toBool(rv)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		result.SetBool(b)
+	case 's':
+		if rv.Kind() != reflect.String {
+			return reflect.Value{}, fmt.Errorf("%v is not a string", value)
+		}
+
+		result.SetString(rv.String())
+	case 'o':
+		if rv.Kind() != reflect.String || !dbus.ObjectPath(rv.String()).IsValid() {
+			return reflect.Value{}, fmt.Errorf("%v is not an object path", value)
+		}
+
+		result.SetString(rv.String())
+	case 'g':
+		if rv.Kind() != reflect.String {
+			return reflect.Value{}, fmt.Errorf("%v is not a signature", value)
+		}
+
+		sig, err := dbus.ParseSignature(rv.String())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("%v is not a signature: %w", value, err)
+		}
+
+		result.Set(reflect.ValueOf(sig))
+	case 'v':
+		if variant, ok := value.(dbus.Variant); ok {
+			result.Set(reflect.ValueOf(variant))
+		} else {
+			result.Set(reflect.ValueOf(dbus.MakeVariant(value)))
+		}
+	case 'a':
+		if signature[1] == '{' {
+			return coerceDict(rv, signature, typ)
+		}
+
+		return coerceArray(rv, signature, typ)
+	case '(':
+		return coerceStruct(rv, signature, typ)
+	default:
+		return reflect.Value{}, fmt.Errorf("%w: %s", ErrUnsupportedSignature, signature)
+	}
+
+	return result, nil
+}
+
+// coerceArray converts the given slice to an array of the given type.
+func coerceArray(rv reflect.Value, signature string, typ reflect.Type) (reflect.Value, error) {
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return reflect.Value{}, fmt.Errorf("%v is not an array", rv.Interface())
+	}
+
+	result := reflect.MakeSlice(typ, rv.Len(), rv.Len())
+
+	for idx := range rv.Len() {
+		elem, err := coerce(rv.Index(idx).Interface(), signature[1:])
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("element %d: %w", idx, err)
+		}
+
+		result.Index(idx).Set(elem)
+	}
+
+	return result, nil
+}
+
+// coerceDict converts the given map to a dict of the given type. As JSON
+// objects only have string keys, keys are also converted.
+func coerceDict(rv reflect.Value, signature string, typ reflect.Type) (reflect.Value, error) {
+	if rv.Kind() != reflect.Map {
+		return reflect.Value{}, fmt.Errorf("%v is not a dict", rv.Interface())
+	}
+
+	// Signature is a{kv}, where k is a basic type.
+	keySig := signature[2:3]
+	valueSig := signature[3 : len(signature)-1]
+	result := reflect.MakeMapWithSize(typ, rv.Len())
+
+	for iter := rv.MapRange(); iter.Next(); {
+		key, err := coerce(iter.Key().Interface(), keySig)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("key %v: %w", iter.Key().Interface(), err)
+		}
+
+		value, err := coerce(iter.Value().Interface(), valueSig)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("value of %v: %w", iter.Key().Interface(), err)
+		}
+
+		result.SetMapIndex(key, value)
+	}
+
+	return result, nil
+}
+
+// coerceStruct converts the given slice of fields to a struct of the given
+// type.
+func coerceStruct(rv reflect.Value, signature string, typ reflect.Type) (reflect.Value, error) {
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return reflect.Value{}, fmt.Errorf("%v is not a struct (array of fields)", rv.Interface())
+	}
+
+	fieldSigs, err := splitTypes(signature[1 : len(signature)-1])
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	if rv.Len() != len(fieldSigs) {
+		return reflect.Value{}, fmt.Errorf("expected %d struct fields, got %d", len(fieldSigs), rv.Len())
+	}
+
+	result := reflect.New(typ).Elem()
+
+	for idx, fieldSig := range fieldSigs {
+		field, err := coerce(rv.Index(idx).Interface(), fieldSig)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("field %d: %w", idx, err)
+		}
+
+		result.Field(idx).Set(field)
+	}
+
+	return result, nil
+}
+
+// typeFor returns the Go type used for the given D-Bus type signature.
+//
+//nolint:cyclop
+func typeFor(signature string) (reflect.Type, error) {
+	if _, err := dbus.ParseSignature(signature); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrUnsupportedSignature, signature, err)
+	}
+
+	if length, err := typeLength(signature); err != nil || length != len(signature) {
+		return nil, fmt.Errorf("%w: %s is not a single complete type", ErrUnsupportedSignature, signature)
+	}
+
+	switch signature[0] {
+	case 'y':
+		return reflect.TypeFor[byte](), nil
+	case 'b':
+		return reflect.TypeFor[bool](), nil
+	case 'n':
+		return reflect.TypeFor[int16](), nil
+	case 'q':
+		return reflect.TypeFor[uint16](), nil
+	case 'i':
+		return reflect.TypeFor[int32](), nil
+	case 'u':
+		return reflect.TypeFor[uint32](), nil
+	case 'x':
+		return reflect.TypeFor[int64](), nil
+	case 't':
+		return reflect.TypeFor[uint64](), nil
+	case 'd':
+		return reflect.TypeFor[float64](), nil
+	case 's':
+		return reflect.TypeFor[string](), nil
+	case 'o':
+		return reflect.TypeFor[dbus.ObjectPath](), nil
+	case 'g':
+		return reflect.TypeFor[dbus.Signature](), nil
+	case 'v':
+		return reflect.TypeFor[dbus.Variant](), nil
+	case 'a':
+		if signature[1] == '{' {
+			keyType, err := typeFor(signature[2:3])
+			if err != nil {
+				return nil, err
+			}
+
+			valueType, err := typeFor(signature[3 : len(signature)-1])
+			if err != nil {
+				return nil, err
+			}
+
+			return reflect.MapOf(keyType, valueType), nil
+		}
+
+		elemType, err := typeFor(signature[1:])
+		if err != nil {
+			return nil, err
+		}
+
+		return reflect.SliceOf(elemType), nil
+	case '(':
+		fieldSigs, err := splitTypes(signature[1 : len(signature)-1])
+		if err != nil {
+			return nil, err
+		}
+		// Structs are encoded by D-Bus from the exported fields of a struct, in
+		// order.
+		fields := make([]reflect.StructField, 0, len(fieldSigs))
+
+		for idx, fieldSig := range fieldSigs {
+			fieldType, err := typeFor(fieldSig)
+			if err != nil {
+				return nil, err
+			}
+
+			fields = append(fields, reflect.StructField{Name: "F" + strconv.Itoa(idx), Type: fieldType})
+		}
+
+		return reflect.StructOf(fields), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedSignature, signature)
+	}
+}
+
+// splitTypes splits the given signature into its complete types.
+func splitTypes(signature string) ([]string, error) {
+	var types []string
+
+	for signature != "" {
+		length, err := typeLength(signature)
+		if err != nil {
+			return nil, err
+		}
+
+		types = append(types, signature[:length])
+		signature = signature[length:]
+	}
+
+	return types, nil
+}
+
+// typeLength returns the length of the first complete type in the given
+// signature.
+func typeLength(signature string) (int, error) {
+	if signature == "" {
+		return 0, fmt.Errorf("%w: empty signature", ErrUnsupportedSignature)
+	}
+
+	switch signature[0] {
+	case 'a':
+		length, err := typeLength(signature[1:])
+		if err != nil {
+			return 0, err
+		}
+
+		return length + 1, nil
+	case '(', '{':
+		depth := 0
+
+		for idx, char := range signature {
+			switch char {
+			case '(', '{':
+				depth++
+			case ')', '}':
+				depth--
+			}
+
+			if depth == 0 {
+				return idx + 1, nil
+			}
+		}
+
+		return 0, fmt.Errorf("%w: unbalanced signature %s", ErrUnsupportedSignature, signature)
+	default:
+		return 1, nil
+	}
+}
+
+// toInt converts the given value (a number or a string) to an integer.
+func toInt(rv reflect.Value) (int64, error) {
+	switch rv.Kind() { //nolint:exhaustive // other kinds are not numbers.
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if rv.Uint() > math.MaxInt64 {
+			return 0, fmt.Errorf("%d overflows int64", rv.Uint())
+		}
+
+		return int64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if f != math.Trunc(f) || f < math.MinInt64 || f > math.MaxInt64 {
+			return 0, fmt.Errorf("%v is not an integer", f)
+		}
+
+		return int64(f), nil
+	case reflect.String:
+		n, err := strconv.ParseInt(rv.String(), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not an integer", rv.String())
+		}
+
+		return n, nil
+	default:
+		return 0, fmt.Errorf("%v is not an integer", rv.Interface())
+	}
+}
+
+// toUint converts the given value (a number or a string) to an unsigned
+// integer.
+func toUint(rv reflect.Value) (uint64, error) {
+	switch rv.Kind() { //nolint:exhaustive // other kinds are not numbers.
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return rv.Uint(), nil
+	case reflect.String:
+		n, err := strconv.ParseUint(rv.String(), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not an unsigned integer", rv.String())
+		}
+
+		return n, nil
+	default:
+		n, err := toInt(rv)
+		if err != nil {
+			return 0, err
+		}
+
+		if n < 0 {
+			return 0, fmt.Errorf("%d is not an unsigned integer", n)
+		}
+
+		return uint64(n), nil
+	}
+}
+
+// toFloat converts the given value (a number or a string) to a float.
+func toFloat(rv reflect.Value) (float64, error) {
+	switch rv.Kind() { //nolint:exhaustive // other kinds are not numbers.
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), nil
+	case reflect.String:
+		f, err := strconv.ParseFloat(rv.String(), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a number", rv.String())
+		}
+
+		return f, nil
+	default:
+		return 0, fmt.Errorf("%v is not a number", rv.Interface())
+	}
+}
+
+// toBool converts the given value (a boolean or a string) to a boolean.
+func toBool(rv reflect.Value) (bool, error) {
+	switch rv.Kind() { //nolint:exhaustive // other kinds are not booleans.
+	case reflect.Bool:
+		return rv.Bool(), nil
+	case reflect.String:
+		b, err := strconv.ParseBool(rv.String())
+		if err != nil {
+			return false, fmt.Errorf("%q is not a boolean", rv.String())
+		}
+
+		return b, nil
+	default:
+		return false, fmt.Errorf("%v is not a boolean", rv.Interface())
+	}
+}
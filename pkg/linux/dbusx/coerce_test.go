@@ -0,0 +1,145 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package dbusx
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoerceValue(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     any
+		signature string
+		want      any
+		wantErr   bool
+	}{
+		{name: "uint32 from float", value: 5000.0, signature: "u", want: uint32(5000)},
+		{name: "uint32 from string", value: "5000", signature: "u", want: uint32(5000)},
+		{name: "uint32 from typed", value: uint32(1), signature: "u", want: uint32(1)},
+		{name: "negative uint32", value: -1.0, signature: "u", wantErr: true},
+		{name: "byte overflow", value: 256.0, signature: "y", wantErr: true},
+		{name: "int32", value: -5.0, signature: "i", want: int32(-5)},
+		{name: "int32 fraction", value: 1.5, signature: "i", wantErr: true},
+		{name: "int16 overflow", value: 40000.0, signature: "n", wantErr: true},
+		{name: "double", value: 1.5, signature: "d", want: 1.5},
+		{name: "bool", value: true, signature: "b", want: true},
+		{name: "bool from string", value: "false", signature: "b", want: false},
+		{name: "string", value: "foo", signature: "s", want: "foo"},
+		{name: "string from number", value: 1.0, signature: "s", wantErr: true},
+		{name: "object path", value: "/org/freedesktop/foo", signature: "o", want: dbus.ObjectPath("/org/freedesktop/foo")},
+		{name: "invalid object path", value: "foo", signature: "o", wantErr: true},
+		{name: "variant", value: "foo", signature: "v", want: dbus.MakeVariant("foo")},
+		{name: "string array", value: []any{"a", "b"}, signature: "as", want: []string{"a", "b"}},
+		{name: "empty string array", value: []any{}, signature: "as", want: []string{}},
+		{name: "invalid array element", value: []any{"a", 1.0}, signature: "as", wantErr: true},
+		{name: "not an array", value: "a", signature: "as", wantErr: true},
+		{name: "dict", value: map[string]any{"urgency": 1.0}, signature: "a{sv}", want: map[string]dbus.Variant{"urgency": dbus.MakeVariant(1.0)}},
+		{name: "dict with uint keys", value: map[string]any{"1": "a"}, signature: "a{us}", want: map[uint32]string{1: "a"}},
+		{name: "struct", value: []any{"a", 1.0}, signature: "(si)", want: struct {
+			F0 string
+			F1 int32
+		}{"a", 1}},
+		{name: "struct field count", value: []any{"a"}, signature: "(si)", wantErr: true},
+		{name: "missing value", value: nil, signature: "s", wantErr: true},
+		{name: "multiple types", value: "a", signature: "ss", wantErr: true},
+		{name: "unsupported type", value: 1.0, signature: "h", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CoerceValue(tt.value, tt.signature)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			// The result must have the requested D-Bus signature.
+			assert.Equal(t, tt.signature, dbus.SignatureOf(got).String())
+		})
+	}
+}
+
+func TestCoerceArgs(t *testing.T) {
+	// org.freedesktop.Notifications.Notify.
+	methodArgs := []introspect.Arg{
+		{Name: "app_name", Type: "s", Direction: "in"},
+		{Name: "replaces_id", Type: "u", Direction: "in"},
+		{Name: "app_icon", Type: "s", Direction: "in"},
+		{Name: "summary", Type: "s", Direction: "in"},
+		{Name: "body", Type: "s", Direction: "in"},
+		{Name: "actions", Type: "as", Direction: "in"},
+		{Name: "hints", Type: "a{sv}", Direction: "in"},
+		{Name: "expire_timeout", Type: "i", Direction: "in"},
+		{Name: "id", Type: "u", Direction: "out"},
+	}
+
+	var args []any
+	require.NoError(t, json.Unmarshal([]byte(`["app", 0, "icon", "summary", "body", [], {}, 5000]`), &args))
+
+	got, err := CoerceArgs(args, methodArgs)
+	require.NoError(t, err)
+	assert.Equal(t, []any{"app", uint32(0), "icon", "summary", "body", []string{}, map[string]dbus.Variant{}, int32(5000)}, got)
+
+	_, err = CoerceArgs(args[:3], methodArgs)
+	require.ErrorIs(t, err, ErrInvalidArgs)
+
+	args[1] = "not a number"
+	_, err = CoerceArgs(args, methodArgs)
+	require.ErrorIs(t, err, ErrInvalidArgs)
+}
+
+func TestPlainValue(t *testing.T) {
+	value := []any{
+		dbus.MakeVariant(dbus.ObjectPath("/foo")),
+		map[string]dbus.Variant{"bar": dbus.MakeVariant(uint32(1))},
+		struct {
+			A string
+			B []dbus.ObjectPath
+		}{"a", []dbus.ObjectPath{"/b"}},
+	}
+
+	got, err := json.Marshal(PlainValue(value))
+	require.NoError(t, err)
+	assert.JSONEq(t, `["/foo", {"bar": 1}, ["a", ["/b"]]]`, string(got))
+}
+
+func TestIntrospection_GetMethod(t *testing.T) {
+	introspection := Introspection{
+		Name: "/org/freedesktop/Notifications",
+		Interfaces: []introspect.Interface{
+			{Name: "org.freedesktop.DBus.Properties", Methods: []introspect.Method{{Name: "Get"}}},
+			{
+				Name:       "org.freedesktop.Notifications",
+				Methods:    []introspect.Method{{Name: "GetServerInformation"}, {Name: "Notify"}},
+				Properties: []introspect.Property{{Name: "Inhibited", Type: "b", Access: "read"}},
+			},
+		},
+	}
+
+	method, err := introspection.GetMethod("org.freedesktop.Notifications.Notify")
+	require.NoError(t, err)
+	assert.Equal(t, "org.freedesktop.Notifications", method.intr)
+	assert.Equal(t, "Notify", method.obj.Name)
+
+	method, err = introspection.GetMethod("Get")
+	require.NoError(t, err)
+	assert.Equal(t, "Get", method.obj.Name)
+
+	_, err = introspection.GetMethod("org.freedesktop.Notifications.Get")
+	require.ErrorIs(t, err, ErrMethodNotFound)
+
+	property, err := introspection.GetProperty("org.freedesktop.Notifications", "Inhibited")
+	require.NoError(t, err)
+	assert.Equal(t, "b", property.Type)
+
+	_, err = introspection.GetProperty("org.freedesktop.DBus.Properties", "Inhibited")
+	require.ErrorIs(t, err, ErrPropertyNotFound)
+}
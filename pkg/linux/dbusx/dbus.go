@@ -174,6 +174,17 @@ func (b *Bus) ListNames() ([]string, error) {
 	return names, nil
 }
 
+// GetNameOwner returns the unique name of the connection that owns the given
+// name on the bus.
+func (b *Bus) GetNameOwner(name string) (string, error) {
+	var owner string
+	err := b.conn.BusObject().Call("org.freedesktop.DBus.GetNameOwner", 0, name).Store(&owner)
+	if err != nil {
+		return "", fmt.Errorf("unable to retrieve owner of %s: %w", name, err)
+	}
+	return owner, nil
+}
+
 func (b *Bus) getObject(intr, path string) dbus.BusObject {
 	return b.conn.Object(intr, dbus.ObjectPath(path))
 }
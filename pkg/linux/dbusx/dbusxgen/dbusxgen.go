@@ -0,0 +1,723 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+// Package dbusxgen generates typed Go bindings for D-Bus interfaces from their introspection XML. For each interface,
+// a client struct is generated with a method for each D-Bus method, getters (and setters) for each property and a
+// watch method for each signal that returns a channel of typed signal values. The bindings use the dbusx package.
+//
+// The generated names can be changed with annotations in the introspection XML:
+//
+//   - dbusx.Name on an interface, method, property or signal sets the Go name of the client struct or its method.
+//   - dbusx.Type on an arg or property with a struct type sets the name of the generated Go struct type.
+//   - dbusx.Fields on an arg or property with a struct type sets the (comma-separated) field names of the generated Go
+//     struct type. Otherwise, the fields are named Field0, Field1, and so on.
+package dbusxgen
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+)
+
+const (
+	// NameAnnotation sets the Go name of an interface client or of a method, property or signal.
+	NameAnnotation = "dbusx.Name"
+	// TypeAnnotation sets the name of the Go struct type generated for an arg or property with a struct type.
+	TypeAnnotation = "dbusx.Type"
+	// FieldsAnnotation sets the (comma-separated) field names of the Go struct type generated for an arg or property
+	// with a struct type.
+	FieldsAnnotation = "dbusx.Fields"
+)
+
+var (
+	// ErrInvalidSignature indicates a type signature in the introspection XML could not be parsed.
+	ErrInvalidSignature = errors.New("invalid type signature")
+	// ErrNameConflict indicates two generated types or methods have the same name. Use the dbusx.Name or dbusx.Type
+	// annotations to rename one of them.
+	ErrNameConflict = errors.New("name conflict")
+	// ErrNoInterfaces indicates there are no interfaces to generate bindings for.
+	ErrNoInterfaces = errors.New("no interfaces found")
+)
+
+// basicTypes maps the D-Bus basic type codes to Go types.
+var basicTypes = map[byte]string{
+	'y': "byte",
+	'b': "bool",
+	'n': "int16",
+	'q': "uint16",
+	'i': "int32",
+	'u': "uint32",
+	'x': "int64",
+	't': "uint64",
+	'd': "float64",
+	'h': "dbus.UnixFD",
+	's': "string",
+	'o': "dbus.ObjectPath",
+	'g': "dbus.Signature",
+	'v': "dbus.Variant",
+}
+
+// reserved are the identifiers used by generated methods that arguments cannot be named.
+var reserved = []string{"c", "ctx", "err", "dbus", "dbusx", "context"}
+
+// Node is a node in the D-Bus introspection XML. It differs from the godbus introspect.Node in allowing annotations
+// on arguments.
+type Node struct {
+	XMLName    xml.Name    `xml:"node"`
+	Name       string      `xml:"name,attr,omitempty"`
+	Interfaces []Interface `xml:"interface"`
+	Children   []Node      `xml:"node,omitempty"`
+}
+
+// Interface is a D-Bus interface.
+type Interface struct {
+	Name        string       `xml:"name,attr"`
+	Methods     []Method     `xml:"method"`
+	Signals     []Signal     `xml:"signal"`
+	Properties  []Property   `xml:"property"`
+	Annotations []Annotation `xml:"annotation"`
+}
+
+// Method is a method of a D-Bus interface.
+type Method struct {
+	Name        string       `xml:"name,attr"`
+	Args        []Arg        `xml:"arg"`
+	Annotations []Annotation `xml:"annotation"`
+}
+
+// Signal is a signal of a D-Bus interface.
+type Signal struct {
+	Name        string       `xml:"name,attr"`
+	Args        []Arg        `xml:"arg"`
+	Annotations []Annotation `xml:"annotation"`
+}
+
+// Property is a property of a D-Bus interface.
+type Property struct {
+	Name        string       `xml:"name,attr"`
+	Type        string       `xml:"type,attr"`
+	Access      string       `xml:"access,attr"`
+	Annotations []Annotation `xml:"annotation"`
+}
+
+// Arg is an argument of a method or signal.
+type Arg struct {
+	Name        string       `xml:"name,attr,omitempty"`
+	Type        string       `xml:"type,attr"`
+	Direction   string       `xml:"direction,attr,omitempty"`
+	Annotations []Annotation `xml:"annotation"`
+}
+
+// Annotation is an annotation in the introspection XML.
+type Annotation struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// Options are the options for generating bindings.
+type Options struct {
+	// Package is the name of the package of the generated code.
+	Package string
+	// Source is the name of the introspection XML file, noted in the header of the generated code.
+	Source string
+	// Interfaces, if not empty, limits the bindings to the listed interfaces.
+	Interfaces []string
+}
+
+// Parse parses the given introspection XML.
+func Parse(r io.Reader) (*Node, error) {
+	node := &Node{}
+	if err := xml.NewDecoder(r).Decode(node); err != nil {
+		return nil, fmt.Errorf("parse introspection: %w", err)
+	}
+
+	return node, nil
+}
+
+// Generate generates the (gofmt-ed) Go bindings for the interfaces of the given node and its children.
+func Generate(node *Node, options Options) ([]byte, error) {
+	gen := &generator{
+		structs: make(map[string]*structType),
+		names:   make(map[string]string),
+	}
+
+	for _, intr := range collectInterfaces(node) {
+		if len(options.Interfaces) > 0 && !slices.Contains(options.Interfaces, intr.Name) {
+			continue
+		}
+
+		if err := gen.generateInterface(&intr); err != nil {
+			return nil, fmt.Errorf("%s: %w", intr.Name, err)
+		}
+	}
+
+	if gen.body.Len() == 0 {
+		return nil, ErrNoInterfaces
+	}
+
+	gen.generateStructs()
+
+	var out bytes.Buffer
+
+	fmt.Fprintf(&out, "// Code generated by dbusxgen from %s. DO NOT EDIT.\n\n", options.Source)
+	fmt.Fprintf(&out, "package %s\n\n", options.Package)
+	out.WriteString("import (\n")
+
+	if gen.usesContext {
+		out.WriteString("\"context\"\n\n")
+	}
+
+	if gen.usesDBus {
+		out.WriteString("\"github.com/godbus/dbus/v5\"\n\n")
+	}
+
+	out.WriteString("\"github.com/joshuar/go-hass-agent/pkg/linux/dbusx\"\n)\n")
+	out.Write(gen.body.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w", err)
+	}
+
+	return src, nil
+}
+
+// collectInterfaces returns the interfaces of the given node and its children, skipping any duplicates.
+func collectInterfaces(node *Node) []Interface {
+	var intrs []Interface
+
+	for _, intr := range node.Interfaces {
+		if !slices.ContainsFunc(intrs, func(e Interface) bool { return e.Name == intr.Name }) {
+			intrs = append(intrs, intr)
+		}
+	}
+
+	for idx := range node.Children {
+		for _, intr := range collectInterfaces(&node.Children[idx]) {
+			if !slices.ContainsFunc(intrs, func(e Interface) bool { return e.Name == intr.Name }) {
+				intrs = append(intrs, intr)
+			}
+		}
+	}
+
+	return intrs
+}
+
+// structType is a Go struct type generated for a D-Bus struct type.
+type structType struct {
+	signature string
+	fields    []field
+}
+
+// field is a field of a generated Go struct type.
+type field struct {
+	name string
+	typ  string
+}
+
+type generator struct {
+	body        bytes.Buffer
+	structs     map[string]*structType
+	names       map[string]string // generated top-level names and what they were generated for.
+	usesContext bool
+	usesDBus    bool
+}
+
+// declare records the given top-level name, returning an error if it is already used.
+func (g *generator) declare(name, what string) error {
+	if prev, found := g.names[name]; found {
+		return fmt.Errorf("%w: %s is generated for both %s and %s", ErrNameConflict, name, prev, what)
+	}
+
+	g.names[name] = what
+
+	return nil
+}
+
+//nolint:funlen
+func (g *generator) generateInterface(intr *Interface) error {
+	client := annotation(intr.Annotations, NameAnnotation)
+	if client == "" {
+		client = camel(intr.Name[strings.LastIndex(intr.Name, ".")+1:])
+	}
+
+	intrConst := client + "Interface"
+
+	for _, name := range []string{client, intrConst, "New" + client} {
+		if err := g.declare(name, intr.Name); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(&g.body, "\n// %s is the name of the %s D-Bus interface.\n", intrConst, intr.Name)
+	fmt.Fprintf(&g.body, "const %s = %q\n", intrConst, intr.Name)
+	fmt.Fprintf(&g.body, "\n// %s is a client for the %s D-Bus interface.\n", client, intr.Name)
+	fmt.Fprintf(&g.body, "type %s struct {\nbus *dbusx.Bus\ndest string\npath string\n}\n", client)
+	fmt.Fprintf(&g.body, "\n// New%s creates a client for the %s D-Bus interface of the object at the given path of the "+
+		"given destination.\n", client, intr.Name)
+	fmt.Fprintf(&g.body, "func New%s(bus *dbusx.Bus, dest, path string) *%s {\n", client, client)
+	fmt.Fprintf(&g.body, "return &%s{bus: bus, dest: dest, path: path}\n}\n", client)
+
+	// Track the names of the client methods to catch conflicts.
+	members := make(map[string]string)
+	declareMember := func(name, what string) error {
+		if prev, found := members[name]; found {
+			return fmt.Errorf("%w: %s.%s is generated for both %s and %s", ErrNameConflict, client, name, prev, what)
+		}
+
+		members[name] = what
+
+		return nil
+	}
+
+	for idx := range intr.Methods {
+		method := &intr.Methods[idx]
+
+		name := memberName(method.Name, method.Annotations)
+		if err := declareMember(name, "method "+method.Name); err != nil {
+			return err
+		}
+
+		if err := g.generateMethod(client, intrConst, name, method); err != nil {
+			return fmt.Errorf("method %s: %w", method.Name, err)
+		}
+	}
+
+	for idx := range intr.Properties {
+		prop := &intr.Properties[idx]
+
+		name := memberName(prop.Name, prop.Annotations)
+		if prop.Access != "write" {
+			if err := declareMember(name, "property "+prop.Name); err != nil {
+				return err
+			}
+		}
+
+		if prop.Access == "write" || prop.Access == "readwrite" {
+			if err := declareMember("Set"+name, "property "+prop.Name); err != nil {
+				return err
+			}
+		}
+
+		if err := g.generateProperty(client, intrConst, name, prop); err != nil {
+			return fmt.Errorf("property %s: %w", prop.Name, err)
+		}
+	}
+
+	for idx := range intr.Signals {
+		signal := &intr.Signals[idx]
+
+		name := memberName(signal.Name, signal.Annotations)
+		if err := declareMember("Watch"+name, "signal "+signal.Name); err != nil {
+			return err
+		}
+
+		if err := g.generateSignal(client, intrConst, name, intr.Name, signal); err != nil {
+			return fmt.Errorf("signal %s: %w", signal.Name, err)
+		}
+	}
+
+	return nil
+}
+
+//nolint:funlen
+func (g *generator) generateMethod(client, intrConst, name string, method *Method) error {
+	g.usesContext = true
+
+	var (
+		params, results, args, stores []string
+		used                          = slices.Clone(reserved)
+	)
+
+	for idx := range method.Args {
+		arg := &method.Args[idx]
+
+		typ, err := g.argType(arg, client+name+camel(arg.Name))
+		if err != nil {
+			return err
+		}
+
+		argName := localName(arg.Name, idx, used)
+		used = append(used, argName)
+
+		if arg.Direction == "out" {
+			results = append(results, argName+" "+typ)
+			stores = append(stores, "&"+argName)
+		} else {
+			params = append(params, argName+" "+typ)
+			args = append(args, argName)
+		}
+	}
+
+	fmt.Fprintf(&g.body, "\n// %s calls the %s method of the D-Bus interface.\n", name, method.Name)
+	fmt.Fprintf(&g.body, "func (c *%s) %s(%s) ", client, name, strings.Join(append([]string{"ctx context.Context"}, params...), ", "))
+
+	call := fmt.Sprintf("dbusx.CallMethod(ctx, c.bus, c.dest, c.path, %s+%q, []any{%s}",
+		intrConst, "."+method.Name, strings.Join(args, ", "))
+	if len(stores) > 0 {
+		call += ", " + strings.Join(stores, ", ")
+	}
+
+	call += ")"
+
+	if len(results) == 0 {
+		fmt.Fprintf(&g.body, "error {\nreturn %s\n}\n", call)
+
+		return nil
+	}
+
+	resultNames := make([]string, 0, len(stores)+1)
+	for _, store := range stores {
+		resultNames = append(resultNames, strings.TrimPrefix(store, "&"))
+	}
+
+	resultNames = append(resultNames, "err")
+
+	fmt.Fprintf(&g.body, "(%s, err error) {\n", strings.Join(results, ", "))
+	fmt.Fprintf(&g.body, "err = %s\n\nreturn %s\n}\n", call, strings.Join(resultNames, ", "))
+
+	return nil
+}
+
+func (g *generator) generateProperty(client, intrConst, name string, prop *Property) error {
+	typ, err := g.goType(prop.Type, typeName(prop.Annotations, client+name), fieldNames(prop.Annotations))
+	if err != nil {
+		return err
+	}
+
+	if prop.Access != "write" {
+		fmt.Fprintf(&g.body, "\n// %s returns the value of the %s property of the D-Bus interface.\n", name, prop.Name)
+		fmt.Fprintf(&g.body, "func (c *%s) %s() (%s, error) {\n", client, name, typ)
+		fmt.Fprintf(&g.body, "return dbusx.NewProperty[%s](c.bus, c.path, c.dest, %s+%q).Get()\n}\n",
+			typ, intrConst, "."+prop.Name)
+	}
+
+	if prop.Access == "write" || prop.Access == "readwrite" {
+		fmt.Fprintf(&g.body, "\n// Set%s sets the value of the %s property of the D-Bus interface.\n", name, prop.Name)
+		fmt.Fprintf(&g.body, "func (c *%s) Set%s(value %s) error {\n", client, name, typ)
+		fmt.Fprintf(&g.body, "return dbusx.NewProperty[%s](c.bus, c.path, c.dest, %s+%q).Set(value)\n}\n",
+			typ, intrConst, "."+prop.Name)
+	}
+
+	return nil
+}
+
+func (g *generator) generateSignal(client, intrConst, name, intrName string, signal *Signal) error {
+	g.usesContext = true
+
+	signalType := client + name + "Signal"
+	if err := g.declare(signalType, "signal "+signal.Name); err != nil {
+		return err
+	}
+
+	fields := make([]field, 0, len(signal.Args))
+	used := make([]string, 0, len(signal.Args))
+
+	for idx := range signal.Args {
+		arg := &signal.Args[idx]
+
+		typ, err := g.argType(arg, client+name+camel(arg.Name))
+		if err != nil {
+			return err
+		}
+
+		fieldName := exportedName(arg.Name, "Arg", idx, used)
+		used = append(used, fieldName)
+		fields = append(fields, field{name: fieldName, typ: typ})
+	}
+
+	fmt.Fprintf(&g.body, "\n// %s contains the arguments of the %s signal of the %s D-Bus interface.\n",
+		signalType, signal.Name, intrName)
+	fmt.Fprintf(&g.body, "type %s struct {\n", signalType)
+
+	for _, f := range fields {
+		fmt.Fprintf(&g.body, "%s %s\n", f.name, f.typ)
+	}
+
+	g.body.WriteString("}\n")
+
+	fmt.Fprintf(&g.body, "\n// Watch%s watches for the %s signal of the D-Bus interface. The arguments of each signal "+
+		"are sent on the returned channel until the context is canceled.\n", name, signal.Name)
+	fmt.Fprintf(&g.body, "func (c *%s) Watch%s(ctx context.Context) (<-chan %s, error) {\n", client, name, signalType)
+	fmt.Fprintf(&g.body, "return dbusx.WatchSignal[%s](ctx, c.bus, c.path, %s, %q)\n}\n",
+		signalType, intrConst, signal.Name)
+
+	return nil
+}
+
+// generateStructs generates the struct types for the D-Bus struct types in the bindings.
+func (g *generator) generateStructs() {
+	names := make([]string, 0, len(g.structs))
+	for name := range g.structs {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		st := g.structs[name]
+
+		fmt.Fprintf(&g.body, "\n// %s is the D-Bus struct type %s.\n", name, st.signature)
+		fmt.Fprintf(&g.body, "type %s struct {\n", name)
+
+		for _, f := range st.fields {
+			fmt.Fprintf(&g.body, "%s %s\n", f.name, f.typ)
+		}
+
+		g.body.WriteString("}\n")
+	}
+}
+
+// argType returns the Go type of the given method or signal argument. Any struct type is named with the given
+// default name, unless the argument is annotated with a name.
+func (g *generator) argType(arg *Arg, defaultName string) (string, error) {
+	return g.goType(arg.Type, typeName(arg.Annotations, defaultName), fieldNames(arg.Annotations))
+}
+
+// goType returns the Go type for the given single complete D-Bus type signature. A struct type in the signature is
+// generated with the given name and field names.
+func (g *generator) goType(signature, name string, fields []string) (string, error) {
+	typ, rest, err := g.parseType(signature, name, fields)
+	if err != nil {
+		return "", fmt.Errorf("%w %q: %w", ErrInvalidSignature, signature, err)
+	}
+
+	if rest != "" {
+		return "", fmt.Errorf("%w %q: not a single complete type", ErrInvalidSignature, signature)
+	}
+
+	return typ, nil
+}
+
+// parseType parses the first complete type of the given signature, returning its Go type and the rest of the
+// signature.
+//
+//nolint:cyclop,funlen
+func (g *generator) parseType(signature, name string, fields []string) (string, string, error) {
+	if signature == "" {
+		return "", "", errors.New("missing type")
+	}
+
+	switch signature[0] {
+	case 'a':
+		if strings.HasPrefix(signature, "a{") {
+			if _, basic := basicTypes[signature[min(2, len(signature)-1)]]; !basic || signature[2] == 'v' {
+				return "", "", errors.New("dict key is not a basic type")
+			}
+
+			key, rest, err := g.parseType(signature[2:], name, nil)
+			if err != nil {
+				return "", "", err
+			}
+
+			value, rest, err := g.parseType(rest, name, fields)
+			if err != nil {
+				return "", "", err
+			}
+
+			if !strings.HasPrefix(rest, "}") {
+				return "", "", errors.New("unterminated dict entry")
+			}
+
+			return "map[" + key + "]" + value, rest[1:], nil
+		}
+
+		elem, rest, err := g.parseType(signature[1:], name, fields)
+		if err != nil {
+			return "", "", err
+		}
+
+		return "[]" + elem, rest, nil
+	case '(':
+		var st structType
+
+		rest := signature[1:]
+		for !strings.HasPrefix(rest, ")") {
+			if rest == "" {
+				return "", "", errors.New("unterminated struct")
+			}
+
+			idx := len(st.fields)
+
+			fieldName := nthOrEmpty(fields, idx)
+			if !token.IsExported(fieldName) || !token.IsIdentifier(fieldName) {
+				fieldName = exportedName(fieldName, "Field", idx, nil)
+			}
+
+			typ, remaining, err := g.parseType(rest, name+fieldName, nil)
+			if err != nil {
+				return "", "", err
+			}
+
+			st.fields = append(st.fields, field{name: fieldName, typ: typ})
+			rest = remaining
+		}
+
+		if len(st.fields) == 0 {
+			return "", "", errors.New("empty struct")
+		}
+
+		st.signature = signature[:len(signature)-len(rest)+1]
+
+		if err := g.addStruct(name, &st); err != nil {
+			return "", "", err
+		}
+
+		return name, rest[1:], nil
+	default:
+		typ, found := basicTypes[signature[0]]
+		if !found {
+			return "", "", fmt.Errorf("unknown type code %q", signature[0])
+		}
+
+		if strings.HasPrefix(typ, "dbus.") {
+			g.usesDBus = true
+		}
+
+		return typ, signature[1:], nil
+	}
+}
+
+// addStruct adds the given struct type to the generated types. A struct type can be used more than once (e.g., by
+// a method argument and a property) as long as its definition is the same.
+func (g *generator) addStruct(name string, st *structType) error {
+	if existing, found := g.structs[name]; found {
+		if !slices.Equal(existing.fields, st.fields) {
+			return fmt.Errorf("%w: struct type %s has conflicting definitions %s and %s",
+				ErrNameConflict, name, existing.signature, st.signature)
+		}
+
+		return nil
+	}
+
+	if err := g.declare(name, "struct type "+st.signature); err != nil {
+		return err
+	}
+
+	g.structs[name] = st
+
+	return nil
+}
+
+// initialisms are the words that are written in upper case in generated Go names.
+var initialisms = []string{"Id", "Uid", "Gid", "Pid", "Uuid", "Url", "Uri", "Ip", "Dns", "Cpu", "Usb", "Ui"}
+
+// camel converts the given D-Bus name into a Go name, keeping common initialisms in upper case (e.g., session_id
+// becomes SessionID).
+func camel(name string) string {
+	goName := name
+	if strings.ContainsAny(name, "_-. ") {
+		goName = strcase.ToCamel(name)
+	} else if name != "" {
+		goName = strings.ToUpper(name[:1]) + name[1:]
+	}
+
+	var words []string
+
+	start := 0
+	for idx := 1; idx <= len(goName); idx++ {
+		if idx == len(goName) || (goName[idx] >= 'A' && goName[idx] <= 'Z') {
+			words = append(words, goName[start:idx])
+			start = idx
+		}
+	}
+
+	for idx, word := range words {
+		if slices.Contains(initialisms, word) {
+			words[idx] = strings.ToUpper(word)
+		}
+	}
+
+	return strings.Join(words, "")
+}
+
+// annotation returns the value of the named annotation, or an empty string if it is not present.
+func annotation(annotations []Annotation, name string) string {
+	for _, a := range annotations {
+		if a.Name == name {
+			return a.Value
+		}
+	}
+
+	return ""
+}
+
+// memberName returns the Go name of a method, property or signal.
+func memberName(name string, annotations []Annotation) string {
+	if goName := annotation(annotations, NameAnnotation); goName != "" {
+		return goName
+	}
+
+	return camel(name)
+}
+
+// typeName returns the name of a generated struct type.
+func typeName(annotations []Annotation, defaultName string) string {
+	if name := annotation(annotations, TypeAnnotation); name != "" {
+		return name
+	}
+
+	return defaultName
+}
+
+// fieldNames returns the field names of a generated struct type, if they are set with an annotation.
+func fieldNames(annotations []Annotation) []string {
+	value := annotation(annotations, FieldsAnnotation)
+	if value == "" {
+		return nil
+	}
+
+	names := strings.Split(value, ",")
+	for idx := range names {
+		names[idx] = strings.TrimSpace(names[idx])
+	}
+
+	return names
+}
+
+func nthOrEmpty(values []string, idx int) string {
+	if idx < len(values) {
+		return values[idx]
+	}
+
+	return ""
+}
+
+// exportedName returns an exported Go identifier for the given D-Bus name. If the name is empty or already used, the
+// prefix and index are used instead.
+func exportedName(name, prefix string, idx int, used []string) string {
+	goName := camel(name)
+	if !token.IsIdentifier(goName) || slices.Contains(used, goName) {
+		return prefix + strconv.Itoa(idx)
+	}
+
+	return goName
+}
+
+// localName returns an unexported Go identifier for the given D-Bus argument name, which does not conflict with Go
+// keywords or any of the given used names.
+func localName(name string, idx int, used []string) string {
+	goName := strcase.ToLowerCamel(name)
+
+	switch {
+	case token.IsKeyword(goName), slices.Contains(used, goName):
+		goName += "Arg"
+	case !token.IsIdentifier(goName):
+		goName = "arg" + strconv.Itoa(idx)
+	}
+
+	for slices.Contains(used, goName) {
+		goName += strconv.Itoa(idx)
+	}
+
+	return goName
+}
@@ -0,0 +1,159 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package dbusxgen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testIntrospection = `<!DOCTYPE node PUBLIC "-//freedesktop//DTD D-BUS Object Introspection 1.0//EN"
+"http://www.freedesktop.org/standards/dbus/1.0/introspect.dtd">
+<node>
+  <interface name="org.freedesktop.DBus.Peer">
+    <method name="Ping"/>
+  </interface>
+  <interface name="org.example.Devices">
+    <method name="GetDevices">
+      <arg type="ao" name="devices" direction="out"/>
+    </method>
+    <method name="Inhibit">
+      <arg type="s" name="what" direction="in"/>
+      <arg type="s" name="type" direction="in"/>
+      <arg type="h" name="fd" direction="out"/>
+    </method>
+    <method name="ListSeats">
+      <arg type="a(so)" name="seats" direction="out">
+        <annotation name="dbusx.Fields" value="ID,Path"/>
+      </arg>
+      <arg type="a{sv}" name="options" direction="out"/>
+    </method>
+    <property name="Percentage" type="d" access="read"/>
+    <property name="Brightness" type="u" access="readwrite">
+      <annotation name="dbusx.Name" value="Level"/>
+    </property>
+    <signal name="DeviceAdded">
+      <arg type="o" name="device_id"/>
+      <arg type="(ib)"/>
+    </signal>
+  </interface>
+  <node name="child">
+    <interface name="org.example.Devices"/>
+  </node>
+</node>`
+
+func TestGenerate(t *testing.T) {
+	node, err := Parse(strings.NewReader(testIntrospection))
+	require.NoError(t, err)
+
+	got, err := Generate(node, Options{
+		Package:    "example",
+		Source:     "devices.xml",
+		Interfaces: []string{"org.example.Devices"},
+	})
+	require.NoError(t, err)
+
+	src := string(got)
+	for _, want := range []string{
+		"// Code generated by dbusxgen from devices.xml. DO NOT EDIT.",
+		"package example",
+		`"github.com/godbus/dbus/v5"`,
+		`const DevicesInterface = "org.example.Devices"`,
+		"func NewDevices(bus *dbusx.Bus, dest, path string) *Devices {",
+		"func (c *Devices) GetDevices(ctx context.Context) (devices []dbus.ObjectPath, err error) {",
+		`err = dbusx.CallMethod(ctx, c.bus, c.dest, c.path, DevicesInterface+".GetDevices", []any{}, &devices)`,
+		"func (c *Devices) Inhibit(ctx context.Context, what string, typeArg string) (fd dbus.UnixFD, err error) {",
+		"[]any{what, typeArg}, &fd)",
+		"func (c *Devices) ListSeats(ctx context.Context) (seats []DevicesListSeatsSeats, options map[string]dbus.Variant, err error) {",
+		"type DevicesListSeatsSeats struct {\n\tID   string\n\tPath dbus.ObjectPath\n}",
+		"func (c *Devices) Percentage() (float64, error) {",
+		"func (c *Devices) Level() (uint32, error) {",
+		`return dbusx.NewProperty[uint32](c.bus, c.path, c.dest, DevicesInterface+".Brightness").Set(value)`,
+		"type DevicesDeviceAddedSignal struct {\n\tDeviceID dbus.ObjectPath\n\tArg1     DevicesDeviceAdded\n}",
+		"type DevicesDeviceAdded struct {\n\tField0 int32\n\tField1 bool\n}",
+		`return dbusx.WatchSignal[DevicesDeviceAddedSignal](ctx, c.bus, c.path, DevicesInterface, "DeviceAdded")`,
+	} {
+		assert.Contains(t, src, want)
+	}
+
+	assert.NotContains(t, src, "Peer")
+}
+
+func TestGenerate_errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		xml     string
+		wantErr error
+	}{
+		{
+			name:    "no interfaces",
+			xml:     `<node></node>`,
+			wantErr: ErrNoInterfaces,
+		},
+		{
+			name: "invalid signature",
+			xml: `<node><interface name="org.example.A">
+				<property name="P" type="a{(s)s}" access="read"/>
+			</interface></node>`,
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name: "unterminated struct",
+			xml: `<node><interface name="org.example.A">
+				<property name="P" type="(su" access="read"/>
+			</interface></node>`,
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name: "more than one type",
+			xml: `<node><interface name="org.example.A">
+				<property name="P" type="su" access="read"/>
+			</interface></node>`,
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name: "method and setter conflict",
+			xml: `<node><interface name="org.example.A">
+				<method name="SetWallMessage"><arg type="s" direction="in"/></method>
+				<property name="WallMessage" type="s" access="readwrite"/>
+			</interface></node>`,
+			wantErr: ErrNameConflict,
+		},
+		{
+			name: "interface name conflict",
+			xml: `<node>
+				<interface name="org.example.one.Manager"/>
+				<interface name="org.example.two.Manager"/>
+			</node>`,
+			wantErr: ErrNameConflict,
+		},
+		{
+			name: "struct type conflict",
+			xml: `<node><interface name="org.example.A">
+				<property name="P" type="(su)" access="read"><annotation name="dbusx.Type" value="Pair"/></property>
+				<property name="Q" type="(ss)" access="read"><annotation name="dbusx.Type" value="Pair"/></property>
+			</interface></node>`,
+			wantErr: ErrNameConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := Parse(strings.NewReader(tt.xml))
+			require.NoError(t, err)
+
+			_, err = Generate(node, Options{Package: "example"})
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func Test_camel(t *testing.T) {
+	assert.Equal(t, "SessionID", camel("session_id"))
+	assert.Equal(t, "IdleHint", camel("IdleHint"))
+	assert.Equal(t, "UPower", camel("UPower"))
+	assert.Equal(t, "ActiveUUID", camel("active_uuid"))
+}
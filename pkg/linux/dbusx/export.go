@@ -0,0 +1,81 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package dbusx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+)
+
+// IntrospectInterface is the canonical introspection interface.
+const IntrospectInterface = "org.freedesktop.DBus.Introspectable"
+
+// ErrNameTaken is returned when a bus name is already owned by another connection.
+var ErrNameTaken = errors.New("name already taken")
+
+// RequestName requests ownership of the given well-known name on the bus. The name is released when the context is
+// canceled. If the name is already owned by another connection, ErrNameTaken is returned.
+func (b *Bus) RequestName(ctx context.Context, name string) error {
+	reply, err := b.conn.RequestName(name, dbus.NameFlagDoNotQueue)
+	if err != nil {
+		return fmt.Errorf("%s: unable to request name %s: %w", b.busType.String(), name, err)
+	}
+
+	if reply != dbus.RequestNameReplyPrimaryOwner {
+		return fmt.Errorf("%s: %s: %w", b.busType.String(), name, ErrNameTaken)
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		if _, err := b.conn.ReleaseName(name); err != nil {
+			b.traceLog("Unable to release name.", slog.String("name", name), slog.Any("error", err))
+		}
+	}()
+
+	return nil
+}
+
+// ExportObject exports an object at the given path. The exported methods of each of the given values are exported as
+// the methods of the interface it is keyed by; each method must return a *dbus.Error as its last value. The given
+// introspection XML is also exported, so that the object can be introspected by tools such as busctl and gdbus. The
+// object is unexported when the context is canceled.
+func (b *Bus) ExportObject(ctx context.Context, path, introspection string, interfaces map[string]any) error {
+	interfaces = maps.Clone(interfaces)
+	interfaces[IntrospectInterface] = introspect.Introspectable(introspection)
+
+	var exported []string
+
+	unexport := func() {
+		for _, intr := range exported {
+			if err := b.conn.Export(nil, dbus.ObjectPath(path), intr); err != nil {
+				b.traceLog("Unable to unexport interface.", slog.String("interface", intr), slog.Any("error", err))
+			}
+		}
+	}
+
+	for _, intr := range slices.Sorted(maps.Keys(interfaces)) {
+		if err := b.conn.Export(interfaces[intr], dbus.ObjectPath(path), intr); err != nil {
+			unexport()
+
+			return fmt.Errorf("%s: unable to export %s on %s: %w", b.busType.String(), intr, path, err)
+		}
+
+		exported = append(exported, intr)
+	}
+
+	go func() {
+		<-ctx.Done()
+		unexport()
+	}()
+
+	return nil
+}
@@ -12,17 +12,28 @@ import (
 	"github.com/godbus/dbus/v5/introspect"
 )
 
-// ErrMethodNotFound is returned when the requested method cannot be executed.
-var ErrMethodNotFound = errors.New("method not found")
+var (
+	// ErrMethodNotFound is returned when the requested method cannot be executed.
+	ErrMethodNotFound = errors.New("method not found")
+	// ErrPropertyNotFound is returned when the requested property does not exist.
+	ErrPropertyNotFound = errors.New("property not found")
+)
 
 // Introspection represents a D-Bus introspection request.
 type Introspection introspect.Node
 
-// GetMethod returns details about the given method (if it exists), or a non-nil error if it cannot be found.
+// GetMethod returns details about the given method (if it exists), or a non-nil error if it cannot be found. If the
+// method name includes the interface (e.g., org.freedesktop.Notifications.Notify), only that interface is searched.
 func (i Introspection) GetMethod(name string) (*Method, error) {
+	intrName, member := splitMember(name)
+
 	for _, intr := range i.Interfaces {
+		if intrName != "" && intr.Name != intrName {
+			continue
+		}
+
 		found := slices.IndexFunc(intr.Methods, func(e introspect.Method) bool {
-			return strings.HasSuffix(name, e.Name)
+			return e.Name == member
 		})
 
 		if found != -1 {
@@ -38,6 +49,36 @@ func (i Introspection) GetMethod(name string) (*Method, error) {
 	return nil, ErrMethodNotFound
 }
 
+// GetProperty returns details about the given property of the given interface (if it exists), or a non-nil error if it
+// cannot be found.
+func (i Introspection) GetProperty(intrName, name string) (*introspect.Property, error) {
+	for _, intr := range i.Interfaces {
+		if intr.Name != intrName {
+			continue
+		}
+
+		found := slices.IndexFunc(intr.Properties, func(e introspect.Property) bool {
+			return e.Name == name
+		})
+
+		if found != -1 {
+			return &intr.Properties[found], nil
+		}
+	}
+
+	return nil, ErrPropertyNotFound
+}
+
+// splitMember splits the given (possibly interface-qualified) member name into the interface and member name.
+func splitMember(name string) (string, string) {
+	idx := strings.LastIndex(name, ".")
+	if idx == -1 {
+		return "", name
+	}
+
+	return name[:idx], name[idx+1:]
+}
+
 // NewIntrospection starts a new introspection request.
 func NewIntrospection(bus *Bus, intr, path string) (*Introspection, error) {
 	obj := bus.getObject(intr, path)
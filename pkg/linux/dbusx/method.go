@@ -42,6 +42,45 @@ func (m *Method) Call(ctx context.Context, args ...any) error {
 	return nil
 }
 
+// Invoke calls the method and returns the values of its reply. The given arguments are first validated against the
+// introspected signature of the method and converted to the D-Bus types of its arguments (see CoerceArgs). If the
+// method cannot be introspected, the arguments are invalid or the call fails, a non-nil error is returned.
+func (m *Method) Invoke(ctx context.Context, args ...any) ([]any, error) {
+	if err := valid(m); err != nil {
+		return nil, fmt.Errorf("invalid method: %w", err)
+	}
+
+	introspection, err := NewIntrospection(m.bus, m.intr, m.path)
+	if err != nil {
+		return nil, fmt.Errorf("could not introspect: %w", err)
+	}
+
+	method, err := introspection.GetMethod(m.name)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", m.name, err)
+	}
+
+	methodArgs, err := method.IntrospectArgs()
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve method arguments: %w", err)
+	}
+
+	coerced, err := CoerceArgs(args, methodArgs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", m.name, err)
+	}
+
+	called := m.bus.getObject(m.intr, m.path).CallWithContext(ctx, m.name, 0, coerced...)
+	if called.Err != nil {
+		return nil, fmt.Errorf("%s: unable to call method %s: %w",
+			m.bus.busType.String(),
+			m.name,
+			called.Err)
+	}
+
+	return called.Body, nil
+}
+
 func (m *Method) IntrospectArgs() ([]introspect.Arg, error) {
 	if m.obj == nil {
 		return nil, ErrIntrospectionNotAvail
@@ -24,6 +24,7 @@ type Trigger struct {
 type Watch struct {
 	path          dbus.ObjectPath
 	pathNamespace string
+	sender        string
 	matches       []dbus.MatchOption
 	methods       []string
 	// handler       WatchHandler
@@ -58,6 +59,18 @@ func MatchPathNamespace(path string) WatchOption {
 	}
 }
 
+// MatchSender option matches messages sent by a particular connection. Signals
+// are sent with the unique name of the connection (e.g. ':1.42'), so a
+// well-known name should be resolved to its owner with GetNameOwner first.
+//
+// https://dbus.freedesktop.org/doc/dbus-specification.html#message-bus-routing-match-rules
+func MatchSender(sender string) WatchOption {
+	return func(a *Watch) {
+		a.sender = sender
+		a.matches = append(a.matches, dbus.WithMatchSender(sender))
+	}
+}
+
 // MatchInterface option match messages sent over or to a particular interface. An
 // example of an interface match is interface='org.freedesktop.Hal.Manager'. For
 // valid interfaces, see:
@@ -215,6 +228,17 @@ func (w *Watch) Start(ctx context.Context, bus *Bus) (<-chan Trigger, error) {
 						continue
 					}
 				}
+				// If a sender match was specified and the signal was sent by
+				// another connection, ignore.
+				if w.sender != "" {
+					if signal.Sender != w.sender {
+						bus.traceLog("Ignoring mismatched sender.",
+							slog.Any("signal", signal.Sender),
+							slog.Any("match", w.sender))
+
+						continue
+					}
+				}
 				// We have a match! Send the signal details back to the client
 				// for further processing.
 				bus.traceLog("Dispatching D-Bus trigger.", slog.Any("signal", signal))
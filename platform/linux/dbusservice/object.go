@@ -0,0 +1,210 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package dbusservice
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+
+	"github.com/joshuar/go-hass-agent/agent/workers"
+	"github.com/joshuar/go-hass-agent/hass/notifications"
+	"github.com/joshuar/go-hass-agent/models"
+	"github.com/joshuar/go-hass-agent/pkg/linux/dbusx"
+	"github.com/joshuar/go-hass-agent/platform/linux"
+	"github.com/joshuar/go-hass-agent/scheduler"
+)
+
+// errInvalidArgs is the D-Bus error name for invalid method arguments.
+const errInvalidArgs = "org.freedesktop.DBus.Error.InvalidArgs"
+
+var (
+	// ErrInvalidSensor indicates a sensor pushed through the service is invalid.
+	ErrInvalidSensor = errors.New("invalid sensor")
+	// ErrInvalidEvent indicates an event fired through the service is invalid.
+	ErrInvalidEvent = errors.New("invalid event")
+)
+
+// object is the object exported on the session bus. Each of its exported methods is a method of the service
+// interface (see service.xml).
+type object struct {
+	service *Service
+}
+
+// listedSensor is a sensor returned by ListSensors.
+type listedSensor struct {
+	ID    string
+	Name  string
+	State dbus.Variant
+	Units string
+}
+
+// PushSensor sends a sensor with the given id and state. The options are the fields of a script sensor.
+func (o *object) PushSensor(id string, state dbus.Variant, options map[string]dbus.Variant) *dbus.Error {
+	entity, err := o.service.newSensor(id, state, options)
+	if err != nil {
+		return dbus.NewError(errInvalidArgs, []any{err.Error()})
+	}
+
+	if err := o.service.send(entity); err != nil {
+		return dbus.MakeFailedError(err)
+	}
+
+	return nil
+}
+
+// FireEvent fires an event with the given type and data.
+func (o *object) FireEvent(eventType string, data map[string]dbus.Variant) *dbus.Error {
+	entity, err := newEvent(eventType, data)
+	if err != nil {
+		return dbus.NewError(errInvalidArgs, []any{err.Error()})
+	}
+
+	if err := o.service.send(entity); err != nil {
+		return dbus.MakeFailedError(err)
+	}
+
+	return nil
+}
+
+// ListSensors returns the current state of each sensor.
+func (o *object) ListSensors() ([]listedSensor, *dbus.Error) {
+	sensors := o.service.currentSensors()
+
+	listed := make([]listedSensor, 0, len(sensors))
+	for _, sensor := range sensors {
+		listed = append(listed, listedSensor{
+			ID:    sensor.UniqueID,
+			Name:  sensor.Name,
+			State: stateVariant(sensor.State),
+			Units: sensor.UnitOfMeasurement,
+		})
+	}
+
+	return listed, nil
+}
+
+// ListJobs returns the ids of the scheduled polling jobs.
+func (o *object) ListJobs() ([]string, *dbus.Error) {
+	ids, err := scheduler.JobIDs()
+	if err != nil {
+		return nil, dbus.MakeFailedError(err)
+	}
+
+	return ids, nil
+}
+
+// RunJob runs the polling job with the given id now.
+func (o *object) RunJob(id string) *dbus.Error {
+	if err := scheduler.RunJob(id); err != nil {
+		if errors.Is(err, scheduler.ErrJobNotFound) {
+			return dbus.NewError(errInvalidArgs, []any{err.Error()})
+		}
+
+		return dbus.MakeFailedError(err)
+	}
+
+	return nil
+}
+
+// Notify displays a notification with the given title, message and data, returning the id of the notification.
+func (o *object) Notify(title, message string, data map[string]dbus.Variant) (string, *dbus.Error) {
+	record, err := notifications.NewRecord(title, message, plainMap(data))
+	if err != nil {
+		return "", dbus.MakeFailedError(err)
+	}
+
+	o.service.router.Route(o.service.ctx, record)
+
+	return record.ID, nil
+}
+
+// newSensor creates a sensor entity from the arguments of PushSensor.
+func (s *Service) newSensor(id string, state dbus.Variant, options map[string]dbus.Variant) (models.Entity, error) {
+	if id == "" {
+		return models.Entity{}, fmt.Errorf("%w: sensor id is required", ErrInvalidSensor)
+	}
+
+	fields := plainMap(options)
+	fields["sensor_id"] = id
+	fields["sensor_state"] = dbusx.PlainValue(state)
+
+	if _, found := fields["sensor_name"]; !found {
+		fields["sensor_name"] = id
+	}
+
+	attributes, _ := fields["sensor_attributes"].(map[string]any) //nolint:errcheck // invalid attributes are replaced.
+	if attributes == nil {
+		attributes = make(map[string]any)
+	}
+
+	attributes["data_source"] = linux.DataSrcDBus
+	fields["sensor_attributes"] = attributes
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return models.Entity{}, fmt.Errorf("%w: %w", ErrInvalidSensor, err)
+	}
+
+	var sensor workers.ScriptSensor
+	if err := json.Unmarshal(data, &sensor); err != nil {
+		return models.Entity{}, fmt.Errorf("%w: %w", ErrInvalidSensor, err)
+	}
+
+	entity := sensor.Entity(s.ctx)
+
+	details, err := entity.AsSensor()
+	if err != nil {
+		return models.Entity{}, fmt.Errorf("%w: %w", ErrInvalidSensor, err)
+	}
+
+	if _, err := details.AsRegistration(); err != nil {
+		return models.Entity{}, fmt.Errorf("%w: %w", ErrInvalidSensor, err)
+	}
+
+	return entity, nil
+}
+
+// newEvent creates an event entity from the arguments of FireEvent.
+func newEvent(eventType string, data map[string]dbus.Variant) (models.Entity, error) {
+	entity, err := models.NewEvent(eventType, plainMap(data))
+	if err != nil {
+		return entity, fmt.Errorf("%w: %w", ErrInvalidEvent, err)
+	}
+
+	if event, err := entity.AsEvent(); err != nil || !event.Valid() {
+		return entity, fmt.Errorf("%w: event type is required", ErrInvalidEvent)
+	}
+
+	return entity, nil
+}
+
+// plainMap converts the given D-Bus dict into a map of plain values.
+func plainMap(dict map[string]dbus.Variant) map[string]any {
+	values := make(map[string]any, len(dict))
+	for key, value := range dict {
+		values[key] = dbusx.PlainValue(value)
+	}
+
+	return values
+}
+
+// stateVariant returns the given sensor state as a variant. States that are not D-Bus basic types are represented by
+// their string form.
+func stateVariant(state any) dbus.Variant {
+	switch value := state.(type) {
+	case string, bool, int32, int64, uint32, uint64, float32, float64:
+		return dbus.MakeVariant(value)
+	case int:
+		return dbus.MakeVariant(int64(value))
+	case uint:
+		return dbus.MakeVariant(uint64(value))
+	case nil:
+		return dbus.MakeVariant("")
+	default:
+		return dbus.MakeVariant(fmt.Sprint(state))
+	}
+}
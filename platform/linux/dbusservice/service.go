@@ -0,0 +1,180 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+// Package dbusservice exposes the agent as a service on the D-Bus session bus. Local applications can use the service
+// to push sensor values and fire events to Home Assistant, list the current sensor states, run polling jobs and display
+// notifications, e.g., with busctl or gdbus.
+package dbusservice
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+
+	slogctx "github.com/veqryn/slog-context"
+
+	"github.com/joshuar/go-hass-agent/agent/workers"
+	"github.com/joshuar/go-hass-agent/hass/notifications"
+	"github.com/joshuar/go-hass-agent/models"
+	"github.com/joshuar/go-hass-agent/pkg/linux/dbusx"
+	"github.com/joshuar/go-hass-agent/platform/linux"
+)
+
+const (
+	// ServiceName is the well-known name of the agent on the session bus.
+	ServiceName = "io.github.joshuar.GoHassAgent"
+	// ServicePath is the path of the object exported by the agent.
+	ServicePath = "/io/github/joshuar/GoHassAgent"
+	// ServiceInterface is the interface of the object exported by the agent.
+	ServiceInterface = "io.github.joshuar.GoHassAgent"
+
+	// PreferencesID is the location of the service preferences in the preferences file.
+	PreferencesID = "dbus_service"
+)
+
+//go:embed service.xml
+var introspection string
+
+var _ workers.EntityWorker = (*Service)(nil)
+
+// Prefs are the preferences for the D-Bus service.
+type Prefs struct {
+	workers.CommonWorkerPrefs `toml:",squash"`
+}
+
+// Service is the D-Bus service of the agent. It is an entity worker that sends the sensors and events pushed by
+// local applications. It also observes all entities sent by the agent, to track the current sensor states.
+type Service struct {
+	*models.WorkerMetadata
+
+	bus      *dbusx.Bus
+	prefs    *Prefs
+	router   *notifications.Router
+	ctx      context.Context //nolint:containedctx // used by D-Bus method calls.
+	entityCh chan models.Entity
+	sensors  map[string]models.Sensor
+	mu       sync.Mutex
+	// sendMu ensures the entity channel is not closed while a D-Bus method call is sending on it.
+	sendMu sync.RWMutex
+}
+
+// NewService creates the D-Bus service of the agent. Notifications sent through the service are displayed with the
+// given notification router.
+func NewService(ctx context.Context, router *notifications.Router) (*Service, error) {
+	service := &Service{
+		WorkerMetadata: models.SetWorkerMetadata("dbus_service", "D-Bus service"),
+		router:         router,
+		sensors:        make(map[string]models.Sensor),
+	}
+
+	var ok bool
+
+	service.bus, ok = linux.CtxGetSessionBus(ctx)
+	if !ok {
+		return service, fmt.Errorf("get session bus: %w", linux.ErrNoSessionBus)
+	}
+
+	defaultPrefs := &Prefs{}
+	var err error
+	service.prefs, err = workers.LoadWorkerPreferences(PreferencesID, defaultPrefs)
+	if err != nil {
+		return service, fmt.Errorf("load preferences: %w", err)
+	}
+
+	return service, nil
+}
+
+// IsDisabled returns whether the service is disabled.
+func (s *Service) IsDisabled() bool {
+	return s.prefs.IsDisabled()
+}
+
+// Start exports the service object on the session bus and requests the service name. Any sensors and events pushed
+// through the service are sent on the returned channel, until the context is canceled.
+func (s *Service) Start(ctx context.Context) (<-chan models.Entity, error) {
+	s.ctx = ctx
+	s.entityCh = make(chan models.Entity)
+
+	if err := s.bus.ExportObject(ctx, ServicePath, introspection, map[string]any{
+		ServiceInterface: &object{service: s},
+	}); err != nil {
+		return nil, fmt.Errorf("export service: %w", err)
+	}
+
+	if err := s.bus.RequestName(ctx, ServiceName); err != nil {
+		return nil, fmt.Errorf("request service name: %w", err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		s.sendMu.Lock()
+		defer s.sendMu.Unlock()
+		close(s.entityCh)
+	}()
+
+	slogctx.FromCtx(ctx).Debug("Exported agent on session bus.", slog.String("name", ServiceName))
+
+	return s.entityCh, nil
+}
+
+// Observe records the current state of each sensor sent on the given entity channel. All entities (including events
+// and location updates) are passed through, unchanged, to the returned channel.
+func (s *Service) Observe(ctx context.Context, entityCh <-chan models.Entity) <-chan models.Entity {
+	outCh := make(chan models.Entity)
+
+	go func() {
+		defer close(outCh)
+
+		for entity := range entityCh {
+			if sensor, err := entity.AsSensor(); err == nil && entity.IsSensor() {
+				s.mu.Lock()
+				s.sensors[sensor.UniqueID] = sensor
+				s.mu.Unlock()
+			}
+
+			select {
+			case outCh <- entity:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return outCh
+}
+
+// send sends the given entity, unless the service has been stopped.
+func (s *Service) send(entity models.Entity) error {
+	s.sendMu.RLock()
+	defer s.sendMu.RUnlock()
+
+	if err := s.ctx.Err(); err != nil {
+		return fmt.Errorf("send entity: %w", err)
+	}
+
+	select {
+	case s.entityCh <- entity:
+		return nil
+	case <-s.ctx.Done():
+		return fmt.Errorf("send entity: %w", s.ctx.Err())
+	}
+}
+
+// currentSensors returns the current state of each sensor, sorted by id.
+func (s *Service) currentSensors() []models.Sensor {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := slices.Sorted(maps.Keys(s.sensors))
+
+	sensors := make([]models.Sensor, 0, len(ids))
+	for _, id := range ids {
+		sensors = append(sensors, s.sensors[id])
+	}
+
+	return sensors
+}
@@ -0,0 +1,201 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package dbusservice
+
+import (
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshuar/go-hass-agent/hass/notifications"
+	"github.com/joshuar/go-hass-agent/models"
+	"github.com/joshuar/go-hass-agent/platform/linux"
+)
+
+func newTestService(t *testing.T, display notifications.DisplayFunc) *Service {
+	t.Helper()
+
+	return &Service{
+		ctx:      t.Context(),
+		entityCh: make(chan models.Entity, 1),
+		sensors:  make(map[string]models.Sensor),
+		router:   notifications.NewRouter(nil, display),
+	}
+}
+
+func TestService_newSensor(t *testing.T) {
+	service := newTestService(t, nil)
+
+	tests := []struct {
+		name    string
+		id      string
+		state   dbus.Variant
+		options map[string]dbus.Variant
+		want    models.Sensor
+		wantErr bool
+	}{
+		{
+			name:  "with options",
+			id:    "backup_age",
+			state: dbus.MakeVariant(uint32(3)),
+			options: map[string]dbus.Variant{
+				"sensor_name":       dbus.MakeVariant("Backup Age"),
+				"sensor_units":      dbus.MakeVariant("d"),
+				"sensor_icon":       dbus.MakeVariant("mdi:backup-restore"),
+				"sensor_attributes": dbus.MakeVariant(map[string]dbus.Variant{"host": dbus.MakeVariant("nas")}),
+			},
+			want: models.Sensor{
+				UniqueID:          "backup_age",
+				Name:              "Backup Age",
+				State:             float64(3),
+				UnitOfMeasurement: "d",
+				Icon:              "mdi:backup-restore",
+				Type:              models.SensorTypeSensor,
+				Attributes:        models.Attributes{"host": "nas", "data_source": linux.DataSrcDBus},
+			},
+		},
+		{
+			name:    "binary sensor",
+			id:      "vpn_connected",
+			state:   dbus.MakeVariant(true),
+			options: map[string]dbus.Variant{"sensor_type": dbus.MakeVariant("binary")},
+			want: models.Sensor{
+				UniqueID:   "vpn_connected",
+				Name:       "vpn_connected",
+				State:      true,
+				Icon:       "mdi:script",
+				Type:       models.SensorTypeBinarySensor,
+				Attributes: models.Attributes{"data_source": linux.DataSrcDBus},
+			},
+		},
+		{
+			name:    "no id",
+			state:   dbus.MakeVariant("on"),
+			options: map[string]dbus.Variant{"sensor_name": dbus.MakeVariant("No ID")},
+			wantErr: true,
+		},
+		{
+			name:    "invalid option",
+			id:      "invalid",
+			state:   dbus.MakeVariant("on"),
+			options: map[string]dbus.Variant{"sensor_units": dbus.MakeVariant(uint32(1))},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entity, err := service.newSensor(tt.id, tt.state, tt.options)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidSensor)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			sensor, err := entity.AsSensor()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want.UniqueID, sensor.UniqueID)
+			assert.Equal(t, tt.want.Name, sensor.Name)
+			assert.Equal(t, tt.want.State, sensor.State)
+			assert.Equal(t, tt.want.UnitOfMeasurement, sensor.UnitOfMeasurement)
+			assert.Equal(t, tt.want.Icon, sensor.Icon)
+			assert.Equal(t, tt.want.Type, sensor.Type)
+			assert.Equal(t, tt.want.Attributes, sensor.Attributes)
+		})
+	}
+}
+
+func Test_newEvent(t *testing.T) {
+	entity, err := newEvent("backup_finished", map[string]dbus.Variant{"files": dbus.MakeVariant(uint32(42))})
+	require.NoError(t, err)
+
+	event, err := entity.AsEvent()
+	require.NoError(t, err)
+	assert.Equal(t, "backup_finished", event.Type)
+	assert.Equal(t, map[string]any{"files": float64(42)}, event.Data)
+
+	_, err = newEvent("", nil)
+	require.ErrorIs(t, err, ErrInvalidEvent)
+}
+
+func TestObject_PushSensor(t *testing.T) {
+	service := newTestService(t, nil)
+	obj := &object{service: service}
+
+	require.Nil(t, obj.PushSensor("backup_age", dbus.MakeVariant(3), nil))
+
+	sensor, err := (<-service.entityCh).AsSensor()
+	require.NoError(t, err)
+	assert.Equal(t, "backup_age", sensor.UniqueID)
+
+	dbusErr := obj.PushSensor("", dbus.MakeVariant(3), nil)
+	require.NotNil(t, dbusErr)
+	assert.Equal(t, errInvalidArgs, dbusErr.Name)
+
+	require.Nil(t, obj.FireEvent("backup_finished", nil))
+
+	event, err := (<-service.entityCh).AsEvent()
+	require.NoError(t, err)
+	assert.Equal(t, "backup_finished", event.Type)
+}
+
+func TestObject_ListSensors(t *testing.T) {
+	service := newTestService(t, nil)
+	obj := &object{service: service}
+
+	event, err := models.NewEvent("backup_finished", map[string]any{})
+	require.NoError(t, err)
+
+	entityCh := make(chan models.Entity, 4)
+	entityCh <- event
+	entityCh <- models.NewSensor(t.Context(), models.WithID("uptime"), models.WithName("Uptime"),
+		models.WithState(3600.5), models.WithUnits("s"))
+	entityCh <- models.NewSensor(t.Context(), models.WithID("desktop"), models.WithName("Desktop"),
+		models.WithState(map[string]any{"name": "GNOME"}))
+	entityCh <- models.NewSensor(t.Context(), models.WithID("uptime"), models.WithName("Uptime"),
+		models.WithState(3601.5), models.WithUnits("s"))
+	close(entityCh)
+
+	var observed int
+	for range service.Observe(t.Context(), entityCh) {
+		observed++
+	}
+
+	assert.Equal(t, 4, observed)
+
+	sensors, dbusErr := obj.ListSensors()
+	require.Nil(t, dbusErr)
+	assert.Equal(t, []listedSensor{
+		{ID: "desktop", Name: "Desktop", State: dbus.MakeVariant("map[name:GNOME]")},
+		{ID: "uptime", Name: "Uptime", State: dbus.MakeVariant(3601.5), Units: "s"},
+	}, sensors)
+}
+
+func TestObject_Notify(t *testing.T) {
+	var displayed *notifications.Record
+
+	service := newTestService(t, func(record *notifications.Record) error {
+		displayed = record
+
+		return nil
+	})
+	obj := &object{service: service}
+
+	id, dbusErr := obj.Notify("Backup", "Backup finished.", map[string]dbus.Variant{"priority": dbus.MakeVariant("high")})
+	require.Nil(t, dbusErr)
+	require.NotNil(t, displayed)
+	assert.Equal(t, id, displayed.ID)
+	assert.Equal(t, "Backup finished.", displayed.Message)
+	assert.Equal(t, map[string]any{"priority": "high"}, displayed.Data)
+}
+
+func Test_stateVariant(t *testing.T) {
+	assert.Equal(t, dbus.MakeVariant(int64(3)), stateVariant(3))
+	assert.Equal(t, dbus.MakeVariant("on"), stateVariant("on"))
+	assert.Equal(t, dbus.MakeVariant(""), stateVariant(nil))
+	assert.Equal(t, dbus.MakeVariant("[a b]"), stateVariant([]string{"a", "b"}))
+}
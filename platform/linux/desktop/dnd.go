@@ -0,0 +1,250 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package desktop
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+	slogctx "github.com/veqryn/slog-context"
+
+	"github.com/joshuar/go-hass-agent/agent/workers"
+	"github.com/joshuar/go-hass-agent/models"
+	"github.com/joshuar/go-hass-agent/pkg/linux/dbusx"
+	"github.com/joshuar/go-hass-agent/platform/linux"
+)
+
+var _ workers.EntityWorker = (*dndWorker)(nil)
+
+const (
+	notificationsPath      = "/org/freedesktop/Notifications"
+	notificationsInterface = "org.freedesktop.Notifications"
+	notificationsInhibited = "Inhibited"
+
+	gnomeNotificationsNamespace = "org.gnome.desktop.notifications"
+	gnomeShowBannersProp        = "show-banners"
+
+	dndSourceGNOME       = "gnome"
+	dndSourceFreedesktop = "freedesktop"
+)
+
+// dndState tracks the do-not-disturb status reported by each supported source.
+type dndState struct {
+	sources map[string]bool
+	mu      sync.Mutex
+}
+
+// set records the status for the given source. It returns a boolean indicating whether the status changed.
+func (s *dndState) set(source string, value bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, found := s.sources[source]
+	s.sources[source] = value
+
+	return !found || current != value
+}
+
+// enabled returns whether any source has do-not-disturb enabled, along with the list of sources that do.
+func (s *dndState) enabled() (bool, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var active []string
+
+	for source, value := range s.sources {
+		if value {
+			active = append(active, source)
+		}
+	}
+
+	slices.Sort(active)
+
+	return len(active) > 0, active
+}
+
+type dndWorker struct {
+	*models.WorkerMetadata
+
+	bus       *dbusx.Bus
+	inhibited *dbusx.Property[bool]
+	hasPortal bool
+	state     *dndState
+	prefs     *WorkerPrefs
+}
+
+// NewDNDWorker creates a worker to track the desktop do-not-disturb status. Do-not-disturb is considered enabled when
+// either GNOME has notification banners disabled or the freedesktop notification server (as used by KDE Plasma and
+// others) reports notifications as inhibited.
+func NewDNDWorker(ctx context.Context) (workers.EntityWorker, error) {
+	worker := &dndWorker{
+		WorkerMetadata: models.SetWorkerMetadata("do_not_disturb", "Do not disturb status"),
+		state:          &dndState{sources: make(map[string]bool)},
+	}
+
+	var ok bool
+
+	worker.bus, ok = linux.CtxGetSessionBus(ctx)
+	if !ok {
+		return worker, fmt.Errorf("get session bus: %w", linux.ErrNoSessionBus)
+	}
+
+	_, worker.hasPortal = linux.CtxGetDesktopPortal(ctx)
+
+	worker.inhibited = dbusx.NewProperty[bool](
+		worker.bus,
+		notificationsPath,
+		notificationsInterface,
+		notificationsInterface+"."+notificationsInhibited,
+	)
+
+	defaultPrefs := &WorkerPrefs{}
+	var err error
+	worker.prefs, err = workers.LoadWorkerPreferences(prefPrefix+"do_not_disturb", defaultPrefs)
+	if err != nil {
+		return worker, fmt.Errorf("load preferences: %w", err)
+	}
+
+	return worker, nil
+}
+
+func (w *dndWorker) IsDisabled() bool {
+	return w.prefs.IsDisabled()
+}
+
+func (w *dndWorker) Start(ctx context.Context) (<-chan models.Entity, error) {
+	inhibitedCh, err := dbusx.NewWatch(
+		dbusx.MatchPath(notificationsPath),
+		dbusx.MatchPropChanged(),
+	).Start(ctx, w.bus)
+	if err != nil {
+		return nil, fmt.Errorf("watch notification inhibition: %w", err)
+	}
+
+	var settingsCh <-chan dbusx.Trigger
+	if w.hasPortal {
+		settingsCh, err = dbusx.NewWatch(
+			dbusx.MatchPath(desktopPortalPath),
+			dbusx.MatchInterface(settingsPortalInterface),
+			dbusx.MatchMembers(settingsChangedSignal),
+			dbusx.MatchArgs(map[int]string{0: gnomeNotificationsNamespace}),
+		).Start(ctx, w.bus)
+		if err != nil {
+			slogctx.FromCtx(ctx).Debug("Unable to watch GNOME notification settings.", slog.Any("error", err))
+		}
+	}
+
+	sensorCh := make(chan models.Entity)
+
+	// Fetch initial states.
+	if inhibited, err := w.inhibited.Get(); err != nil {
+		slogctx.FromCtx(ctx).Debug("Could not retrieve notification inhibited state.", slog.Any("error", err))
+	} else {
+		w.state.set(dndSourceFreedesktop, inhibited)
+	}
+
+	if w.hasPortal {
+		if showBanners, err := w.getShowBanners(); err != nil {
+			slogctx.FromCtx(ctx).Debug("Could not retrieve GNOME notification banner setting.", slog.Any("error", err))
+		} else {
+			w.state.set(dndSourceGNOME, !showBanners)
+		}
+	}
+
+	go func() {
+		defer close(sensorCh)
+
+		send := func() bool {
+			select {
+			case sensorCh <- newDNDSensor(ctx, w.state):
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		// Send an initial update.
+		if !send() {
+			return
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-inhibitedCh:
+				changed, inhibited, err := dbusx.HasPropertyChanged[bool](event.Content, notificationsInhibited)
+				switch {
+				case err != nil:
+					slogctx.FromCtx(ctx).Debug("Could not parse received D-Bus signal.", slog.Any("error", err))
+				case changed:
+					if w.state.set(dndSourceFreedesktop, inhibited) && !send() {
+						return
+					}
+				}
+			case event := <-settingsCh:
+				if !strings.Contains(event.Signal, settingsChangedSignal) {
+					continue
+				}
+				if prop, value := extractProp(event.Content); prop == gnomeShowBannersProp {
+					showBanners, err := dbusx.VariantToValue[bool](value)
+					if err != nil {
+						slogctx.FromCtx(ctx).Debug("Could not parse GNOME notification banner setting.",
+							slog.Any("error", err))
+						continue
+					}
+					if w.state.set(dndSourceGNOME, !showBanners) && !send() {
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return sensorCh, nil
+}
+
+func (w *dndWorker) getShowBanners() (bool, error) {
+	value, err := dbusx.GetData[dbus.Variant](w.bus,
+		desktopPortalPath,
+		desktopPortalInterface,
+		settingsPortalInterface+".Read",
+		gnomeNotificationsNamespace,
+		gnomeShowBannersProp)
+	if err != nil {
+		return true, fmt.Errorf("could not retrieve %s from D-Bus: %w", gnomeShowBannersProp, err)
+	}
+
+	showBanners, err := dbusx.VariantToValue[bool](value)
+	if err != nil {
+		return true, fmt.Errorf("could not parse %s: %w", gnomeShowBannersProp, err)
+	}
+
+	return showBanners, nil
+}
+
+func newDNDSensor(ctx context.Context, state *dndState) models.Entity {
+	enabled, sources := state.enabled()
+
+	icon := "mdi:bell"
+	if enabled {
+		icon = "mdi:bell-off"
+	}
+
+	return models.NewSensor(ctx,
+		models.WithName("Do Not Disturb"),
+		models.WithID("do_not_disturb"),
+		models.AsTypeBinarySensor(),
+		models.WithIcon(icon),
+		models.WithState(enabled),
+		models.WithAttribute("sources", sources),
+		models.WithDataSourceAttribute(linux.DataSrcDBus),
+		models.AsRetryableRequest(true),
+	)
+}
@@ -8,8 +8,11 @@ package system
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"path"
+	"strings"
 
 	"github.com/eclipse/paho.golang/paho"
 	slogctx "github.com/veqryn/slog-context"
@@ -25,29 +28,69 @@ import (
 
 const (
 	dbusCmdPreferencesID = controlsPrefPrefix + "dbus_commands"
+
+	dbusCmdActionCall = "call"
+	dbusCmdActionGet  = "get"
+	dbusCmdActionSet  = "set"
+)
+
+var (
+	// ErrInvalidDBusCommand indicates a D-Bus command request is invalid.
+	ErrInvalidDBusCommand = errors.New("invalid D-Bus command")
+	// ErrDBusCommandNotAllowed indicates a D-Bus command request is for a
+	// destination or interface that is not allowed by the preferences.
+	ErrDBusCommandNotAllowed = errors.New("D-Bus command not allowed")
 )
 
+// dbusCommandMsg is a request to call a D-Bus method or get/set a D-Bus
+// property.
 type dbusCommandMsg struct {
+	ID             string `json:"id"`
+	Action         string `json:"action"`
 	Bus            string `json:"bus"`
 	Destination    string `json:"destination"`
 	Path           string `json:"path"`
+	Interface      string `json:"interface"`
 	Method         string `json:"method"`
+	Property       string `json:"property"`
 	Args           []any  `json:"args"`
+	Value          any    `json:"value"`
 	UseSessionPath bool   `json:"use_session_path"`
 }
 
-type dbusCmdWorker struct {
+// dbusCommandResponse is the response to a D-Bus command request. For method
+// calls, the result contains the values returned by the method. For property
+// gets, it contains the value of the property.
+type dbusCommandResponse struct {
+	ID      string `json:"id,omitempty"`
+	Success bool   `json:"success"`
+	Result  any    `json:"result,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// DBusCommandWorker listens on MQTT for requests to call D-Bus methods or
+// get/set D-Bus properties and publishes the results.
+type DBusCommandWorker struct {
 	*models.WorkerMetadata
 
-	prefs *workers.CommonWorkerPrefs
+	prefs         *DBusCommandPrefs
+	buses         map[string]*dbusx.Bus
+	responseTopic string
+	Subscription  *mqttapi.Subscription
+	MsgCh         chan mqttapi.Msg
 }
 
-func NewDBusCommandSubscription(ctx context.Context, device *mqtthass.Device) (*mqttapi.Subscription, error) {
-	worker := &dbusCmdWorker{
+// NewDBusCommandWorker creates a worker that runs D-Bus commands received on
+// MQTT. If the worker is disabled, nil is returned.
+func NewDBusCommandWorker(ctx context.Context, device *mqtthass.Device) (*DBusCommandWorker, error) {
+	topic := "gohassagent/" + device.Name + "/dbuscommand"
+	worker := &DBusCommandWorker{
 		WorkerMetadata: models.SetWorkerMetadata("dbus_commands", "Custom D-Bus Commands"),
+		responseTopic:  topic + "/response",
+		MsgCh:          make(chan mqttapi.Msg),
 	}
 
-	defaultPrefs := &workers.CommonWorkerPrefs{}
+	defaultPrefs := &DBusCommandPrefs{}
 	var err error
 	worker.prefs, err = workers.LoadWorkerPreferences(dbusCmdPreferencesID, defaultPrefs)
 	if err != nil {
@@ -61,62 +104,252 @@ func NewDBusCommandSubscription(ctx context.Context, device *mqtthass.Device) (*
 
 	systemBus, ok := linux.CtxGetSystemBus(ctx)
 	if !ok {
-		return nil, fmt.Errorf("get system bus: %w", err)
+		return nil, fmt.Errorf("get system bus: %w", linux.ErrNoSystemBus)
 	}
 
 	sessionBus, ok := linux.CtxGetSessionBus(ctx)
 	if !ok {
-		return nil, fmt.Errorf("get session bus: %w", err)
-	}
-
-	busMap := map[string]*dbusx.Bus{"session": sessionBus, "system": systemBus}
-
-	return &mqttapi.Subscription{
-			Callback: func(packet *paho.Publish) {
-				var (
-					dbusMsg dbusCommandMsg
-					err     error
-				)
-
-				logger := slogctx.FromCtx(ctx)
-
-				// Unmarshal the request.
-				if err = json.Unmarshal(packet.Payload, &dbusMsg); err != nil {
-					logger.Error("Could not unmarshal D-Bus MQTT message.", slog.Any("error", err))
-
-					return
-				}
-				// Check which bus type was requested.
-				bus, busOk := busMap[dbusMsg.Bus]
-				if !busOk {
-					logger.Error("Unsupported D-Bus type.")
-
-					return
-				}
-				// Fetch the session path if requested.
-				if dbusMsg.UseSessionPath {
-					dbusMsg.Path, err = busMap["session"].GetSessionPath()
-					if err != nil {
-						logger.Error("Could not determine session path.", slog.Any("error", err))
-
-						return
-					}
-				}
-
-				logger.With(
-					slog.String("bus", dbusMsg.Bus),
-					slog.String("destination", dbusMsg.Destination),
-					slog.String("path", dbusMsg.Path),
-					slog.String("method", dbusMsg.Method),
-				).Info("Dispatching D-Bus command.")
-
-				// Call the method.
-				if err = dbusx.NewMethod(bus, dbusMsg.Destination, dbusMsg.Path, dbusMsg.Method).
-					Call(ctx, dbusMsg.Args...); err != nil {
-					logger.Warn("Error dispatching D-Bus command.", slog.Any("error", err))
-				}
-			},
-			Topic: "gohassagent/" + device.Name + "/dbuscommand",
+		return nil, fmt.Errorf("get session bus: %w", linux.ErrNoSessionBus)
+	}
+
+	worker.buses = map[string]*dbusx.Bus{"session": sessionBus, "system": systemBus}
+
+	if !worker.hasAllowlist() {
+		slogctx.FromCtx(ctx).Warn("No D-Bus command allowlist set. Methods of any destination and interface can be called, and getting or setting properties is not allowed.")
+	}
+
+	worker.Subscription = &mqttapi.Subscription{
+		Callback: func(packet *paho.Publish) {
+			worker.handleRequest(ctx, packet)
 		},
-		nil
+		Topic: topic,
+	}
+
+	return worker, nil
+}
+
+// handleRequest runs the D-Bus command in the given request and publishes the
+// response. The response is published on the response topic of the request,
+// if it has one (MQTT v5), otherwise on the worker's response topic.
+func (w *DBusCommandWorker) handleRequest(ctx context.Context, packet *paho.Publish) {
+	var dbusMsg dbusCommandMsg
+
+	response := &dbusCommandResponse{}
+	responseTopic := w.responseTopic
+
+	if packet.Properties != nil {
+		if packet.Properties.ResponseTopic != "" {
+			responseTopic = packet.Properties.ResponseTopic
+		}
+		// Use the correlation data as the request ID if the request does not
+		// have one.
+		response.ID = string(packet.Properties.CorrelationData)
+	}
+
+	if err := json.Unmarshal(packet.Payload, &dbusMsg); err != nil {
+		response.Error = fmt.Errorf("%w: %w", ErrInvalidDBusCommand, err).Error()
+	} else {
+		if dbusMsg.ID != "" {
+			response.ID = dbusMsg.ID
+		}
+
+		result, err := w.run(ctx, &dbusMsg)
+		if err != nil {
+			response.Error = err.Error()
+		} else {
+			response.Success = true
+			response.Result = dbusx.PlainValue(result)
+		}
+	}
+
+	if !response.Success {
+		slogctx.FromCtx(ctx).Warn("Error dispatching D-Bus command.",
+			slog.String("id", response.ID),
+			slog.String("error", response.Error))
+	}
+
+	payload, err := json.Marshal(response)
+	if err != nil {
+		slogctx.FromCtx(ctx).Warn("Could not marshal D-Bus command response.",
+			slog.Any("error", err))
+
+		return
+	}
+
+	go func() {
+		select {
+		case w.MsgCh <- *mqttapi.NewMsg(responseTopic, payload):
+		case <-ctx.Done():
+		}
+	}()
+}
+
+// run validates the given request and runs the D-Bus command, returning the
+// result.
+func (w *DBusCommandWorker) run(ctx context.Context, dbusMsg *dbusCommandMsg) (any, error) {
+	intr, err := dbusMsg.validate()
+	if err != nil {
+		return nil, err
+	}
+	// Check which bus type was requested.
+	bus, found := w.buses[dbusMsg.Bus]
+	if !found {
+		return nil, fmt.Errorf("%w: unsupported bus %q", ErrInvalidDBusCommand, dbusMsg.Bus)
+	}
+
+	if !w.allowed(dbusMsg.Action, dbusMsg.Destination, intr) {
+		return nil, fmt.Errorf("%w: %s %s (%s)", ErrDBusCommandNotAllowed, dbusMsg.Action, dbusMsg.Destination, intr)
+	}
+	// Fetch the session path if requested.
+	if dbusMsg.UseSessionPath {
+		dbusMsg.Path, err = w.buses["session"].GetSessionPath()
+		if err != nil {
+			return nil, fmt.Errorf("could not determine session path: %w", err)
+		}
+	}
+
+	slogctx.FromCtx(ctx).Info("Dispatching D-Bus command.",
+		slog.String("id", dbusMsg.ID),
+		slog.String("action", dbusMsg.Action),
+		slog.String("bus", dbusMsg.Bus),
+		slog.String("destination", dbusMsg.Destination),
+		slog.String("path", dbusMsg.Path),
+		slog.String("interface", intr),
+		slog.String("method", dbusMsg.Method),
+		slog.String("property", dbusMsg.Property),
+	)
+
+	switch dbusMsg.Action {
+	case dbusCmdActionGet:
+		value, err := dbusx.NewProperty[any](bus, dbusMsg.Path, dbusMsg.Destination, intr+"."+dbusMsg.Property).Get()
+		if err != nil {
+			return nil, fmt.Errorf("get property: %w", err)
+		}
+
+		return value, nil
+	case dbusCmdActionSet:
+		value, err := propertyValue(bus, dbusMsg.Destination, dbusMsg.Path, intr, dbusMsg.Property, dbusMsg.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := dbusx.NewProperty[any](bus, dbusMsg.Path, dbusMsg.Destination, intr+"."+dbusMsg.Property).Set(value); err != nil {
+			return nil, fmt.Errorf("set property: %w", err)
+		}
+
+		return nil, nil
+	default:
+		result, err := dbusx.NewMethod(bus, dbusMsg.Destination, dbusMsg.Path, dbusMsg.Method).Invoke(ctx, dbusMsg.Args...)
+		if err != nil {
+			return nil, fmt.Errorf("call method: %w", err)
+		}
+
+		return result, nil
+	}
+}
+
+// allowed returns whether the given action on the given destination and
+// interface is allowed by the preferences. Without an allowlist, only method
+// calls are allowed, as getting or setting properties could read or change any
+// state on the bus.
+func (w *DBusCommandWorker) allowed(action, destination, intr string) bool {
+	if !w.hasAllowlist() && action != dbusCmdActionCall {
+		return false
+	}
+
+	return matchesAny(w.prefs.AllowedDestinations, destination) && matchesAny(w.prefs.AllowedInterfaces, intr)
+}
+
+// hasAllowlist returns whether any allowed destinations or interfaces have been
+// set in the preferences.
+func (w *DBusCommandWorker) hasAllowlist() bool {
+	return len(w.prefs.AllowedDestinations) > 0 || len(w.prefs.AllowedInterfaces) > 0
+}
+
+// validate checks the request has the fields required for its action and
+// returns the interface of the method or property. The method name is
+// qualified with the interface if needed.
+func (m *dbusCommandMsg) validate() (string, error) {
+	if m.Action == "" {
+		m.Action = dbusCmdActionCall
+	}
+
+	if m.Destination == "" {
+		return "", fmt.Errorf("%w: no destination", ErrInvalidDBusCommand)
+	}
+
+	if m.Path == "" && !m.UseSessionPath {
+		return "", fmt.Errorf("%w: no path", ErrInvalidDBusCommand)
+	}
+
+	switch m.Action {
+	case dbusCmdActionCall:
+		if m.Method == "" {
+			return "", fmt.Errorf("%w: no method", ErrInvalidDBusCommand)
+		}
+
+		if m.Interface != "" && !strings.HasPrefix(m.Method, m.Interface+".") {
+			m.Method = m.Interface + "." + m.Method
+		}
+
+		idx := strings.LastIndex(m.Method, ".")
+		if idx <= 0 {
+			return "", fmt.Errorf("%w: method %q does not include an interface", ErrInvalidDBusCommand, m.Method)
+		}
+
+		return m.Method[:idx], nil
+	case dbusCmdActionGet, dbusCmdActionSet:
+		if m.Interface == "" || m.Property == "" {
+			return "", fmt.Errorf("%w: no interface or property", ErrInvalidDBusCommand)
+		}
+
+		if m.Action == dbusCmdActionSet && m.Value == nil {
+			return "", fmt.Errorf("%w: no value", ErrInvalidDBusCommand)
+		}
+
+		return m.Interface, nil
+	default:
+		return "", fmt.Errorf("%w: unknown action %q", ErrInvalidDBusCommand, m.Action)
+	}
+}
+
+// propertyValue validates the given value against the introspected type of the
+// property, returning the value converted to the D-Bus type of the property.
+func propertyValue(bus *dbusx.Bus, destination, objPath, intr, property string, value any) (any, error) {
+	introspection, err := dbusx.NewIntrospection(bus, destination, objPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not introspect: %w", err)
+	}
+
+	details, err := introspection.GetProperty(intr, property)
+	if err != nil {
+		return nil, fmt.Errorf("%s.%s: %w", intr, property, err)
+	}
+
+	if !strings.Contains(details.Access, "write") {
+		return nil, fmt.Errorf("%w: property %s.%s is read-only", ErrInvalidDBusCommand, intr, property)
+	}
+
+	coerced, err := dbusx.CoerceValue(value, details.Type)
+	if err != nil {
+		return nil, fmt.Errorf("%w: value: %w", ErrInvalidDBusCommand, err)
+	}
+
+	return coerced, nil
+}
+
+// matchesAny returns whether the given name matches any of the given patterns.
+// An empty list of patterns matches any name.
+func matchesAny(patterns []string, name string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, name); err == nil && matched {
+			return true
+		}
+	}
+
+	return false
 }
@@ -0,0 +1,104 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package system
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_dbusCommandMsg_validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		msg        dbusCommandMsg
+		wantIntr   string
+		wantMethod string
+		wantAction string
+		wantErr    bool
+	}{
+		{
+			name:       "qualified method",
+			msg:        dbusCommandMsg{Destination: "org.freedesktop.Notifications", Path: "/org/freedesktop/Notifications", Method: "org.freedesktop.Notifications.Notify"},
+			wantIntr:   "org.freedesktop.Notifications",
+			wantMethod: "org.freedesktop.Notifications.Notify",
+			wantAction: dbusCmdActionCall,
+		},
+		{
+			name:       "method with interface",
+			msg:        dbusCommandMsg{Destination: "org.freedesktop.Notifications", Path: "/org/freedesktop/Notifications", Interface: "org.freedesktop.Notifications", Method: "Notify"},
+			wantIntr:   "org.freedesktop.Notifications",
+			wantMethod: "org.freedesktop.Notifications.Notify",
+			wantAction: dbusCmdActionCall,
+		},
+		{
+			name:    "unqualified method",
+			msg:     dbusCommandMsg{Destination: "org.freedesktop.Notifications", Path: "/org/freedesktop/Notifications", Method: "Notify"},
+			wantErr: true,
+		},
+		{
+			name:       "get property",
+			msg:        dbusCommandMsg{Action: dbusCmdActionGet, Destination: "org.freedesktop.login1", UseSessionPath: true, Interface: "org.freedesktop.login1.Session", Property: "IdleHint"},
+			wantIntr:   "org.freedesktop.login1.Session",
+			wantAction: dbusCmdActionGet,
+		},
+		{
+			name:    "set property without value",
+			msg:     dbusCommandMsg{Action: dbusCmdActionSet, Destination: "org.freedesktop.login1", Path: "/", Interface: "org.freedesktop.login1.Session", Property: "IdleHint"},
+			wantErr: true,
+		},
+		{
+			name:    "property without interface",
+			msg:     dbusCommandMsg{Action: dbusCmdActionGet, Destination: "org.freedesktop.login1", Path: "/", Property: "IdleHint"},
+			wantErr: true,
+		},
+		{
+			name:    "no destination",
+			msg:     dbusCommandMsg{Path: "/", Method: "org.freedesktop.Notifications.Notify"},
+			wantErr: true,
+		},
+		{
+			name:    "no path",
+			msg:     dbusCommandMsg{Destination: "org.freedesktop.Notifications", Method: "org.freedesktop.Notifications.Notify"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown action",
+			msg:     dbusCommandMsg{Action: "delete", Destination: "org.freedesktop.Notifications", Path: "/"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			intr, err := tt.msg.validate()
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidDBusCommand)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantIntr, intr)
+			assert.Equal(t, tt.wantAction, tt.msg.Action)
+			if tt.wantMethod != "" {
+				assert.Equal(t, tt.wantMethod, tt.msg.Method)
+			}
+		})
+	}
+}
+
+func TestDBusCommandWorker_allowed(t *testing.T) {
+	worker := &DBusCommandWorker{prefs: &DBusCommandPrefs{}}
+	// No allowlist allows calling any method, but not getting or setting properties.
+	assert.True(t, worker.allowed(dbusCmdActionCall, "org.example.Foo", "org.example.Foo"))
+	assert.False(t, worker.allowed(dbusCmdActionGet, "org.example.Foo", "org.example.Foo"))
+	assert.False(t, worker.allowed(dbusCmdActionSet, "org.example.Foo", "org.example.Foo"))
+
+	worker.prefs.AllowedDestinations = []string{"org.freedesktop.*"}
+	worker.prefs.AllowedInterfaces = []string{"org.freedesktop.Notifications", "org.freedesktop.login1.Session"}
+	assert.True(t, worker.allowed(dbusCmdActionCall, "org.freedesktop.Notifications", "org.freedesktop.Notifications"))
+	assert.True(t, worker.allowed(dbusCmdActionSet, "org.freedesktop.login1", "org.freedesktop.login1.Session"))
+	assert.True(t, worker.allowed(dbusCmdActionGet, "org.freedesktop.login1", "org.freedesktop.login1.Session"))
+	assert.False(t, worker.allowed(dbusCmdActionCall, "org.example.Foo", "org.freedesktop.Notifications"))
+	assert.False(t, worker.allowed(dbusCmdActionSet, "org.freedesktop.systemd1", "org.freedesktop.systemd1.Manager"))
+}
@@ -0,0 +1,348 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package system
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"github.com/iancoleman/strcase"
+	slogctx "github.com/veqryn/slog-context"
+
+	"github.com/joshuar/go-hass-agent/agent/workers"
+	"github.com/joshuar/go-hass-agent/models"
+	"github.com/joshuar/go-hass-agent/pkg/linux/dbusx"
+	"github.com/joshuar/go-hass-agent/platform/linux"
+	"github.com/joshuar/go-hass-agent/validation"
+)
+
+const (
+	dbusSensorsPreferencesID = sensorsPrefPrefix + "dbus_sensors"
+)
+
+var _ workers.EntityWorker = (*dbusSensorsWorker)(nil)
+
+var (
+	// ErrInvalidDBusSensor indicates a D-Bus sensor in the preferences is
+	// invalid.
+	ErrInvalidDBusSensor = errors.New("invalid D-Bus sensor")
+	// ErrNoSignalArg indicates a D-Bus signal did not have the argument for a
+	// D-Bus sensor.
+	ErrNoSignalArg = errors.New("signal argument not found")
+	// ErrNoDBusSensors indicates none of the D-Bus sensors could be watched.
+	ErrNoDBusSensors = errors.New("no D-Bus sensors could be watched")
+)
+
+type dbusSensorsWorker struct {
+	*models.WorkerMetadata
+
+	sensors []*dbusSensor
+	prefs   *DBusSensorsPrefs
+}
+
+// dbusSensor is a D-Bus sensor with the bus and object path it uses resolved.
+type dbusSensor struct {
+	*DBusSensor
+
+	bus  *dbusx.Bus
+	path string
+}
+
+// NewDBusSensorsWorker creates a worker for the user-defined sensors from D-Bus
+// properties and signals in the preferences. Any invalid sensors are logged and
+// skipped.
+func NewDBusSensorsWorker(ctx context.Context) (workers.EntityWorker, error) {
+	worker := &dbusSensorsWorker{
+		WorkerMetadata: models.SetWorkerMetadata("dbus_sensors", "Custom D-Bus sensors"),
+	}
+
+	defaultPrefs := &DBusSensorsPrefs{}
+	var err error
+	worker.prefs, err = workers.LoadWorkerPreferences(dbusSensorsPreferencesID, defaultPrefs)
+	if err != nil {
+		return worker, fmt.Errorf("load preferences: %w", err)
+	}
+
+	if worker.prefs.IsDisabled() {
+		return worker, nil
+	}
+
+	buses := make(map[string]*dbusx.Bus)
+	if bus, ok := linux.CtxGetSessionBus(ctx); ok {
+		buses["session"] = bus
+	}
+	if bus, ok := linux.CtxGetSystemBus(ctx); ok {
+		buses["system"] = bus
+	}
+
+	for idx := range worker.prefs.Sensors {
+		sensor, err := newDBusSensor(ctx, &worker.prefs.Sensors[idx], buses)
+		if err != nil {
+			slogctx.FromCtx(ctx).Warn("Ignoring D-Bus sensor.",
+				slog.String("sensor", worker.prefs.Sensors[idx].Name),
+				slog.Any("error", err))
+
+			continue
+		}
+
+		worker.sensors = append(worker.sensors, sensor)
+	}
+
+	return worker, nil
+}
+
+// newDBusSensor validates the given sensor preferences and resolves the bus
+// and object path of the sensor.
+func newDBusSensor(ctx context.Context, prefs *DBusSensor, buses map[string]*dbusx.Bus) (*dbusSensor, error) {
+	if err := validation.ValidateStruct(prefs); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDBusSensor, err)
+	}
+
+	sensor := &dbusSensor{DBusSensor: prefs, path: prefs.Path}
+
+	var found bool
+
+	sensor.bus, found = buses[prefs.Bus]
+	if !found {
+		return nil, fmt.Errorf("%w: %s bus not available", ErrInvalidDBusSensor, prefs.Bus)
+	}
+
+	if prefs.UseSessionPath {
+		sensor.path, found = linux.CtxGetSessionPath(ctx)
+		if !found {
+			return nil, fmt.Errorf("%w: session path not available", ErrInvalidDBusSensor)
+		}
+	}
+
+	return sensor, nil
+}
+
+// IsDisabled returns whether the worker is disabled. The worker is also
+// disabled if there are no valid sensors.
+func (w *dbusSensorsWorker) IsDisabled() bool {
+	return w.prefs.IsDisabled() || len(w.sensors) == 0
+}
+
+// Start watches each sensor for changes, sending the sensor whenever its value
+// changes. The current value of property sensors is sent on start.
+func (w *dbusSensorsWorker) Start(ctx context.Context) (<-chan models.Entity, error) {
+	sensorCh := make(chan models.Entity)
+
+	var (
+		wg      sync.WaitGroup
+		started int
+	)
+
+	for _, sensor := range w.sensors {
+		watch, err := sensor.watch()
+		if err != nil {
+			slogctx.FromCtx(ctx).Warn("Could not watch D-Bus sensor.",
+				slog.String("sensor", sensor.Name),
+				slog.Any("error", err))
+
+			continue
+		}
+
+		triggerCh, err := watch.Start(ctx, sensor.bus)
+		if err != nil {
+			slogctx.FromCtx(ctx).Warn("Could not watch D-Bus sensor.",
+				slog.String("sensor", sensor.Name),
+				slog.Any("error", err))
+
+			continue
+		}
+
+		started++
+
+		wg.Go(func() {
+			sensor.run(ctx, triggerCh, sensorCh)
+		})
+	}
+
+	go func() {
+		wg.Wait()
+		close(sensorCh)
+	}()
+
+	if started == 0 {
+		return sensorCh, ErrNoDBusSensors
+	}
+
+	return sensorCh, nil
+}
+
+// watch returns the D-Bus watch for changes to the sensor value. Only changes
+// sent by the current owner of the sensor destination are watched, so other
+// connections on the bus cannot set the sensor value.
+func (s *dbusSensor) watch() (*dbusx.Watch, error) {
+	owner, err := s.bus.GetNameOwner(s.Destination)
+	if err != nil {
+		return nil, fmt.Errorf("resolve destination: %w", err)
+	}
+
+	if s.Property != "" {
+		return dbusx.NewWatch(
+			dbusx.MatchSender(owner),
+			dbusx.MatchPath(s.path),
+			dbusx.MatchPropChanged(),
+			dbusx.MatchArgs(map[int]string{0: s.Interface}),
+		), nil
+	}
+
+	return dbusx.NewWatch(
+		dbusx.MatchSender(owner),
+		dbusx.MatchPath(s.path),
+		dbusx.MatchInterface(s.Interface),
+		dbusx.MatchMembers(s.Signal),
+	), nil
+}
+
+// run sends the sensor with its current value (for property sensors) and then
+// whenever the watch is triggered with a new value, until the context is
+// canceled.
+func (s *dbusSensor) run(ctx context.Context, triggerCh <-chan dbusx.Trigger, sensorCh chan<- models.Entity) {
+	send := func(value any) {
+		select {
+		case sensorCh <- s.entity(ctx, value):
+		case <-ctx.Done():
+		}
+	}
+
+	if s.Property != "" {
+		if value, err := s.get(); err != nil {
+			slogctx.FromCtx(ctx).Debug("Could not retrieve D-Bus sensor value.",
+				slog.String("sensor", s.Name),
+				slog.Any("error", err))
+		} else {
+			send(value)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case trigger, ok := <-triggerCh:
+			if !ok {
+				return
+			}
+
+			value, found, refetch, err := s.triggerValue(trigger)
+			if refetch {
+				value, err = s.get()
+				found = err == nil
+			}
+
+			switch {
+			case err != nil:
+				slogctx.FromCtx(ctx).Debug("Could not parse D-Bus sensor value.",
+					slog.String("sensor", s.Name),
+					slog.Any("error", err))
+			case found:
+				send(value)
+			}
+		}
+	}
+}
+
+// get retrieves the current value of a property sensor.
+func (s *dbusSensor) get() (any, error) {
+	value, err := dbusx.NewProperty[any](s.bus, s.path, s.Destination, s.Interface+"."+s.Property).Get()
+	if err != nil {
+		return nil, fmt.Errorf("get property: %w", err)
+	}
+
+	return value, nil
+}
+
+// triggerValue returns the sensor value from the given D-Bus trigger. For
+// property sensors, found is false if the property did not change, and refetch
+// is true if the property was invalidated (and so its value must be
+// retrieved).
+func (s *DBusSensor) triggerValue(trigger dbusx.Trigger) (value any, found, refetch bool, err error) {
+	if s.Property == "" {
+		if trigger.Signal != s.Interface+"."+s.Signal {
+			return nil, false, false, nil
+		}
+
+		if s.Arg >= len(trigger.Content) {
+			return nil, false, false, fmt.Errorf("%w: %s has no argument %d", ErrNoSignalArg, trigger.Signal, s.Arg)
+		}
+
+		return trigger.Content[s.Arg], true, false, nil
+	}
+
+	if trigger.Signal != dbusx.PropChangedSignal {
+		return nil, false, false, nil
+	}
+
+	props, err := dbusx.ParsePropertiesChanged(trigger.Content)
+	if err != nil {
+		return nil, false, false, fmt.Errorf("parse properties: %w", err)
+	}
+
+	if props.Interface != s.Interface {
+		return nil, false, false, nil
+	}
+
+	if variant, changed := props.Changed[s.Property]; changed {
+		return variant.Value(), true, false, nil
+	}
+
+	return nil, false, slices.Contains(props.Invalidated, s.Property), nil
+}
+
+// entity returns the sensor entity with the given D-Bus value as its state.
+// Boolean values are represented as a binary sensor.
+func (s *DBusSensor) entity(ctx context.Context, value any) models.Entity {
+	state := dbusState(value)
+
+	id := s.ID
+	if id == "" {
+		id = strcase.ToSnake(s.Name)
+	}
+
+	icon := s.Icon
+	if icon == "" {
+		icon = "mdi:bus"
+	}
+
+	typeOption := models.AsTypeSensor()
+	if _, isBool := state.(bool); isBool {
+		typeOption = models.AsTypeBinarySensor()
+	}
+
+	return models.NewSensor(ctx,
+		models.WithName(s.Name),
+		models.WithID(id),
+		typeOption,
+		models.WithIcon(icon),
+		models.WithUnits(s.Units),
+		models.WithDeviceClass(models.ParseSensorDeviceClass(s.DeviceClass)),
+		models.WithStateClass(models.ParseSensorStateClass(s.StateClass)),
+		models.WithState(state),
+		models.WithDataSourceAttribute(linux.DataSrcDBus),
+	)
+}
+
+// dbusState converts the given D-Bus value into a sensor state. Byte arrays
+// are treated as strings and any other arrays, dicts or structs are
+// represented by their string form.
+func dbusState(value any) any {
+	value = dbusx.PlainValue(value)
+
+	switch state := value.(type) {
+	case []byte:
+		return string(bytes.TrimRight(state, "\x00"))
+	case []any, map[string]any:
+		return fmt.Sprint(state)
+	default:
+		return state
+	}
+}
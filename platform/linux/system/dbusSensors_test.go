@@ -0,0 +1,253 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package system
+
+import (
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshuar/go-hass-agent/models"
+	"github.com/joshuar/go-hass-agent/pkg/linux/dbusx"
+)
+
+func Test_newDBusSensor(t *testing.T) {
+	buses := map[string]*dbusx.Bus{"system": {}}
+
+	tests := []struct {
+		name    string
+		sensor  DBusSensor
+		wantErr bool
+	}{
+		{
+			name: "property",
+			sensor: DBusSensor{
+				Name: "Battery Level", Bus: "system", Destination: "org.freedesktop.UPower",
+				Path: "/org/freedesktop/UPower/devices/DisplayDevice", Interface: "org.freedesktop.UPower.Device",
+				Property: "Percentage",
+			},
+		},
+		{
+			name: "signal",
+			sensor: DBusSensor{
+				Name: "Last Prepare For Sleep", Bus: "system", Destination: "org.freedesktop.login1",
+				Path: "/org/freedesktop/login1", Interface: "org.freedesktop.login1.Manager",
+				Signal: "PrepareForSleep",
+			},
+		},
+		{
+			name: "property and signal",
+			sensor: DBusSensor{
+				Name: "Invalid", Bus: "system", Destination: "org.freedesktop.login1",
+				Path: "/org/freedesktop/login1", Interface: "org.freedesktop.login1.Manager",
+				Property: "IdleHint", Signal: "PrepareForSleep",
+			},
+			wantErr: true,
+		},
+		{
+			name: "no property or signal",
+			sensor: DBusSensor{
+				Name: "Invalid", Bus: "system", Destination: "org.freedesktop.login1",
+				Path: "/org/freedesktop/login1", Interface: "org.freedesktop.login1.Manager",
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown bus",
+			sensor: DBusSensor{
+				Name: "Invalid", Bus: "other", Destination: "org.freedesktop.login1",
+				Path: "/org/freedesktop/login1", Interface: "org.freedesktop.login1.Manager", Property: "IdleHint",
+			},
+			wantErr: true,
+		},
+		{
+			name: "bus not available",
+			sensor: DBusSensor{
+				Name: "Invalid", Bus: "session", Destination: "org.freedesktop.Notifications",
+				Path: "/org/freedesktop/Notifications", Interface: "org.freedesktop.Notifications", Property: "Inhibited",
+			},
+			wantErr: true,
+		},
+		{
+			name: "no path",
+			sensor: DBusSensor{
+				Name: "Invalid", Bus: "system", Destination: "org.freedesktop.login1",
+				Interface: "org.freedesktop.login1.Session", Property: "IdleHint",
+			},
+			wantErr: true,
+		},
+		{
+			name: "session path not available",
+			sensor: DBusSensor{
+				Name: "Idle", Bus: "system", Destination: "org.freedesktop.login1", UseSessionPath: true,
+				Interface: "org.freedesktop.login1.Session", Property: "IdleHint",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newDBusSensor(t.Context(), &tt.sensor, buses)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidDBusSensor)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.sensor.Path, got.path)
+		})
+	}
+}
+
+func TestDBusSensor_triggerValue(t *testing.T) {
+	propSensor := &DBusSensor{Interface: "org.freedesktop.UPower.Device", Property: "Percentage"}
+	signalSensor := &DBusSensor{Interface: "org.freedesktop.login1.Manager", Signal: "PrepareForSleep", Arg: 0}
+
+	tests := []struct {
+		name        string
+		sensor      *DBusSensor
+		trigger     dbusx.Trigger
+		wantValue   any
+		wantFound   bool
+		wantRefetch bool
+		wantErr     bool
+	}{
+		{
+			name:   "property changed",
+			sensor: propSensor,
+			trigger: dbusx.Trigger{
+				Signal: dbusx.PropChangedSignal,
+				Content: []any{
+					"org.freedesktop.UPower.Device",
+					map[string]dbus.Variant{"Percentage": dbus.MakeVariant(80.0)},
+					[]string{},
+				},
+			},
+			wantValue: 80.0,
+			wantFound: true,
+		},
+		{
+			name:   "other property changed",
+			sensor: propSensor,
+			trigger: dbusx.Trigger{
+				Signal: dbusx.PropChangedSignal,
+				Content: []any{
+					"org.freedesktop.UPower.Device",
+					map[string]dbus.Variant{"State": dbus.MakeVariant(uint32(1))},
+					[]string{},
+				},
+			},
+		},
+		{
+			name:   "property invalidated",
+			sensor: propSensor,
+			trigger: dbusx.Trigger{
+				Signal: dbusx.PropChangedSignal,
+				Content: []any{
+					"org.freedesktop.UPower.Device",
+					map[string]dbus.Variant{},
+					[]string{"Percentage"},
+				},
+			},
+			wantRefetch: true,
+		},
+		{
+			name:   "other interface",
+			sensor: propSensor,
+			trigger: dbusx.Trigger{
+				Signal: dbusx.PropChangedSignal,
+				Content: []any{
+					"org.freedesktop.UPower",
+					map[string]dbus.Variant{"Percentage": dbus.MakeVariant(80.0)},
+					[]string{},
+				},
+			},
+		},
+		{
+			name:    "invalid properties changed",
+			sensor:  propSensor,
+			trigger: dbusx.Trigger{Signal: dbusx.PropChangedSignal, Content: []any{"org.freedesktop.UPower.Device"}},
+			wantErr: true,
+		},
+		{
+			name:      "signal",
+			sensor:    signalSensor,
+			trigger:   dbusx.Trigger{Signal: "org.freedesktop.login1.Manager.PrepareForSleep", Content: []any{true}},
+			wantValue: true,
+			wantFound: true,
+		},
+		{
+			name:    "other signal",
+			sensor:  signalSensor,
+			trigger: dbusx.Trigger{Signal: "org.freedesktop.login1.Manager.PrepareForShutdown", Content: []any{true}},
+		},
+		{
+			name:    "missing signal argument",
+			sensor:  signalSensor,
+			trigger: dbusx.Trigger{Signal: "org.freedesktop.login1.Manager.PrepareForSleep"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, found, refetch, err := tt.sensor.triggerValue(tt.trigger)
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantValue, value)
+			assert.Equal(t, tt.wantFound, found)
+			assert.Equal(t, tt.wantRefetch, refetch)
+		})
+	}
+}
+
+func TestDBusSensor_entity(t *testing.T) {
+	sensor := &DBusSensor{
+		Name:        "Battery Level",
+		Units:       "%",
+		DeviceClass: "battery",
+		StateClass:  "measurement",
+	}
+
+	battery, err := sensor.entity(t.Context(), 80.0).AsSensor()
+	require.NoError(t, err)
+	assert.Equal(t, "battery_level", battery.UniqueID)
+	assert.Equal(t, models.SensorTypeSensor, battery.Type)
+	assert.Equal(t, "%", battery.UnitOfMeasurement)
+	assert.Equal(t, models.SensorClassBattery.String(), battery.DeviceClass)
+	assert.Equal(t, models.StateMeasurement.String(), battery.StateClass)
+	assert.InDelta(t, 80.0, battery.State, 0)
+
+	idle, err := (&DBusSensor{Name: "Idle", ID: "session_idle"}).entity(t.Context(), true).AsSensor()
+	require.NoError(t, err)
+	assert.Equal(t, "session_idle", idle.UniqueID)
+	assert.Equal(t, models.SensorTypeBinarySensor, idle.Type)
+	assert.Equal(t, true, idle.State)
+}
+
+func Test_dbusState(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  any
+	}{
+		{name: "number", value: uint32(3), want: uint32(3)},
+		{name: "variant", value: dbus.MakeVariant("on"), want: "on"},
+		{name: "object path", value: dbus.ObjectPath("/org/freedesktop/login1/session/_31"), want: "/org/freedesktop/login1/session/_31"},
+		{name: "byte array", value: []byte("home-wifi\x00"), want: "home-wifi"},
+		{name: "array", value: []string{"a", "b"}, want: "[a b]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, dbusState(tt.value))
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// Code generated by dbusxgen from login1.xml. DO NOT EDIT.
+
+package system
+
+import (
+	"context"
+
+	"github.com/godbus/dbus/v5"
+
+	"github.com/joshuar/go-hass-agent/pkg/linux/dbusx"
+)
+
+// Login1ManagerInterface is the name of the org.freedesktop.login1.Manager D-Bus interface.
+const Login1ManagerInterface = "org.freedesktop.login1.Manager"
+
+// Login1Manager is a client for the org.freedesktop.login1.Manager D-Bus interface.
+type Login1Manager struct {
+	bus  *dbusx.Bus
+	dest string
+	path string
+}
+
+// NewLogin1Manager creates a client for the org.freedesktop.login1.Manager D-Bus interface of the object at the given path of the given destination.
+func NewLogin1Manager(bus *dbusx.Bus, dest, path string) *Login1Manager {
+	return &Login1Manager{bus: bus, dest: dest, path: path}
+}
+
+// ListSessions calls the ListSessions method of the D-Bus interface.
+func (c *Login1Manager) ListSessions(ctx context.Context) (sessions []Login1Session, err error) {
+	err = dbusx.CallMethod(ctx, c.bus, c.dest, c.path, Login1ManagerInterface+".ListSessions", []any{}, &sessions)
+
+	return sessions, err
+}
+
+// Login1ManagerSessionNewSignal contains the arguments of the SessionNew signal of the org.freedesktop.login1.Manager D-Bus interface.
+type Login1ManagerSessionNewSignal struct {
+	SessionID  string
+	ObjectPath dbus.ObjectPath
+}
+
+// WatchSessionNew watches for the SessionNew signal of the D-Bus interface. The arguments of each signal are sent on the returned channel until the context is canceled.
+func (c *Login1Manager) WatchSessionNew(ctx context.Context) (<-chan Login1ManagerSessionNewSignal, error) {
+	return dbusx.WatchSignal[Login1ManagerSessionNewSignal](ctx, c.bus, c.path, Login1ManagerInterface, "SessionNew")
+}
+
+// Login1ManagerSessionRemovedSignal contains the arguments of the SessionRemoved signal of the org.freedesktop.login1.Manager D-Bus interface.
+type Login1ManagerSessionRemovedSignal struct {
+	SessionID  string
+	ObjectPath dbus.ObjectPath
+}
+
+// WatchSessionRemoved watches for the SessionRemoved signal of the D-Bus interface. The arguments of each signal are sent on the returned channel until the context is canceled.
+func (c *Login1Manager) WatchSessionRemoved(ctx context.Context) (<-chan Login1ManagerSessionRemovedSignal, error) {
+	return dbusx.WatchSignal[Login1ManagerSessionRemovedSignal](ctx, c.bus, c.path, Login1ManagerInterface, "SessionRemoved")
+}
+
+// Login1SessionClientInterface is the name of the org.freedesktop.login1.Session D-Bus interface.
+const Login1SessionClientInterface = "org.freedesktop.login1.Session"
+
+// Login1SessionClient is a client for the org.freedesktop.login1.Session D-Bus interface.
+type Login1SessionClient struct {
+	bus  *dbusx.Bus
+	dest string
+	path string
+}
+
+// NewLogin1SessionClient creates a client for the org.freedesktop.login1.Session D-Bus interface of the object at the given path of the given destination.
+func NewLogin1SessionClient(bus *dbusx.Bus, dest, path string) *Login1SessionClient {
+	return &Login1SessionClient{bus: bus, dest: dest, path: path}
+}
+
+// Name returns the value of the Name property of the D-Bus interface.
+func (c *Login1SessionClient) Name() (string, error) {
+	return dbusx.NewProperty[string](c.bus, c.path, c.dest, Login1SessionClientInterface+".Name").Get()
+}
+
+// Remote returns the value of the Remote property of the D-Bus interface.
+func (c *Login1SessionClient) Remote() (bool, error) {
+	return dbusx.NewProperty[bool](c.bus, c.path, c.dest, Login1SessionClientInterface+".Remote").Get()
+}
+
+// RemoteHost returns the value of the RemoteHost property of the D-Bus interface.
+func (c *Login1SessionClient) RemoteHost() (string, error) {
+	return dbusx.NewProperty[string](c.bus, c.path, c.dest, Login1SessionClientInterface+".RemoteHost").Get()
+}
+
+// RemoteUser returns the value of the RemoteUser property of the D-Bus interface.
+func (c *Login1SessionClient) RemoteUser() (string, error) {
+	return dbusx.NewProperty[string](c.bus, c.path, c.dest, Login1SessionClientInterface+".RemoteUser").Get()
+}
+
+// Desktop returns the value of the Desktop property of the D-Bus interface.
+func (c *Login1SessionClient) Desktop() (string, error) {
+	return dbusx.NewProperty[string](c.bus, c.path, c.dest, Login1SessionClientInterface+".Desktop").Get()
+}
+
+// Service returns the value of the Service property of the D-Bus interface.
+func (c *Login1SessionClient) Service() (string, error) {
+	return dbusx.NewProperty[string](c.bus, c.path, c.dest, Login1SessionClientInterface+".Service").Get()
+}
+
+// Type returns the value of the Type property of the D-Bus interface.
+func (c *Login1SessionClient) Type() (string, error) {
+	return dbusx.NewProperty[string](c.bus, c.path, c.dest, Login1SessionClientInterface+".Type").Get()
+}
+
+// Login1Session is the D-Bus struct type (susso).
+type Login1Session struct {
+	ID   string
+	UID  uint32
+	User string
+	Seat string
+	Path dbus.ObjectPath
+}
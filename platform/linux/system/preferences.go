@@ -42,3 +42,53 @@ type UptimePrefs struct {
 type UserSessionsPrefs struct {
 	workers.CommonWorkerPrefs `toml:",squash"`
 }
+
+// DBusCommandPrefs are the preferences for the D-Bus commands worker. If any
+// destinations or interfaces are listed, only D-Bus commands for those
+// destinations and interfaces are run. Properties can only be retrieved or set
+// when some are listed. Entries can contain wildcards (e.g.,
+// "org.freedesktop.*").
+type DBusCommandPrefs struct {
+	workers.CommonWorkerPrefs `toml:",squash"`
+
+	AllowedDestinations []string `toml:"allowed_destinations"`
+	AllowedInterfaces   []string `toml:"allowed_interfaces"`
+}
+
+// DBusSensorsPrefs are the preferences for the D-Bus sensors worker.
+type DBusSensorsPrefs struct {
+	workers.CommonWorkerPrefs `toml:",squash"`
+
+	Sensors []DBusSensor `toml:"sensors"`
+}
+
+// DBusSensor describes a sensor whose state is a D-Bus property, or an argument
+// of a D-Bus signal.
+type DBusSensor struct {
+	// Name is the name of the sensor.
+	Name string `toml:"name" validate:"required"`
+	// ID is the ID of the sensor. If not set, it is derived from the name.
+	ID string `toml:"id,omitempty"`
+	// Bus is the bus to use, either "session" or "system".
+	Bus string `toml:"bus" validate:"required,oneof=session system"`
+	// Destination is the bus name of the service (e.g., org.freedesktop.UPower).
+	Destination string `toml:"destination" validate:"required"`
+	// Path is the object path. It is not needed if UseSessionPath is set.
+	Path string `toml:"path,omitempty" validate:"required_without=UseSessionPath"`
+	// UseSessionPath uses the path of the user's login session as the object
+	// path.
+	UseSessionPath bool `toml:"use_session_path,omitempty"`
+	// Interface is the interface of the property or signal.
+	Interface string `toml:"interface" validate:"required"`
+	// Property is the name of the property whose value is the sensor state.
+	Property string `toml:"property,omitempty" validate:"required_without=Signal,excluded_with=Signal"`
+	// Signal is the name of a signal with an argument whose value is the sensor
+	// state.
+	Signal string `toml:"signal,omitempty" validate:"required_without=Property"`
+	// Arg is the index of the signal argument whose value is the sensor state.
+	Arg         int    `toml:"arg,omitempty" validate:"gte=0"`
+	Icon        string `toml:"icon,omitempty"`
+	Units       string `toml:"units,omitempty"`
+	DeviceClass string `toml:"device_class,omitempty"`
+	StateClass  string `toml:"state_class,omitempty"`
+}
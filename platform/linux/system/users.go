@@ -3,6 +3,7 @@
 // This software is released under the MIT License.
 // https://opensource.org/licenses/MIT
 
+//go:generate go run github.com/joshuar/go-hass-agent/pkg/linux/dbusx/cmd/dbusxgen -output login1.gen.go login1.xml
 //revive:disable:unused-receiver
 package system
 
@@ -10,11 +11,8 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-	"slices"
-	"strings"
 	"sync"
 
-	"github.com/godbus/dbus/v5"
 	slogctx "github.com/veqryn/slog-context"
 
 	"github.com/joshuar/go-hass-agent/agent/workers"
@@ -24,12 +22,8 @@ import (
 )
 
 const (
-	loginBasePath        = "/org/freedesktop/login1"
-	loginBaseInterface   = "org.freedesktop.login1"
-	managerInterface     = loginBaseInterface + ".Manager"
-	sessionAddedSignal   = "SessionNew"
-	sessionRemovedSignal = "SessionRemoved"
-	listSessionsMethod   = managerInterface + ".ListSessions"
+	loginBasePath      = "/org/freedesktop/login1"
+	loginBaseInterface = "org.freedesktop.login1"
 
 	usersSensorUnits = "users"
 	usersSensorIcon  = "mdi:account"
@@ -48,8 +42,8 @@ var (
 type UserSessionSensorWorker struct {
 	*models.WorkerMetadata
 
-	bus   *dbusx.Bus
-	prefs *UserSessionsPrefs
+	manager *Login1Manager
+	prefs   *UserSessionsPrefs
 }
 
 func NewUserSessionSensorWorker(ctx context.Context) (workers.EntityWorker, error) {
@@ -57,13 +51,13 @@ func NewUserSessionSensorWorker(ctx context.Context) (workers.EntityWorker, erro
 		WorkerMetadata: models.SetWorkerMetadata("user_sessions", "User sessions"),
 	}
 
-	var ok bool
-
-	worker.bus, ok = linux.CtxGetSystemBus(ctx)
+	bus, ok := linux.CtxGetSystemBus(ctx)
 	if !ok {
 		return worker, fmt.Errorf("get system bus: %w", linux.ErrNoSystemBus)
 	}
 
+	worker.manager = NewLogin1Manager(bus, loginBaseInterface, loginBasePath)
+
 	defaultPrefs := &UserSessionsPrefs{}
 	var err error
 	worker.prefs, err = workers.LoadWorkerPreferences(userSessionsPreferencesID, defaultPrefs)
@@ -75,18 +69,20 @@ func NewUserSessionSensorWorker(ctx context.Context) (workers.EntityWorker, erro
 }
 
 func (w *UserSessionSensorWorker) Start(ctx context.Context) (<-chan models.Entity, error) {
-	triggerCh, err := dbusx.NewWatch(
-		dbusx.MatchPath(loginBasePath),
-		dbusx.MatchInterface(managerInterface),
-		dbusx.MatchMembers(sessionAddedSignal, sessionRemovedSignal),
-	).Start(ctx, w.bus)
+	sessionNewCh, err := w.manager.WatchSessionNew(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("watch user sessions: %w", err)
+	}
+
+	sessionRemovedCh, err := w.manager.WatchSessionRemoved(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("watch user sessions: %w", err)
 	}
+
 	sensorCh := make(chan models.Entity)
 
 	sendUpdate := func() {
-		if users, err := w.getUsers(); err != nil {
+		if users, err := w.getUsers(ctx); err != nil {
 			slogctx.FromCtx(ctx).Debug("Failed to get list of user sessions.", slog.Any("error", err))
 		} else {
 			sensorCh <- newUsersSensor(ctx, users)
@@ -100,7 +96,17 @@ func (w *UserSessionSensorWorker) Start(ctx context.Context) (<-chan models.Enti
 			select {
 			case <-ctx.Done():
 				return
-			case <-triggerCh:
+			case _, ok := <-sessionNewCh:
+				if !ok {
+					return
+				}
+
+				go sendUpdate()
+			case _, ok := <-sessionRemovedCh:
+				if !ok {
+					return
+				}
+
 				go sendUpdate()
 			}
 		}
@@ -116,18 +122,15 @@ func (w *UserSessionSensorWorker) IsDisabled() bool {
 	return w.prefs.IsDisabled()
 }
 
-func (w *UserSessionSensorWorker) getUsers() ([]string, error) {
-	userData, err := dbusx.GetData[[][]any](w.bus, loginBasePath, loginBaseInterface, listSessionsMethod)
+func (w *UserSessionSensorWorker) getUsers(ctx context.Context) ([]string, error) {
+	sessions, err := w.manager.ListSessions(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("get users from D-Bus: %w", err)
 	}
 
-	var users []string
-
-	for _, u := range userData {
-		if user, ok := u[2].(string); ok {
-			users = append(users, user)
-		}
+	users := make([]string, 0, len(sessions))
+	for _, session := range sessions {
+		users = append(users, session.User)
 	}
 
 	return users, nil
@@ -165,6 +168,7 @@ func NewUserSessionEventsWorker(ctx context.Context) (workers.EntityWorker, erro
 
 	worker.sessionTracker = &sessionTracker{
 		bus:      bus,
+		manager:  NewLogin1Manager(bus, loginBaseInterface, loginBasePath),
 		sessions: make(map[string]map[string]any),
 	}
 
@@ -175,15 +179,13 @@ func NewUserSessionEventsWorker(ctx context.Context) (workers.EntityWorker, erro
 		return worker, fmt.Errorf("load preferences: %w", err)
 	}
 
-	currentSessions, err := dbusx.GetData[[][]any](bus, loginBasePath, loginBaseInterface, listSessionsMethod)
+	currentSessions, err := worker.manager.ListSessions(ctx)
 	if err != nil {
 		return worker, fmt.Errorf("get user sessions from D-Bus: %w", err)
 	}
 
-	for session := range slices.Values(currentSessions) {
-		if s, valid := session[4].(string); valid {
-			worker.trackSession(s)
-		}
+	for _, session := range currentSessions {
+		worker.trackSession(string(session.Path))
 	}
 
 	return worker, nil
@@ -191,6 +193,7 @@ func NewUserSessionEventsWorker(ctx context.Context) (workers.EntityWorker, erro
 
 type sessionTracker struct {
 	bus      *dbusx.Bus
+	manager  *Login1Manager
 	sessions map[string]map[string]any
 	mu       sync.Mutex
 }
@@ -207,44 +210,28 @@ func (t *sessionTracker) unTrackSession(path string) {
 	delete(t.sessions, path)
 }
 
-func (t *sessionTracker) getSessionProp(path, prop string) (dbus.Variant, error) {
-	var value dbus.Variant
-	value, err := dbusx.NewProperty[dbus.Variant](t.bus,
-		path,
-		loginBaseInterface,
-		loginBaseInterface+".Session."+prop).Get()
-	if err != nil {
-		return dbus.MakeVariant("Unknown"),
-			fmt.Errorf("could not retrieve session property %s (session %s): %w", prop, path, err)
-	}
-
-	return value, nil
-}
-
 func (t *sessionTracker) getSessionDetails(path string) map[string]any {
+	session := NewLogin1SessionClient(t.bus, loginBaseInterface, path)
 	sessionDetails := make(map[string]any)
 
-	sessionDetails["user"] = sessionProp[string](t.getSessionProp, path, "Name")
-	sessionDetails["remote"] = sessionProp[bool](t.getSessionProp, path, "Remote")
-
-	if _, ok := sessionDetails["remote"].(bool); ok {
-		sessionDetails["remote_host"] = sessionProp[string](t.getSessionProp, path, "RemoteHost")
-		sessionDetails["remote_user"] = sessionProp[string](t.getSessionProp, path, "RemoteUser")
-	}
-
-	sessionDetails["desktop"] = sessionProp[string](t.getSessionProp, path, "Desktop")
-	sessionDetails["service"] = sessionProp[string](t.getSessionProp, path, "Service")
-	sessionDetails["type"] = sessionProp[string](t.getSessionProp, path, "Type")
+	sessionDetails["user"] = sessionProp(session.Name)
+	sessionDetails["remote"] = sessionProp(session.Remote)
+	sessionDetails["remote_host"] = sessionProp(session.RemoteHost)
+	sessionDetails["remote_user"] = sessionProp(session.RemoteUser)
+	sessionDetails["desktop"] = sessionProp(session.Desktop)
+	sessionDetails["service"] = sessionProp(session.Service)
+	sessionDetails["type"] = sessionProp(session.Type)
 
 	return sessionDetails
 }
 
 func (w *UserSessionEventsWorker) Start(ctx context.Context) (<-chan models.Entity, error) {
-	triggerCh, err := dbusx.NewWatch(
-		dbusx.MatchPath(loginBasePath),
-		dbusx.MatchInterface(managerInterface),
-		dbusx.MatchMembers(sessionAddedSignal, sessionRemovedSignal),
-	).Start(ctx, w.bus)
+	sessionNewCh, err := w.manager.WatchSessionNew(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("watch user sessions: %w", err)
+	}
+
+	sessionRemovedCh, err := w.manager.WatchSessionRemoved(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("watch user sessions: %w", err)
 	}
@@ -258,36 +245,34 @@ func (w *UserSessionEventsWorker) Start(ctx context.Context) (<-chan models.Enti
 			select {
 			case <-ctx.Done():
 				return
-			case trigger := <-triggerCh:
-				if len(trigger.Content) != 2 { //nolint:mnd // valid trigger content will be 2.
-					continue
+			case session, ok := <-sessionNewCh:
+				if !ok {
+					return
 				}
-				// If the trigger does not contain a session path, ignore.
-				path, ok := trigger.Content[1].(dbus.ObjectPath)
+
+				path := string(session.ObjectPath)
+				// Add the session to the tracker.
+				w.trackSession(path)
+				// Send the session added event.
+				if entity, err := models.NewEvent(sessionStartedEventName, w.sessions[path]); err != nil {
+					slogctx.FromCtx(ctx).Warn("Could not generate users event.", slog.Any("error", err))
+				} else {
+					eventCh <- entity
+				}
+			case session, ok := <-sessionRemovedCh:
 				if !ok {
-					continue
+					return
 				}
-				// Send the appropriate event type.
-				switch {
-				case strings.Contains(trigger.Signal, sessionAddedSignal):
-					// Add the session to the tracker.
-					w.trackSession(string(path))
-					// Send the session added event.
-					if entity, err := models.NewEvent(sessionStartedEventName, w.sessions[string(path)]); err != nil {
-						slogctx.FromCtx(ctx).Warn("Could not generate users event.", slog.Any("error", err))
-					} else {
-						eventCh <- entity
-					}
-				case strings.Contains(trigger.Signal, sessionRemovedSignal):
-					// Send the session removed event.
-					if entity, err := models.NewEvent(sessionStoppedEventName, w.sessions[string(path)]); err != nil {
-						slogctx.FromCtx(ctx).Warn("Could not generate users event.", slog.Any("error", err))
-					} else {
-						eventCh <- entity
-					}
-					// Remove the session from the tracker.
-					w.unTrackSession(string(path))
+
+				path := string(session.ObjectPath)
+				// Send the session removed event.
+				if entity, err := models.NewEvent(sessionStoppedEventName, w.sessions[path]); err != nil {
+					slogctx.FromCtx(ctx).Warn("Could not generate users event.", slog.Any("error", err))
+				} else {
+					eventCh <- entity
 				}
+				// Remove the session from the tracker.
+				w.unTrackSession(path)
 			}
 		}
 	}()
@@ -299,18 +284,10 @@ func (w *UserSessionEventsWorker) IsDisabled() bool {
 	return w.prefs.IsDisabled()
 }
 
-func sessionProp[T any](getFunc func(string, string) (dbus.Variant, error), path, prop string) T {
-	var (
-		err     error
-		value   T
-		variant dbus.Variant
-	)
-
-	if variant, err = getFunc(path, prop); err != nil {
-		return value
-	}
-
-	value, _ = dbusx.VariantToValue[T](variant)
+// sessionProp returns the value of a session property, or the default value of
+// its type if it cannot be retrieved.
+func sessionProp[T any](get func() (T, error)) T {
+	value, _ := get() //nolint:errcheck // default value on error.
 
 	return value
 }
@@ -0,0 +1,65 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+// ErrParseSchedule is returned when a schedule string cannot be parsed.
+var ErrParseSchedule = errors.New("could not parse schedule")
+
+// ParseSchedule parses a cron schedule string and returns the equivalent quartz
+// Trigger.
+//
+// Cron schedule parsing code adapted from
+// https://github.com/robfig/cron/blob/master/parser.go
+func ParseSchedule(sched string) (quartz.Trigger, error) {
+	var (
+		trigger quartz.Trigger
+		err     error
+	)
+
+	// Attempt to parse as a standard cron schedule string.
+	trigger, err = quartz.NewCronTrigger(sched)
+	if err == nil {
+		return trigger, nil
+	}
+
+	// Attempt to parse as one of the year/month/week/day/hour strings.
+	switch sched {
+	case "@yearly", "@annually":
+		trigger, err = quartz.NewCronTrigger("0 0 0 1 1 * *")
+	case "@monthly":
+		trigger, err = quartz.NewCronTrigger("0 0 0 1 * *")
+	case "@weekly":
+		trigger, err = quartz.NewCronTrigger("0 0 0 * * 1")
+	case "@daily", "@midnight":
+		trigger, err = quartz.NewCronTrigger("0 0 0 * * *")
+	case "@hourly":
+		trigger, err = quartz.NewCronTrigger("0 0 * * * *")
+	}
+	// If successfully parsed, return the trigger.
+	if err == nil {
+		return trigger, nil
+	}
+
+	// Else, attempt to parse as an "@every ..." string.
+	const every = "@every "
+	if strings.HasPrefix(sched, every) {
+		duration, err := time.ParseDuration(sched[len(every):])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrParseSchedule, err)
+		}
+
+		return quartz.NewSimpleTrigger(duration), nil
+	}
+
+	return nil, fmt.Errorf("%w: unknown schedule format %s", ErrParseSchedule, sched)
+}
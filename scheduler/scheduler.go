@@ -9,6 +9,8 @@ import (
 	"fmt"
 	"log/slog"
 	"math/rand/v2"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/reugn/go-quartz/quartz"
@@ -20,12 +22,18 @@ const (
 )
 
 var (
-	ErrRunFailed      = errors.New("failed to run scheduler")
-	ErrScheduleFailed = errors.New("failed to schedule job")
+	ErrRunFailed        = errors.New("failed to run scheduler")
+	ErrScheduleFailed   = errors.New("failed to schedule job")
+	ErrUnscheduleFailed = errors.New("failed to unschedule job")
+	ErrNotStarted       = errors.New("scheduler not started")
+	ErrJobNotFound      = errors.New("job not found")
+	ErrJobRunning       = errors.New("job is already running")
 )
 
 type manager struct {
 	quartz.Scheduler
+	// ctx is the context passed to jobs when they are run.
+	ctx context.Context //nolint:containedctx
 }
 
 var mgr manager
@@ -44,6 +52,7 @@ func Start(ctx context.Context) error {
 
 	mgr = manager{
 		Scheduler: scheduler,
+		ctx:       ctx,
 	}
 
 	// Run goroutine to log misfired jobs.
@@ -74,7 +83,7 @@ func Start(ctx context.Context) error {
 
 func ScheduleJob(id string, job quartz.Job, trigger quartz.Trigger) error {
 	// Generate the job details.
-	jobDetail := quartz.NewJobDetail(job, quartz.NewJobKey(id))
+	jobDetail := quartz.NewJobDetail(&serialJob{Job: job}, quartz.NewJobKey(id))
 	// Schedule the job.
 	if err := mgr.ScheduleJob(jobDetail, trigger); err != nil {
 		return errors.Join(ErrScheduleFailed, err)
@@ -82,10 +91,90 @@ func ScheduleJob(id string, job quartz.Job, trigger quartz.Trigger) error {
 	return nil
 }
 
+// UnscheduleJob removes the job with the given id from the scheduler.
+func UnscheduleJob(id string) error {
+	if err := mgr.DeleteJob(quartz.NewJobKey(id)); err != nil {
+		return errors.Join(ErrUnscheduleFailed, err)
+	}
+	return nil
+}
+
 func IsStarted() bool {
 	return mgr.IsStarted()
 }
 
+// JobIDs returns the (sorted) ids of all scheduled jobs.
+func JobIDs() ([]string, error) {
+	if mgr.Scheduler == nil {
+		return nil, ErrNotStarted
+	}
+
+	keys, err := mgr.GetJobKeys()
+	if err != nil {
+		return nil, fmt.Errorf("get job keys: %w", err)
+	}
+
+	ids := make([]string, 0, len(keys))
+	for _, key := range keys {
+		ids = append(ids, key.Name())
+	}
+
+	slices.Sort(ids)
+
+	return ids, nil
+}
+
+// RunJob runs the scheduled job with the given id now, outside of its schedule. The job runs in the background, with
+// the same context as its scheduled runs, and any error is logged. If there is no job with the given id,
+// ErrJobNotFound is returned. If the job is already running, ErrJobRunning is returned.
+func RunJob(id string) error {
+	if mgr.Scheduler == nil || !mgr.IsStarted() {
+		return ErrNotStarted
+	}
+
+	scheduled, err := mgr.GetScheduledJob(quartz.NewJobKey(id))
+	if err != nil {
+		return errors.Join(ErrJobNotFound, err)
+	}
+
+	job, ok := scheduled.JobDetail().Job().(*serialJob)
+	if !ok {
+		return fmt.Errorf("%w: %s: unknown job type", ErrJobNotFound, id)
+	}
+
+	if !job.mu.TryLock() {
+		return fmt.Errorf("%w: %s", ErrJobRunning, id)
+	}
+
+	go func() {
+		defer job.mu.Unlock()
+
+		if err := job.Job.Execute(mgr.ctx); err != nil {
+			slogctx.FromCtx(mgr.ctx).Warn("Could not run job.",
+				slog.String("job_id", id),
+				slog.Any("error", err))
+		}
+	}()
+
+	return nil
+}
+
+// serialJob wraps a scheduled job so that only one run of the job executes at a time, whether it was run by the
+// scheduler or by RunJob.
+type serialJob struct {
+	quartz.Job
+
+	mu sync.Mutex
+}
+
+// Execute runs the job once any other run of the job has finished.
+func (j *serialJob) Execute(ctx context.Context) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.Job.Execute(ctx) //nolint:wrapcheck
+}
+
 // PollTriggerWithJitter implements the quartz.Trigger interface; uses a fixed
 // interval with an amount of jitter.
 type PollTriggerWithJitter struct {
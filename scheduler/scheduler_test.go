@@ -0,0 +1,100 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ctxKey struct{}
+
+// blockingJob is a job that runs until it is released, recording the runs and
+// the most runs executing at once.
+type blockingJob struct {
+	release chan struct{}
+	started chan context.Context
+	running atomic.Int32
+	maxRuns atomic.Int32
+}
+
+func (j *blockingJob) Execute(ctx context.Context) error {
+	running := j.running.Add(1)
+	defer j.running.Add(-1)
+
+	if running > j.maxRuns.Load() {
+		j.maxRuns.Store(running)
+	}
+
+	j.started <- ctx
+
+	<-j.release
+
+	return nil
+}
+
+func (j *blockingJob) Description() string {
+	return "blocking job"
+}
+
+func TestRunJob(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.WithValue(t.Context(), ctxKey{}, "scheduler"))
+	defer cancel()
+
+	require.NoError(t, Start(ctx))
+
+	job := &blockingJob{
+		release: make(chan struct{}),
+		started: make(chan context.Context, 2),
+	}
+	// The job is scheduled far enough in the future that it is only run by
+	// the test.
+	require.NoError(t, ScheduleJob("blocking", job, quartz.NewSimpleTrigger(time.Hour)))
+
+	// Unknown jobs are not found.
+	require.ErrorIs(t, RunJob("unknown"), ErrJobNotFound)
+
+	// The job is run with the context of the scheduler.
+	require.NoError(t, RunJob("blocking"))
+
+	select {
+	case runCtx := <-job.started:
+		assert.Equal(t, "scheduler", runCtx.Value(ctxKey{}))
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timed out waiting for job to run")
+	}
+
+	// The job cannot be run again while it is running.
+	require.ErrorIs(t, RunJob("blocking"), ErrJobRunning)
+
+	// A run by the scheduler waits for the manual run to finish.
+	scheduled, err := mgr.GetScheduledJob(quartz.NewJobKey("blocking"))
+	require.NoError(t, err)
+
+	go scheduled.JobDetail().Job().Execute(ctx) //nolint:errcheck
+
+	select {
+	case <-job.started:
+		require.FailNow(t, "scheduled run started while the job was running")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	job.release <- struct{}{}
+
+	select {
+	case <-job.started:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timed out waiting for scheduled run")
+	}
+
+	job.release <- struct{}{}
+
+	assert.Equal(t, int32(1), job.maxRuns.Load())
+}
@@ -18,6 +18,8 @@ type Config struct {
 	ReadTimeout  time.Duration `toml:"read_timeout"`
 	WriteTimeout time.Duration `toml:"write_timeout"`
 	IdleTimeout  time.Duration `toml:"idle_timeout"`
+	// Metrics enables the Prometheus/OpenMetrics /metrics endpoint.
+	Metrics bool `toml:"metrics"`
 }
 
 // NewConfig creates a new default server config with sane values.
@@ -69,3 +71,12 @@ func WithKeyFile(file string) configOption {
 		}
 	}
 }
+
+// WithMetrics enables the Prometheus/OpenMetrics /metrics endpoint.
+func WithMetrics(enabled bool) configOption {
+	return func(c *Config) {
+		if enabled {
+			c.Metrics = true
+		}
+	}
+}
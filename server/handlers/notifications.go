@@ -0,0 +1,114 @@
+// Copyright 2026 Joshua Rich <joshua.rich@gmail.com>.
+// SPDX-License-Identifier: MIT
+
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/a-h/templ"
+	"github.com/go-chi/chi/v5"
+	"github.com/justinas/alice"
+
+	"github.com/joshuar/go-hass-agent/agent"
+	"github.com/joshuar/go-hass-agent/config"
+	"github.com/joshuar/go-hass-agent/hass"
+	"github.com/joshuar/go-hass-agent/hass/event"
+	"github.com/joshuar/go-hass-agent/hass/notifications"
+	"github.com/joshuar/go-hass-agent/models"
+	"github.com/joshuar/go-hass-agent/web/templates"
+)
+
+// ShowNotifications handles showing the notification history, optionally filtered by a search term.
+func ShowNotifications() http.HandlerFunc {
+	return alice.New(
+		routeLogger,
+	).ThenFunc(func(res http.ResponseWriter, req *http.Request) {
+		search := req.URL.Query().Get("q")
+		history, err := notifications.Load(config.GetPath())
+		if err != nil {
+			template := templ.Join(
+				templates.NotificationHistory(nil, search),
+				templates.Notification(models.NewErrorMessage("Error retrieving notifications.", err.Error())))
+			renderPage(template, "Notifications - Go Hass Agent").ServeHTTP(res, req)
+			return
+		}
+		renderPage(templates.NotificationHistory(history.Search(search), search), "Notifications - Go Hass Agent").
+			ServeHTTP(res, req)
+	}).ServeHTTP
+}
+
+// ChooseNotificationAction handles choosing an action for a notification in the notification history. The action is
+// sent to Home Assistant as an event and recorded in the history.
+func ChooseNotificationAction(agent *agent.Agent) http.HandlerFunc {
+	return alice.New(
+		routeLogger,
+	).ThenFunc(func(res http.ResponseWriter, req *http.Request) {
+		history, err := notifications.Load(config.GetPath())
+		if err != nil {
+			renderPartial(templates.Notification(
+				models.NewErrorMessage("Error retrieving notifications.", err.Error()))).ServeHTTP(res, req)
+			return
+		}
+		record, err := history.Get(chi.URLParam(req, "id"))
+		if err != nil {
+			renderPartial(templates.Notification(
+				models.NewErrorMessage("Unknown notification.", err.Error()))).ServeHTTP(res, req)
+			return
+		}
+		action := req.FormValue("action")
+		if action == "" {
+			renderPartial(templ.Join(
+				templates.NotificationHistoryRow(record),
+				templates.Notification(models.NewErrorMessage("No action chosen.", "")))).ServeHTTP(res, req)
+			return
+		}
+		// Only send actions that were offered by the notification.
+		if !record.HasAction(action) {
+			renderPartial(templ.Join(
+				templates.NotificationHistoryRow(record),
+				templates.Notification(models.NewErrorMessage("Unknown action.", action)))).ServeHTTP(res, req)
+			return
+		}
+		// Send the action to the server that sent the notification.
+		profile, err := hass.GetProfile(record.Profile)
+		if err != nil {
+			renderPartial(templ.Join(
+				templates.NotificationHistoryRow(record),
+				templates.Notification(models.NewErrorMessage("Could not send action.", err.Error())))).ServeHTTP(res, req)
+			return
+		}
+		hassclient, err := hass.NewProfileClient(req.Context(), agent, profile)
+		if err != nil {
+			renderPartial(templ.Join(
+				templates.NotificationHistoryRow(record),
+				templates.Notification(models.NewErrorMessage("Could not send action.", err.Error())))).ServeHTTP(res, req)
+			return
+		}
+		err = event.Handler(req.Context(), hassclient, models.Event{
+			Type: notifications.ActionEventType,
+			Data: map[string]any{
+				"action":  action,
+				"title":   record.Title,
+				"message": record.Message,
+			},
+		})
+		if err != nil {
+			renderPartial(templ.Join(
+				templates.NotificationHistoryRow(record),
+				templates.Notification(models.NewErrorMessage("Could not send action.", err.Error())))).ServeHTTP(res, req)
+			return
+		}
+		if err := history.SetAction(record.ID, action); err != nil {
+			renderPartial(templ.Join(
+				templates.NotificationHistoryRow(record),
+				templates.Notification(models.NewWarningMessage("Action sent but not recorded.", err.Error())))).
+				ServeHTTP(res, req)
+			return
+		}
+		record.Action = action
+		renderPartial(templ.Join(
+			templates.NotificationHistoryRow(record),
+			templates.Notification(models.NewSuccessMessage("Action sent.", "")))).ServeHTTP(res, req)
+	}).ServeHTTP
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/joshuar/go-hass-agent/agent"
 	"github.com/joshuar/go-hass-agent/config"
+	"github.com/joshuar/go-hass-agent/metrics"
 	"github.com/joshuar/go-hass-agent/server/handlers"
 	"github.com/joshuar/go-hass-agent/server/middlewares"
 	"github.com/joshuar/go-hass-agent/validation"
@@ -101,6 +102,14 @@ func New(ctx context.Context, static embed.FS, agent *agent.Agent, options ...co
 	// Preferences.
 	router.Get("/preferences", handlers.ShowPreferences())
 	router.With(middlewares.RequireHTMX).Post("/preferences/mqtt", handlers.SaveMQTTPreferences())
+	// Notification history.
+	router.Get("/notifications", handlers.ShowNotifications())
+	router.With(middlewares.RequireHTMX).Post("/notifications/{id}/action", handlers.ChooseNotificationAction(agent))
+	// Metrics.
+	if server.Config.Metrics {
+		metrics.Enable()
+		router.Get("/metrics", metrics.Handler().ServeHTTP)
+	}
 
 	// Set up server object.
 	h2s := &http2.Server{}
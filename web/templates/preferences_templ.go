@@ -55,7 +55,7 @@ func PreferencesForm(prefs *Preferences) templ.Component {
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, " name=\"mqtt.mqtt_enabled\" aria-describedby=\"mqtt-enabled-description\" class=\"col-start-1 row-start-1 checkbox\"></div></div><div class=\"text-sm/6\"><label for=\"mqtt_enabled\" class=\"font-medium\">Enabled</label><p id=\"mqtt-enabled-description\" class=\"text-base-content/80\">Toggle MQTT functionality (requires agent restart to take effect).</p></div></div><div class=\"sm:col-span-4\"><label for=\"mqtt_server\" class=\"block text-sm/6 font-medium\">Server</label><div class=\"mt-2\"><input id=\"mqtt_server\" type=\"text\" name=\"mqtt.mqtt_server\" placeholder=\"tcp://somehost:1883\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, " name=\"mqtt.mqtt_enabled\" aria-describedby=\"mqtt-enabled-description\" class=\"col-start-1 row-start-1 checkbox\"></div></div><div class=\"text-sm/6\"><label for=\"mqtt_enabled\" class=\"font-medium\">Enabled</label><p id=\"mqtt-enabled-description\" class=\"text-base-content/80\">Toggle MQTT functionality (requires agent restart to take effect).</p></div></div><div class=\"sm:col-span-4\"><label for=\"mqtt_server\" class=\"block text-sm/6 font-medium\">Server</label><div class=\"mt-2\"><input id=\"mqtt_server\" type=\"text\" name=\"mqtt.mqtt_server\" placeholder=\"tcp://somehost:1883\" aria-describedby=\"mqtt-server-description\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -67,7 +67,7 @@ func PreferencesForm(prefs *Preferences) templ.Component {
 			var templ_7745c5c3_Var2 string
 			templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.ResolveAttributeValue(prefs.MQTT.MQTTServer)
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/preferences.templ`, Line: 62, Col: 39}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/preferences.templ`, Line: 63, Col: 39}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var2)
 			if templ_7745c5c3_Err != nil {
@@ -78,7 +78,7 @@ func PreferencesForm(prefs *Preferences) templ.Component {
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, " class=\"block w-full input\"></div></div><div class=\"sm:col-span-3\"><label for=\"mqtt_username\" class=\"block text-sm/6 font-medium\">Username (optional)</label><div class=\"mt-2\"><input id=\"mqtt_username\" type=\"text\" name=\"mqtt.mqtt_user\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, " class=\"block w-full input\"></div><p id=\"mqtt-server-description\" class=\"mt-2 text-sm/6 text-base-content/80\">Use tcp:// for MQTT, ssl:// for MQTT over TLS or ws:// and wss:// for MQTT over WebSockets.</p></div><div class=\"sm:col-span-3\"><label for=\"mqtt_username\" class=\"block text-sm/6 font-medium\">Username (optional)</label><div class=\"mt-2\"><input id=\"mqtt_username\" type=\"text\" name=\"mqtt.mqtt_user\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -90,7 +90,7 @@ func PreferencesForm(prefs *Preferences) templ.Component {
 			var templ_7745c5c3_Var3 string
 			templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.ResolveAttributeValue(prefs.MQTT.MQTTUser)
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/preferences.templ`, Line: 76, Col: 37}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/preferences.templ`, Line: 78, Col: 37}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var3)
 			if templ_7745c5c3_Err != nil {
@@ -113,7 +113,7 @@ func PreferencesForm(prefs *Preferences) templ.Component {
 			var templ_7745c5c3_Var4 string
 			templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.ResolveAttributeValue(prefs.MQTT.MQTTPassword)
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/preferences.templ`, Line: 90, Col: 41}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/preferences.templ`, Line: 92, Col: 41}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var4)
 			if templ_7745c5c3_Err != nil {
@@ -136,7 +136,7 @@ func PreferencesForm(prefs *Preferences) templ.Component {
 			var templ_7745c5c3_Var5 string
 			templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.ResolveAttributeValue(prefs.MQTT.MQTTTopicPrefix)
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/preferences.templ`, Line: 104, Col: 44}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/preferences.templ`, Line: 106, Col: 44}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var5)
 			if templ_7745c5c3_Err != nil {
@@ -152,7 +152,86 @@ func PreferencesForm(prefs *Preferences) templ.Component {
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 16, " class=\"block w-full input\"></div></div></div></div></div><div class=\"mt-6 flex items-center justify-end gap-x-6\"><button type=\"submit\" class=\"btn btn-primary\">Save</button></div></form></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 16, " class=\"block w-full input\"></div></div><div class=\"sm:col-span-4\"><label for=\"mqtt_ca_cert\" class=\"block text-sm/6 font-medium\">CA Certificate (optional)</label><div class=\"mt-2\"><input id=\"mqtt_ca_cert\" type=\"text\" name=\"mqtt.mqtt_ca_cert\" placeholder=\"/path/to/ca.pem\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		if prefs.MQTT.MQTTCACert != "" {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 17, " value=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var6 string
+			templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.ResolveAttributeValue(prefs.MQTT.MQTTCACert)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/preferences.templ`, Line: 123, Col: 39}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var6)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 18, "\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 19, " class=\"block w-full input\"></div></div><div class=\"sm:col-span-3\"><label for=\"mqtt_client_cert\" class=\"block text-sm/6 font-medium\">Client Certificate (optional)</label><div class=\"mt-2\"><input id=\"mqtt_client_cert\" type=\"text\" name=\"mqtt.mqtt_client_cert\" placeholder=\"/path/to/client.pem\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		if prefs.MQTT.MQTTClientCert != "" {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 20, " value=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var7 string
+			templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.ResolveAttributeValue(prefs.MQTT.MQTTClientCert)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/preferences.templ`, Line: 138, Col: 43}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var7)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 21, "\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 22, " class=\"block w-full input\"></div></div><div class=\"sm:col-span-3\"><label for=\"mqtt_client_key\" class=\"block text-sm/6 font-medium\">Client Key (optional)</label><div class=\"mt-2\"><input id=\"mqtt_client_key\" type=\"text\" name=\"mqtt.mqtt_client_key\" placeholder=\"/path/to/client.key\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		if prefs.MQTT.MQTTClientKey != "" {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 23, " value=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var8 string
+			templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.ResolveAttributeValue(prefs.MQTT.MQTTClientKey)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/preferences.templ`, Line: 153, Col: 42}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var8)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 24, "\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 25, " class=\"block w-full input\"></div></div><div class=\"sm:col-span-4 flex gap-3\"><div class=\"flex h-6 shrink-0 items-center\"><div class=\"group grid size-4 grid-cols-1\"><input id=\"mqtt_insecure\" type=\"checkbox\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		if prefs.MQTT.MQTTInsecure {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 26, " checked=\"checked\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 27, " name=\"mqtt.mqtt_insecure\" aria-describedby=\"mqtt-insecure-description\" class=\"col-start-1 row-start-1 checkbox\"></div></div><div class=\"text-sm/6\"><label for=\"mqtt_insecure\" class=\"font-medium\">Skip Certificate Verification</label><p id=\"mqtt-insecure-description\" class=\"text-base-content/80\">Do not verify the certificate of the server (insecure, for testing only).</p></div></div></div></div></div><div class=\"mt-6 flex items-center justify-end gap-x-6\"><button type=\"submit\" class=\"btn btn-primary\">Save</button></div></form></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}